@@ -2,24 +2,99 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"flag"
+	"io"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
 
 	"clean-arquitecture-template/config"
 	app "clean-arquitecture-template/internal/app/example"
 	"clean-arquitecture-template/internal/inputports/example"
 	"clean-arquitecture-template/internal/inputports/example/http"
-	"clean-arquitecture-template/internal/interfaceadapters/example/storage/mongodb"
+	"clean-arquitecture-template/internal/inputports/example/jobs"
+	"clean-arquitecture-template/internal/interfaceadapters"
+	"clean-arquitecture-template/internal/interfaceadapters/example/identity"
+	"clean-arquitecture-template/internal/interfaceadapters/example/storage/cache"
+	"clean-arquitecture-template/internal/interfaceadapters/example/storage/resilience"
+)
+
+const (
+	shutdownTimeout time.Duration = 10 * time.Second
+
+	logConfigNode string = "apps.example.log"
+
+	cacheVarName string = "cache"
 )
 
+type logConfig struct {
+	Level string `json:"level"`
+}
+
+func applyLogConfig(r io.Reader) error {
+	cnf := logConfig{}
+	if err := json.NewDecoder(r).Decode(&cnf); err != nil {
+		return err
+	}
+
+	if cnf.Level == "" {
+		return nil
+	}
+
+	level, err := logrus.ParseLevel(cnf.Level)
+	if err != nil {
+		return err
+	}
+
+	logrus.SetLevel(level)
+
+	return nil
+}
+
 func main() {
 	ctx := context.Background()
 
-	cnf, err := config.New()
+	cnfFlags := config.Flags(flag.CommandLine)
+	flag.Parse()
+
+	cnf, err := config.New(cnfFlags)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logReader, err := cnf.Find(logConfigNode)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = applyLogConfig(logReader); err != nil {
+		log.Fatal(err)
+	}
+
+	logrus.WithField("config", cnf.String()).Debug("config loaded")
+
+	unwatchLog, err := cnf.Watch(logConfigNode, applyLogConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer unwatchLog()
+
+	storage, err := interfaceadapters.NewStorage(ctx, cnf)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	identityConf, err := identity.ReadConfig(cnf)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	mongoConfig, err := mongodb.ReadConfig(cnf)
+	idProv, err := identity.New(identityConf, storage.IDs)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -29,10 +104,60 @@ func main() {
 		log.Fatal(err)
 	}
 
-	repo := mongodb.NewExampleRepo(ctx, mongoConfig)
-	idProv := mongodb.NewIdentityProvider()
-	services := app.NewServices(repo, idProv)
-	rest := example.NewServices(ctx, services, restConf)
+	purgeConf, err := jobs.ReadConfig(cnf)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cacheConf, err := cache.ReadConfig(cnf)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	resilienceConf, err := resilience.ReadConfig(cnf, storage.Backend)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	rest.Server.ListenAndServe()
+	lines := resilience.NewRepository(storage.Lines, resilience.Policies(resilienceConf)...)
+	if cacheConf.Enabled() {
+		cached := cache.NewRepository(lines, cacheConf)
+		cached.Publish(cacheVarName)
+		lines = cached
+	}
+
+	services := app.NewServices(lines, idProv, storage.Lines, storage.Audit)
+	rest := example.NewServices(ctx, services, restConf, storage.Lines)
+
+	unwatchRest, err := cnf.Watch(http.ConfigNode, rest.Server.ApplyConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer unwatchRest()
+
+	unwatchStorage, err := cnf.Watch(storage.ConfigNode, storage.ApplyConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer unwatchStorage()
+
+	go rest.Server.ListenAndServe()
+
+	sigCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go jobs.NewPurger(services.ExampleService.Commands.PurgeExamplesHandler, purgeConf).Run(sigCtx)
+
+	<-sigCtx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, shutdownTimeout)
+	defer cancel()
+
+	if err := rest.Server.Shutdown(shutdownCtx); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := storage.Close(shutdownCtx); err != nil {
+		log.Fatal(err)
+	}
 }
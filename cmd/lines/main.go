@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"os/user"
+	"syscall"
+	"time"
+
+	"clean-arquitecture-template/config"
+	app "clean-arquitecture-template/internal/app/example"
+	"clean-arquitecture-template/internal/app/example/commands"
+	"clean-arquitecture-template/internal/app/example/queries"
+	"clean-arquitecture-template/internal/domain/example"
+	"clean-arquitecture-template/internal/inputports/example/jobs"
+	"clean-arquitecture-template/internal/inputports/example/ndjson"
+	"clean-arquitecture-template/internal/interfaceadapters"
+	"clean-arquitecture-template/internal/interfaceadapters/example/identity"
+	"clean-arquitecture-template/internal/interfaceadapters/example/storage/resilience"
+)
+
+const usage string = `usage: lines [-config file] <command> [flags]
+
+commands:
+  export  writes every line as NDJSON
+  import  writes the lines of an NDJSON stream
+  purge   removes for good the lines deleted long ago
+`
+
+const closeTimeout time.Duration = 10 * time.Second
+
+func main() {
+	cnfFlags := config.Flags(flag.CommandLine)
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cnf, err := config.New(cnfFlags)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	identityConf, err := identity.ReadConfig(cnf)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	purgeConf, err := jobs.ReadConfig(cnf)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ctx = example.WithActor(ctx, actor())
+
+	storage, err := interfaceadapters.NewStorage(ctx, cnf)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	idProv, err := identity.New(identityConf, storage.IDs)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	resilienceConf, err := resilience.ReadConfig(cnf, storage.Backend)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	lines := resilience.NewRepository(storage.Lines, resilience.Policies(resilienceConf)...)
+	services := app.NewServices(lines, idProv, storage.Lines, storage.Audit)
+
+	switch flag.Arg(0) {
+	case "export":
+		err = export(ctx, services, flag.Args()[1:])
+	case "import":
+		err = load(ctx, services, flag.Args()[1:])
+	case "purge":
+		err = purge(ctx, services, purgeConf, flag.Args()[1:])
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+
+	if closeErr := storage.Close(closeCtx); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func export(ctx context.Context, services app.Services, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	output := fs.String("o", "", "file to write, stdout when empty")
+	fs.Parse(args)
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	cursor, err := services.ExampleService.Queries.ExportExamplesHandler.Handle(ctx, queries.ExportExamplesRequest{})
+	if err != nil {
+		return err
+	}
+
+	written, err := ndjson.Export(ctx, w, cursor)
+	fmt.Fprintf(os.Stderr, "exported %d lines\n", written)
+
+	return err
+}
+
+func load(ctx context.Context, services app.Services, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	input := fs.String("i", "", "file to read, stdin when empty")
+	keepIDs := fs.Bool("keep-ids", false, "keep the identifiers of the records")
+	keepTimestamps := fs.Bool("keep-timestamps", false, "keep the creation times of the records")
+	workers := fs.Int("workers", commands.DefaultImportWorkers, "batches written at once")
+	batch := fs.Int("batch", commands.DefaultImportBatchSize, "lines per batch")
+	fs.Parse(args)
+
+	var r io.Reader = os.Stdin
+	if *input != "" {
+		f, err := os.Open(*input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		r = f
+	}
+
+	report, err := services.ExampleService.Commands.ImportExamplesHandler.Handle(ctx, commands.ImportExamplesRequest{
+		Source:         ndjson.NewSource(r),
+		KeepIDs:        *keepIDs,
+		KeepTimestamps: *keepTimestamps,
+		Workers:        *workers,
+		BatchSize:      *batch,
+		Progress: func(p commands.ImportProgress) {
+			fmt.Fprintf(os.Stderr, "\rread %d, written %d, failed %d", p.Read, p.Written, p.Failed)
+		},
+	})
+	fmt.Fprintf(os.Stderr, "\rread %d, written %d, failed %d\n", report.Read, report.Written, report.Failed)
+
+	for _, failure := range report.Failures {
+		fmt.Fprintf(os.Stderr, "line %d: %s\n", failure.Position, failure.Err)
+	}
+
+	return err
+}
+
+func purge(ctx context.Context, services app.Services, cnf jobs.Config, args []string) error {
+	fs := flag.NewFlagSet("purge", flag.ExitOnError)
+	retention := fs.Duration("retention", cnf.Retention(), "time the deleted lines are kept")
+	fs.Parse(args)
+
+	purged, err := services.ExampleService.Commands.PurgeExamplesHandler.Handle(ctx, commands.PurgeExamplesRequest{Retention: *retention})
+	fmt.Fprintf(os.Stderr, "purged %d lines\n", purged)
+
+	return err
+}
+
+// actor names the user running the command in the audit records of the
+// changes it makes.
+func actor() string {
+	if u, err := user.Current(); err == nil {
+		return "lines:" + u.Username
+	}
+
+	return "lines"
+}
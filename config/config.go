@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/spf13/viper"
@@ -20,10 +23,21 @@ const (
 	configFileName string = "config"
 	configFileType string = "yaml"
 
+	configFlagName  string = "config"
+	configFlagUsage string = "path to the config file"
+
+	envPrefix string = "EXAMPLE"
+
 	ErrReadConfigFile Error = "unable to read config file"
+	ErrInvalidConfig  Error = "invalid config"
+	ErrConfigNotRead  Error = "config haven't been read"
 )
 
-var onlyOnce sync.Once
+var (
+	onlyOnce    sync.Once
+	instance    *config
+	instanceErr error
+)
 
 type Error string
 
@@ -31,31 +45,106 @@ func (e Error) Error() string {
 	return string(e)
 }
 
-type config struct {
-	vp *viper.Viper
+type Option func(*config)
+
+// WithFile reads the config from path instead of searching the default
+// locations. An empty path keeps the default search.
+func WithFile(path string) Option {
+	return func(c *config) {
+		if path != "" {
+			c.file = path
+		}
+	}
+}
+
+// WithSchema replaces the schema the config is validated against.
+func WithSchema(s Schema) Option {
+	return func(c *config) {
+		c.schema = s
+	}
+}
+
+// WithSecretProvider registers p to resolve the values referencing its
+// scheme, replacing any provider registered for the same scheme.
+func WithSecretProvider(p SecretProvider) Option {
+	return func(c *config) {
+		c.secrets[p.Scheme()] = p
+	}
 }
 
-func New() (*config, error) {
-	var cnf *config
-	var err error
+// Flags registers the --config flag on fs. The returned option reads the flag
+// value when it is applied, so it must be passed to New after fs is parsed.
+func Flags(fs *flag.FlagSet) Option {
+	path := fs.String(configFlagName, "", configFlagUsage)
 
+	return func(c *config) {
+		WithFile(*path)(c)
+	}
+}
+
+type config struct {
+	mu      sync.RWMutex
+	vp      *viper.Viper
+	file    string
+	schema  Schema
+	secrets secretProviders
+
+	watchOnce     sync.Once
+	reloadMu      sync.Mutex
+	nextID        int
+	subscriptions map[int]*subscription
+}
+
+// New reads and validates the config only once, later calls return the same
+// config and ignore their options.
+func New(opts ...Option) (*config, error) {
 	onlyOnce.Do(func() {
-		cnf = new(config)
-		err = cnf.read()
+		instance, instanceErr = newConfig(opts...)
 	})
 
-	return cnf, err
+	return instance, instanceErr
+}
+
+func newConfig(opts ...Option) (*config, error) {
+	cnf := &config{
+		schema:        schema,
+		secrets:       defaultSecretProviders(),
+		subscriptions: make(map[int]*subscription),
+	}
+
+	for _, opt := range opts {
+		opt(cnf)
+	}
+
+	if err := cnf.read(); err != nil {
+		return nil, err
+	}
+
+	if err := cnf.validate(); err != nil {
+		return nil, err
+	}
+
+	return cnf, nil
 }
 
 func (c *config) read() error {
 	c.vp = viper.New()
 
-	c.vp.SetConfigName(configFileName)
 	c.vp.SetConfigType(configFileType)
-	c.vp.AddConfigPath(configPathETC)
-	c.vp.AddConfigPath(configPathLocalETC)
-	c.vp.AddConfigPath(configPathHome)
-	c.vp.AddConfigPath(configPathLocal)
+
+	if c.file != "" {
+		c.vp.SetConfigFile(c.file)
+	} else {
+		c.vp.SetConfigName(configFileName)
+		c.vp.AddConfigPath(configPathETC)
+		c.vp.AddConfigPath(configPathLocalETC)
+		c.vp.AddConfigPath(configPathHome)
+		c.vp.AddConfigPath(configPathLocal)
+	}
+
+	c.vp.SetEnvPrefix(envPrefix)
+	c.vp.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	c.vp.AutomaticEnv()
 
 	if err := c.vp.ReadInConfig(); err != nil {
 		return fmt.Errorf("%s: %w", err.Error(), ErrReadConfigFile)
@@ -64,20 +153,179 @@ func (c *config) read() error {
 	return nil
 }
 
+// validate reports every unknown key found in the file, every required key
+// that is not set and every value that does not match its kind.
+func (c *config) validate() error {
+	problems := make([]string, 0)
+
+	for _, key := range c.vp.AllKeys() {
+		if !c.schema.known(key) {
+			problems = append(problems, fmt.Sprintf("unknown key %q", key))
+		}
+	}
+
+	for _, k := range c.schema {
+		if !c.vp.IsSet(k.Path) {
+			if k.Required {
+				problems = append(problems, fmt.Sprintf("missing key %q", k.Path))
+			}
+
+			continue
+		}
+
+		if _, err := c.value(k, false); errors.Is(err, ErrSecretNotFound) {
+			problems = append(problems, fmt.Sprintf("unresolved secret for key %q", k.Path))
+		} else if err != nil {
+			problems = append(problems, fmt.Sprintf("invalid %s value for key %q", k.Kind, k.Path))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("%s: %w", strings.Join(problems, ", "), ErrInvalidConfig)
+	}
+
+	return nil
+}
+
 func (c *config) Find(node string) (io.Reader, error) {
 	if c == nil {
-		return nil, errors.New("config haven't been read")
+		return nil, ErrConfigNotRead
+	}
+
+	data, err := c.find(node)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(data), nil
+}
+
+func (c *config) find(node string) ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	d, err := c.node(strings.ToLower(node), false)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(d); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Dump returns the whole config with every secret redacted, so it can be
+// logged safely.
+func (c *config) Dump() (io.Reader, error) {
+	if c == nil {
+		return nil, ErrConfigNotRead
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	d, err := c.node("", true)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(d); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+func (c *config) String() string {
+	dump, err := c.Dump()
+	if err != nil {
+		return err.Error()
+	}
+
+	data, _ := io.ReadAll(dump)
+
+	return strings.TrimSpace(string(data))
+}
+
+// node builds the tree under node from the schema and file keys, so values
+// overridden through the environment are picked up even when the file does
+// not define them. An empty node builds the whole tree.
+func (c *config) node(node string, redact bool) (map[string]interface{}, error) {
+	keys := make(map[string]Key)
+	for _, key := range c.vp.AllKeys() {
+		keys[key] = Key{Path: key}
+	}
+	for _, k := range c.schema {
+		keys[k.Path] = k
+	}
+
+	prefix := ""
+	if node != "" {
+		prefix = node + "."
+	}
+
+	tree := make(map[string]interface{})
+
+	for path, k := range keys {
+		if !strings.HasPrefix(path, prefix) || !c.vp.IsSet(path) {
+			continue
+		}
+
+		value, err := c.value(k, redact)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", err.Error(), ErrInvalidConfig)
+		}
+
+		if value == nil {
+			continue
+		}
+
+		insert(tree, strings.Split(strings.TrimPrefix(path, prefix), "."), value)
 	}
 
-	n := c.vp.Get(node)
-	if d, is := n.(map[string]interface{}); is {
-		buf := new(bytes.Buffer)
-		if err := json.NewEncoder(buf).Encode(d); err != nil {
-			return nil, err
+	return tree, nil
+}
+
+// value resolves the secret references and casts the result to the kind of
+// the key.
+func (c *config) value(k Key, redact bool) (interface{}, error) {
+	raw := c.vp.Get(k.Path)
+
+	if s, is := raw.(string); is {
+		provider, ref, isRef := c.secrets.lookup(s)
+
+		if redact && (isRef || k.Secret) {
+			return redactedValue, nil
+		}
+
+		if isRef {
+			secret, err := provider.Resolve(ref)
+			if err != nil {
+				return nil, err
+			}
+
+			raw = secret
+		}
+	}
+
+	return k.Kind.cast(raw)
+}
+
+func insert(tree map[string]interface{}, path []string, value interface{}) {
+	for _, p := range path[:len(path)-1] {
+		child, is := tree[p].(map[string]interface{})
+		if !is {
+			child = make(map[string]interface{})
+			tree[p] = child
 		}
 
-		return buf, nil
+		tree = child
 	}
 
-	return nil, nil
+	tree[path[len(path)-1]] = value
 }
@@ -0,0 +1,225 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func Test_NewConfig(t *testing.T) {
+	testCases := []struct {
+		testName      string
+		content       string
+		env           map[string]string
+		expectedError error
+		expectedMsg   string
+	}{
+		{
+			testName:      "shipped-config-case",
+			content:       shippedConfig(t),
+			expectedError: nil,
+		},
+		{
+			testName: "unknown-key-case",
+			content: `
+apps:
+  example:
+    input-ports:
+      rest:
+        port: "8080"
+        prot: "8081"
+    interface-adapters:
+      storage:
+        mongodb:
+          dsn: "mongodb-dsn"
+          database: "example"
+          collection: "lines"
+`,
+			expectedError: ErrInvalidConfig,
+			expectedMsg:   `unknown key "apps.example.input-ports.rest.prot": invalid config`,
+		},
+		{
+			testName: "missing-keys-case",
+			content: `
+apps:
+  example:
+    input-ports:
+      rest:
+        address: "127.0.0.1"
+    interface-adapters:
+      storage:
+        mongodb:
+          url: "mongodb-dsn"
+`,
+			expectedError: ErrInvalidConfig,
+			expectedMsg: `missing key "apps.example.input-ports.rest.port", ` +
+				`missing key "apps.example.interface-adapters.storage.mongodb.collection", ` +
+				`missing key "apps.example.interface-adapters.storage.mongodb.database", ` +
+				`missing key "apps.example.interface-adapters.storage.mongodb.dsn", ` +
+				`unknown key "apps.example.interface-adapters.storage.mongodb.url": invalid config`,
+		},
+		{
+			testName: "missing-keys-from-env-case",
+			content: `
+apps:
+  example:
+    input-ports:
+      rest:
+        address: "127.0.0.1"
+`,
+			env: map[string]string{
+				"EXAMPLE_APPS_EXAMPLE_INPUT_PORTS_REST_PORT":                         "8080",
+				"EXAMPLE_APPS_EXAMPLE_INTERFACE_ADAPTERS_STORAGE_MONGODB_DSN":        "mongodb-dsn",
+				"EXAMPLE_APPS_EXAMPLE_INTERFACE_ADAPTERS_STORAGE_MONGODB_DATABASE":   "example",
+				"EXAMPLE_APPS_EXAMPLE_INTERFACE_ADAPTERS_STORAGE_MONGODB_COLLECTION": "lines",
+			},
+			expectedError: nil,
+		},
+	}
+
+	for _, c := range testCases {
+		testName := c.testName
+		content := c.content
+		env := c.env
+		expectedError := c.expectedError
+		expectedMsg := c.expectedMsg
+
+		t.Run(testName, func(t *testing.T) {
+			for k, v := range env {
+				t.Setenv(k, v)
+			}
+
+			cnf, err := newConfig(WithFile(writeConfigFile(t, content)))
+
+			if expectedError != nil {
+				assert.Nil(t, cnf)
+				assert.ErrorIs(t, err, expectedError)
+				assert.Equal(t, expectedMsg, err.Error())
+			} else {
+				assert.NotNil(t, cnf)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_NewConfigFileNotFound(t *testing.T) {
+	cnf, err := newConfig(WithFile(filepath.Join(t.TempDir(), "config.yaml")))
+
+	assert.Nil(t, cnf)
+	assert.ErrorIs(t, err, ErrReadConfigFile)
+}
+
+func Test_Flags(t *testing.T) {
+	path := writeConfigFile(t, testConfig)
+
+	fs := flag.NewFlagSet("example", flag.ContinueOnError)
+	opt := Flags(fs)
+	require.NoError(t, fs.Parse([]string{"--config", path}))
+
+	cnf := new(config)
+	opt(cnf)
+
+	assert.Equal(t, path, cnf.file)
+}
+
+func Test_Find(t *testing.T) {
+	testCases := []struct {
+		testName         string
+		node             string
+		env              map[string]string
+		expectedResponse string
+	}{
+		{
+			testName:         "file-values-case",
+			node:             "apps.example.input-ports.rest",
+			expectedResponse: "{\"address\":\"\",\"port\":\"8080\"}\n",
+		},
+		{
+			testName: "env-override-case",
+			node:     "apps.example.input-ports.rest",
+			env: map[string]string{
+				"EXAMPLE_APPS_EXAMPLE_INPUT_PORTS_REST_ADDRESS": "127.0.0.1",
+			},
+			expectedResponse: "{\"address\":\"127.0.0.1\",\"port\":\"8080\"}\n",
+		},
+		{
+			testName:         "empty-node-case",
+			node:             "apps.example.interface-adapters.storage.memory",
+			expectedResponse: "{}\n",
+		},
+	}
+
+	for _, c := range testCases {
+		testName := c.testName
+		node := c.node
+		env := c.env
+		expectedResponse := c.expectedResponse
+
+		t.Run(testName, func(t *testing.T) {
+			for k, v := range env {
+				t.Setenv(k, v)
+			}
+
+			cnf, err := newConfig(WithFile(writeConfigFile(t, testConfig)))
+			require.NoError(t, err)
+
+			reader, err := cnf.Find(node)
+			require.NoError(t, err)
+
+			data, err := io.ReadAll(reader)
+			require.NoError(t, err)
+
+			assert.Equal(t, expectedResponse, string(data))
+		})
+	}
+}
+
+func Test_FindNilConfig(t *testing.T) {
+	var cnf *config
+
+	reader, err := cnf.Find("apps")
+
+	assert.Nil(t, reader)
+	assert.ErrorIs(t, err, ErrConfigNotRead)
+}
+
+const testConfig string = `
+apps:
+  example:
+    log:
+      level: "info"
+    input-ports:
+      rest:
+        address: ""
+        port: "8080"
+    interface-adapters:
+      storage:
+        mongodb:
+          dsn: "mongodb-dsn"
+          database: "example"
+          collection: "lines"
+`
+
+func shippedConfig(t *testing.T) string {
+	t.Helper()
+
+	data, err := os.ReadFile("config.yaml")
+	require.NoError(t, err)
+
+	return string(data)
+}
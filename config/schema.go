@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+const (
+	KindAny Kind = iota
+	KindString
+	KindInt
+	KindBool
+	KindFloat
+	KindStringSlice
+)
+
+type Kind int
+
+func (k Kind) String() string {
+	return []string{"any", "string", "int", "bool", "float", "string slice"}[k]
+}
+
+// cast converts values coming from the file or from the environment, which
+// are always strings, to the kind the config readers expect.
+func (k Kind) cast(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	switch k {
+	case KindString:
+		return cast.ToStringE(v)
+	case KindInt:
+		return cast.ToIntE(v)
+	case KindBool:
+		return cast.ToBoolE(v)
+	case KindFloat:
+		return cast.ToFloat64E(v)
+	case KindStringSlice:
+		if s, is := v.(string); is {
+			return strings.Fields(strings.ReplaceAll(s, ",", " ")), nil
+		}
+		return cast.ToStringSliceE(v)
+	}
+
+	if _, is := v.(map[string]interface{}); is {
+		return nil, fmt.Errorf("%v is a section", v)
+	}
+
+	return v, nil
+}
+
+type Key struct {
+	Path     string
+	Kind     Kind
+	Required bool
+	Secret   bool
+}
+
+type Schema []Key
+
+// known tells whether key is declared in the schema, either as a value or as
+// a section holding declared values.
+func (s Schema) known(key string) bool {
+	for _, k := range s {
+		if k.Path == key || strings.HasPrefix(k.Path, key+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+var schema = Schema{
+	{Path: "apps.example.log.level", Kind: KindString},
+
+	{Path: "apps.example.input-ports.rest.address", Kind: KindString},
+	{Path: "apps.example.input-ports.rest.port", Kind: KindString, Required: true},
+	{Path: "apps.example.input-ports.rest.timeouts.write", Kind: KindString},
+	{Path: "apps.example.input-ports.rest.timeouts.read", Kind: KindString},
+	{Path: "apps.example.input-ports.rest.timeouts.batch-write", Kind: KindString},
+	{Path: "apps.example.input-ports.rest.timeouts.batch-read", Kind: KindString},
+	{Path: "apps.example.input-ports.rest.timeouts.search", Kind: KindString},
+	{Path: "apps.example.input-ports.rest.timeouts.list", Kind: KindString},
+	{Path: "apps.example.input-ports.rest.timeouts.delete", Kind: KindString},
+	{Path: "apps.example.input-ports.rest.timeouts.restore", Kind: KindString},
+	{Path: "apps.example.input-ports.rest.timeouts.history", Kind: KindString},
+	{Path: "apps.example.input-ports.rest.openapi.validate", Kind: KindBool},
+	{Path: "apps.example.input-ports.rest.openapi.ui", Kind: KindBool},
+	{Path: "apps.example.input-ports.rest.admin-token", Kind: KindString, Secret: true},
+	{Path: "apps.example.input-ports.rest.drain-delay", Kind: KindString},
+
+	{Path: "apps.example.input-ports.jobs.purge.retention", Kind: KindString},
+	{Path: "apps.example.input-ports.jobs.purge.interval", Kind: KindString},
+	{Path: "apps.example.input-ports.jobs.purge.timeout", Kind: KindString},
+
+	{Path: "apps.example.interface-adapters.identity.strategy", Kind: KindString},
+	{Path: "apps.example.interface-adapters.identity.node", Kind: KindInt},
+
+	{Path: "apps.example.interface-adapters.storage.backend", Kind: KindString},
+
+	{Path: "apps.example.interface-adapters.storage.mongodb.dsn", Kind: KindString, Required: true, Secret: true},
+	{Path: "apps.example.interface-adapters.storage.mongodb.database", Kind: KindString, Required: true},
+	{Path: "apps.example.interface-adapters.storage.mongodb.collection", Kind: KindString, Required: true},
+	{Path: "apps.example.interface-adapters.storage.mongodb.audit-collection", Kind: KindString},
+	{Path: "apps.example.interface-adapters.storage.mongodb.timeout", Kind: KindString},
+	{Path: "apps.example.interface-adapters.storage.mongodb.max-pool-size", Kind: KindInt},
+	{Path: "apps.example.interface-adapters.storage.mongodb.min-pool-size", Kind: KindInt},
+	{Path: "apps.example.interface-adapters.storage.mongodb.server-selection-timeout", Kind: KindString},
+	{Path: "apps.example.interface-adapters.storage.mongodb.connect-timeout", Kind: KindString},
+	{Path: "apps.example.interface-adapters.storage.mongodb.retry-writes", Kind: KindBool},
+	{Path: "apps.example.interface-adapters.storage.mongodb.read-preference", Kind: KindString},
+	{Path: "apps.example.interface-adapters.storage.mongodb.write-concern", Kind: KindString},
+	{Path: "apps.example.interface-adapters.storage.mongodb.tls", Kind: KindBool},
+
+	{Path: "apps.example.interface-adapters.storage.memory.timeout", Kind: KindString},
+	{Path: "apps.example.interface-adapters.storage.memory.implementation", Kind: KindString},
+	{Path: "apps.example.interface-adapters.storage.memory.shards", Kind: KindInt},
+
+	{Path: "apps.example.interface-adapters.storage.cache.enabled", Kind: KindBool},
+	{Path: "apps.example.interface-adapters.storage.cache.size", Kind: KindInt},
+	{Path: "apps.example.interface-adapters.storage.cache.ttl", Kind: KindString},
+	{Path: "apps.example.interface-adapters.storage.cache.negative-ttl", Kind: KindString},
+	{Path: "apps.example.interface-adapters.storage.cache.timeout", Kind: KindString},
+
+	{Path: "apps.example.interface-adapters.storage.resilience.mongodb.retry.attempts", Kind: KindInt},
+	{Path: "apps.example.interface-adapters.storage.resilience.mongodb.retry.base-delay", Kind: KindString},
+	{Path: "apps.example.interface-adapters.storage.resilience.mongodb.retry.max-delay", Kind: KindString},
+	{Path: "apps.example.interface-adapters.storage.resilience.mongodb.breaker.failures", Kind: KindInt},
+	{Path: "apps.example.interface-adapters.storage.resilience.mongodb.breaker.cooldown", Kind: KindString},
+	{Path: "apps.example.interface-adapters.storage.resilience.mongodb.bulkhead.max-concurrent", Kind: KindInt},
+	{Path: "apps.example.interface-adapters.storage.resilience.mongodb.bulkhead.wait", Kind: KindString},
+
+	{Path: "apps.example.interface-adapters.storage.resilience.memory.retry.attempts", Kind: KindInt},
+	{Path: "apps.example.interface-adapters.storage.resilience.memory.retry.base-delay", Kind: KindString},
+	{Path: "apps.example.interface-adapters.storage.resilience.memory.retry.max-delay", Kind: KindString},
+	{Path: "apps.example.interface-adapters.storage.resilience.memory.breaker.failures", Kind: KindInt},
+	{Path: "apps.example.interface-adapters.storage.resilience.memory.breaker.cooldown", Kind: KindString},
+	{Path: "apps.example.interface-adapters.storage.resilience.memory.bulkhead.max-concurrent", Kind: KindInt},
+	{Path: "apps.example.interface-adapters.storage.resilience.memory.bulkhead.wait", Kind: KindString},
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	fileSecretScheme string = "file"
+	envSecretScheme  string = "env"
+	schemeSeparator  string = "://"
+
+	redactedValue string = "[REDACTED]"
+
+	ErrSecretNotFound Error = "secret not found"
+)
+
+// SecretProvider resolves the config values written as "<scheme>://<ref>".
+// Values using a scheme without a registered provider, such as a mongodb
+// DSN, are returned as they are.
+type SecretProvider interface {
+	Scheme() string
+	Resolve(ref string) (string, error)
+}
+
+type secretProviders map[string]SecretProvider
+
+func defaultSecretProviders() secretProviders {
+	return secretProviders{
+		fileSecretScheme: fileSecretProvider{},
+		envSecretScheme:  envSecretProvider{},
+	}
+}
+
+func (sp secretProviders) lookup(value string) (SecretProvider, string, bool) {
+	scheme, ref, found := strings.Cut(value, schemeSeparator)
+	if !found {
+		return nil, "", false
+	}
+
+	provider, exists := sp[scheme]
+
+	return provider, ref, exists
+}
+
+type fileSecretProvider struct{}
+
+func (fileSecretProvider) Scheme() string {
+	return fileSecretScheme
+}
+
+func (fileSecretProvider) Resolve(ref string) (string, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", err.Error(), ErrSecretNotFound)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+type envSecretProvider struct{}
+
+func (envSecretProvider) Scheme() string {
+	return envSecretScheme
+}
+
+func (envSecretProvider) Resolve(ref string) (string, error) {
+	secret, exists := os.LookupEnv(ref)
+	if !exists {
+		return "", fmt.Errorf("%s: %w", ref, ErrSecretNotFound)
+	}
+
+	return secret, nil
+}
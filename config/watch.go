@@ -0,0 +1,165 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const (
+	ErrNoConfigFile Error = "config file not found"
+)
+
+// Subscriber receives the content of the watched node. It must validate the
+// content before applying it and return an error to reject it.
+type Subscriber func(io.Reader) error
+
+type subscription struct {
+	node string
+	fn   Subscriber
+	last []byte
+}
+
+// Watch calls fn every time a reload of the config file changes node. The
+// returned function cancels the subscription.
+func (c *config) Watch(node string, fn Subscriber) (func(), error) {
+	if c == nil {
+		return nil, ErrConfigNotRead
+	}
+
+	if err := c.watch(); err != nil {
+		return nil, err
+	}
+
+	return c.subscribe(node, fn)
+}
+
+func (c *config) subscribe(node string, fn Subscriber) (func(), error) {
+	current, err := c.find(node)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := c.nextID
+	c.nextID++
+	c.subscriptions[id] = &subscription{
+		node: node,
+		fn:   fn,
+		last: current,
+	}
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		delete(c.subscriptions, id)
+	}, nil
+}
+
+func (c *config) watch() error {
+	file := c.vp.ConfigFileUsed()
+	if file == "" {
+		return ErrNoConfigFile
+	}
+
+	c.watchOnce.Do(func() {
+		watcher := viper.New()
+		watcher.SetConfigFile(file)
+		watcher.SetConfigType(configFileType)
+		watcher.OnConfigChange(func(fsnotify.Event) {
+			c.reload()
+		})
+		watcher.WatchConfig()
+	})
+
+	return nil
+}
+
+// reload reads the config file again and only replaces the current config
+// when the new one is valid and every subscriber whose node changed accepts
+// it, in the order they subscribed. When a subscriber rejects its node the
+// subscribers that already applied the new content get their previous one
+// back and the current config is kept.
+func (c *config) reload() {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	next := &config{
+		file:    c.vp.ConfigFileUsed(),
+		schema:  c.schema,
+		secrets: c.secrets,
+	}
+
+	if err := next.read(); err != nil {
+		log.WithError(err).Error("config reload rejected")
+		return
+	}
+
+	if err := next.validate(); err != nil {
+		log.WithError(err).Error("config reload rejected")
+		return
+	}
+
+	c.mu.RLock()
+	ids := make([]int, 0, len(c.subscriptions))
+	for id := range c.subscriptions {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	notify := make([]*subscription, 0, len(ids))
+	for _, id := range ids {
+		notify = append(notify, c.subscriptions[id])
+	}
+	c.mu.RUnlock()
+
+	applied := make([]*subscription, 0, len(notify))
+	candidates := make(map[*subscription][]byte, len(notify))
+
+	for _, s := range notify {
+		data, err := next.find(s.node)
+		if err == nil && bytes.Equal(data, s.last) {
+			continue
+		}
+
+		if err == nil {
+			err = s.fn(bytes.NewReader(data))
+		}
+
+		if err != nil {
+			log.WithError(fmt.Errorf("%s: %w", err.Error(), ErrInvalidConfig)).WithField("node", s.node).Error("config reload rejected")
+			rollback(applied)
+
+			return
+		}
+
+		applied = append(applied, s)
+		candidates[s] = data
+	}
+
+	c.mu.Lock()
+	c.vp = next.vp
+	c.mu.Unlock()
+
+	for s, data := range candidates {
+		s.last = data
+	}
+}
+
+// rollback gives the subscribers back the content they had before a rejected
+// reload.
+func rollback(applied []*subscription) {
+	for _, s := range applied {
+		if err := s.fn(bytes.NewReader(s.last)); err != nil {
+			log.WithError(err).WithField("node", s.node).Error("config rollback failed")
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	restNode string = "apps.example.input-ports.rest"
+	logNode  string = "apps.example.log"
+)
+
+type subscriberMock struct {
+	mu       sync.Mutex
+	err      error
+	received []string
+}
+
+func (sm *subscriberMock) subscribe(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.received = append(sm.received, string(data))
+
+	return sm.err
+}
+
+func (sm *subscriberMock) calls() []string {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return append([]string(nil), sm.received...)
+}
+
+func Test_Reload(t *testing.T) {
+	testCases := []struct {
+		testName         string
+		newContent       func(string) string
+		subscriberError  error
+		expectedCalls    []string
+		expectedRestNode string
+	}{
+		{
+			testName: "changed-node-case",
+			newContent: func(s string) string {
+				return strings.Replace(s, `port: "8080"`, `port: "9090"`, 1)
+			},
+			expectedCalls:    []string{"{\"address\":\"\",\"port\":\"9090\"}\n"},
+			expectedRestNode: "{\"address\":\"\",\"port\":\"9090\"}\n",
+		},
+		{
+			testName: "unchanged-node-case",
+			newContent: func(s string) string {
+				return strings.Replace(s, `level: "info"`, `level: "debug"`, 1)
+			},
+			expectedCalls:    nil,
+			expectedRestNode: "{\"address\":\"\",\"port\":\"8080\"}\n",
+		},
+		{
+			testName: "invalid-config-case",
+			newContent: func(s string) string {
+				return strings.Replace(s, `port: "8080"`, `prot: "9090"`, 1)
+			},
+			expectedCalls:    nil,
+			expectedRestNode: "{\"address\":\"\",\"port\":\"8080\"}\n",
+		},
+		{
+			testName: "unreadable-config-case",
+			newContent: func(s string) string {
+				return "apps: ["
+			},
+			expectedCalls:    nil,
+			expectedRestNode: "{\"address\":\"\",\"port\":\"8080\"}\n",
+		},
+		{
+			testName: "rejected-by-subscriber-case",
+			newContent: func(s string) string {
+				return strings.Replace(s, `port: "8080"`, `port: "9090"`, 1)
+			},
+			subscriberError:  errors.New("invalid port"),
+			expectedCalls:    []string{"{\"address\":\"\",\"port\":\"9090\"}\n"},
+			expectedRestNode: "{\"address\":\"\",\"port\":\"8080\"}\n",
+		},
+	}
+
+	for _, c := range testCases {
+		testName := c.testName
+		newContent := c.newContent
+		subscriberError := c.subscriberError
+		expectedCalls := c.expectedCalls
+		expectedRestNode := c.expectedRestNode
+
+		t.Run(testName, func(t *testing.T) {
+			content := testConfig
+			path := writeConfigFile(t, content)
+
+			cnf, err := newConfig(WithFile(path))
+			require.NoError(t, err)
+
+			sub := &subscriberMock{err: subscriberError}
+			unsubscribe, err := cnf.subscribe(restNode, sub.subscribe)
+			require.NoError(t, err)
+			defer unsubscribe()
+
+			require.NoError(t, os.WriteFile(path, []byte(newContent(content)), 0o600))
+			cnf.reload()
+
+			data, err := cnf.find(restNode)
+			require.NoError(t, err)
+
+			assert.Equal(t, expectedCalls, sub.calls())
+			assert.Equal(t, expectedRestNode, string(data))
+		})
+	}
+}
+
+func Test_ReloadRejectedSubscriberRetries(t *testing.T) {
+	content := testConfig
+	path := writeConfigFile(t, content)
+
+	cnf, err := newConfig(WithFile(path))
+	require.NoError(t, err)
+
+	sub := &subscriberMock{err: errors.New("invalid port")}
+	unsubscribe, err := cnf.subscribe(restNode, sub.subscribe)
+	require.NoError(t, err)
+	defer unsubscribe()
+
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(content, `port: "8080"`, `port: "9090"`, 1)), 0o600))
+	cnf.reload()
+	cnf.reload()
+
+	assert.Len(t, sub.calls(), 2)
+}
+
+func Test_ReloadRejectedSubscriberRollsBack(t *testing.T) {
+	content := testConfig
+	path := writeConfigFile(t, content)
+
+	cnf, err := newConfig(WithFile(path))
+	require.NoError(t, err)
+
+	accepting := &subscriberMock{}
+	unsubscribe, err := cnf.subscribe(logNode, accepting.subscribe)
+	require.NoError(t, err)
+	defer unsubscribe()
+
+	rejecting := &subscriberMock{err: errors.New("invalid port")}
+	unsubscribe, err = cnf.subscribe(restNode, rejecting.subscribe)
+	require.NoError(t, err)
+	defer unsubscribe()
+
+	newContent := strings.Replace(content, `port: "8080"`, `port: "9090"`, 1)
+	newContent = strings.Replace(newContent, `level: "info"`, `level: "debug"`, 1)
+	require.NoError(t, os.WriteFile(path, []byte(newContent), 0o600))
+	cnf.reload()
+
+	data, err := cnf.find(logNode)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"{\"level\":\"debug\"}\n", "{\"level\":\"info\"}\n"}, accepting.calls())
+	assert.Equal(t, "{\"level\":\"info\"}\n", string(data))
+}
+
+func Test_Unsubscribe(t *testing.T) {
+	content := testConfig
+	path := writeConfigFile(t, content)
+
+	cnf, err := newConfig(WithFile(path))
+	require.NoError(t, err)
+
+	sub := &subscriberMock{}
+	unsubscribe, err := cnf.subscribe(restNode, sub.subscribe)
+	require.NoError(t, err)
+
+	unsubscribe()
+
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(content, `port: "8080"`, `port: "9090"`, 1)), 0o600))
+	cnf.reload()
+
+	assert.Empty(t, sub.calls())
+}
+
+func Test_WatchFileChange(t *testing.T) {
+	content := testConfig
+	path := writeConfigFile(t, content)
+
+	cnf, err := newConfig(WithFile(path))
+	require.NoError(t, err)
+
+	sub := &subscriberMock{}
+	unsubscribe, err := cnf.Watch(restNode, sub.subscribe)
+	require.NoError(t, err)
+	defer unsubscribe()
+
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(content, `port: "8080"`, `port: "9090"`, 1)), 0o600))
+
+	assert.Eventually(t, func() bool {
+		return len(sub.calls()) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_WatchNilConfig(t *testing.T) {
+	var cnf *config
+
+	unsubscribe, err := cnf.Watch(restNode, func(io.Reader) error { return nil })
+
+	assert.Nil(t, unsubscribe)
+	assert.ErrorIs(t, err, ErrConfigNotRead)
+}
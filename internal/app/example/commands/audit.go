@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+// auditRecord stamps a change of a line with the actor and the request found
+// in ctx. Before is nil for a creation.
+func auditRecord(ctx context.Context, action example.AuditAction, at time.Time, before, after *example.Line) example.AuditRecord {
+	record := example.AuditRecord{
+		Action:    action,
+		Actor:     example.ActorFrom(ctx),
+		RequestID: example.RequestIDFrom(ctx),
+		At:        at,
+		Before:    before,
+		After:     after,
+	}
+
+	if after != nil {
+		record.LineID = after.ID
+	} else if before != nil {
+		record.LineID = before.ID
+	}
+
+	return record
+}
+
+// appendAudit appends the records of changes already stored. A change is not
+// undone when its records are lost, so the failure is logged and the request
+// still succeeds.
+func appendAudit(ctx context.Context, audit example.AuditLog, records []example.AuditRecord) {
+	if len(records) == 0 {
+		return
+	}
+
+	if err := audit.Append(ctx, records); err != nil {
+		ids := make([]string, len(records))
+		for i, record := range records {
+			ids[i] = record.LineID.String()
+		}
+
+		log.WithError(err).WithField("lines", ids).WithField("action", records[0].Action).Error("audit records lost")
+	}
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const (
+	ErrInvalidID ServiceError = "invalid id parameter"
+	ErrNotFound  ServiceError = "line not found"
+)
+
+// DeleteExampleRequest moves the line to the trash, where it stays until it
+// is restored or purged. Deleting a deleted line changes nothing and leaves
+// no audit record.
+type DeleteExampleRequest struct {
+	ID string
+}
+
+type DeleteLineRequestHandler interface {
+	Handle(ctx context.Context, command DeleteExampleRequest) error
+}
+
+type deleteExampleRequestHandler struct {
+	repo       example.LineRepository
+	idProvider example.IdentityProvider
+	audit      example.AuditLog
+	now        func() time.Time
+}
+
+func NewDeleteExampleRequestHandler(repo example.LineRepository, idProvider example.IdentityProvider, audit example.AuditLog) DeleteLineRequestHandler {
+	return deleteExampleRequestHandler{
+		repo:       repo,
+		idProvider: idProvider,
+		audit:      audit,
+		now:        time.Now,
+	}
+}
+
+func (h deleteExampleRequestHandler) Handle(ctx context.Context, command DeleteExampleRequest) error {
+	id, err := h.idProvider.ParseID(command.ID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", err.Error(), ErrInvalidID)
+	}
+
+	before, err := h.repo.Read(ctx, id)
+	if err != nil {
+		return serviceError(ctx, err)
+	}
+
+	if before == nil {
+		return ErrNotFound
+	}
+
+	if before.Deleted() {
+		return nil
+	}
+
+	at := h.now().UTC()
+
+	found, err := h.repo.Delete(ctx, id, at)
+	if err != nil {
+		return serviceError(ctx, err)
+	}
+
+	if !found {
+		return ErrNotFound
+	}
+
+	after := *before
+	after.DeletedAt = at
+
+	appendAudit(ctx, h.audit, []example.AuditRecord{auditRecord(ctx, example.AuditDelete, at, before, &after)})
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"clean-arquitecture-template/internal/domain/example"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_DeleteExampleRequestHandlerHandle(t *testing.T) {
+	ctx := example.WithRequestID(example.WithActor(context.Background(), "alice"), "r1")
+	now := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+	id := example.MockIdentifier("first")
+
+	live := &example.Line{ID: id, Created: now.Add(-time.Hour), Data: "first-line"}
+	deleted := &example.Line{ID: id, Created: now.Add(-time.Hour), Data: "first-line", DeletedAt: now}
+
+	parsed := func() *example.MockIdentityProvider {
+		provider := &example.MockIdentityProvider{}
+		provider.On("ParseID", "first").Return(id, nil)
+
+		return provider
+	}
+
+	testCases := []struct {
+		name          string
+		repo          func() *example.MockRepository
+		idProvider    func() *example.MockIdentityProvider
+		audit         func() *example.MockAuditLog
+		expectedError error
+	}{
+		{
+			name: "successfull-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", ctx, id).Return(live, nil)
+				mr.On("Delete", ctx, id, now).Return(true, nil)
+
+				return mr
+			},
+			idProvider: parsed,
+			audit: func() *example.MockAuditLog {
+				ma := &example.MockAuditLog{}
+				ma.On("Append", ctx, []example.AuditRecord{{
+					LineID:    id,
+					Action:    example.AuditDelete,
+					Actor:     "alice",
+					RequestID: "r1",
+					At:        now,
+					Before:    live,
+					After:     deleted,
+				}}).Return(nil)
+
+				return ma
+			},
+		},
+		{
+			name: "already-deleted-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", ctx, id).Return(deleted, nil)
+
+				return mr
+			},
+			idProvider: parsed,
+			audit: func() *example.MockAuditLog {
+				return &example.MockAuditLog{}
+			},
+		},
+		{
+			name: "not-found-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", ctx, id).Return((*example.Line)(nil), nil)
+
+				return mr
+			},
+			idProvider: parsed,
+			audit: func() *example.MockAuditLog {
+				return &example.MockAuditLog{}
+			},
+			expectedError: ErrNotFound,
+		},
+		{
+			name: "purged-meanwhile-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", ctx, id).Return(live, nil)
+				mr.On("Delete", ctx, id, now).Return(false, nil)
+
+				return mr
+			},
+			idProvider: parsed,
+			audit: func() *example.MockAuditLog {
+				return &example.MockAuditLog{}
+			},
+			expectedError: ErrNotFound,
+		},
+		{
+			name: "invalid-id-case",
+			repo: func() *example.MockRepository {
+				return &example.MockRepository{}
+			},
+			idProvider: func() *example.MockIdentityProvider {
+				provider := &example.MockIdentityProvider{}
+				provider.On("ParseID", "first").Return(example.MockIdentifier(""), errors.New("some-error"))
+
+				return provider
+			},
+			audit: func() *example.MockAuditLog {
+				return &example.MockAuditLog{}
+			},
+			expectedError: ErrInvalidID,
+		},
+		{
+			name: "error-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", ctx, id).Return(live, nil)
+				mr.On("Delete", ctx, id, now).Return(false, errors.New("some-error"))
+
+				return mr
+			},
+			idProvider: parsed,
+			audit: func() *example.MockAuditLog {
+				return &example.MockAuditLog{}
+			},
+			expectedError: ErrSystem,
+		},
+		{
+			name: "audit-error-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", ctx, id).Return(live, nil)
+				mr.On("Delete", ctx, id, now).Return(true, nil)
+
+				return mr
+			},
+			idProvider: parsed,
+			audit: func() *example.MockAuditLog {
+				ma := &example.MockAuditLog{}
+				ma.On("Append", ctx, mock.Anything).Return(example.ErrTimeout)
+
+				return ma
+			},
+			expectedError: nil,
+		},
+	}
+
+	for _, c := range testCases {
+		name := c.name
+		repo := c.repo()
+		idProvider := c.idProvider()
+		audit := c.audit()
+		expectedError := c.expectedError
+
+		t.Run(name, func(t *testing.T) {
+			h := deleteExampleRequestHandler{
+				repo:       repo,
+				idProvider: idProvider,
+				audit:      audit,
+				now:        func() time.Time { return now },
+			}
+
+			err := h.Handle(ctx, DeleteExampleRequest{ID: "first"})
+
+			assert.ErrorIs(t, err, expectedError)
+			repo.AssertExpectations(t)
+			audit.AssertExpectations(t)
+		})
+	}
+}
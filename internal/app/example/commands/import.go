@@ -0,0 +1,298 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const (
+	DefaultImportWorkers   int = 4
+	DefaultImportBatchSize int = 100
+
+	// MaxImportFailures bounds the failures kept in the report, the count
+	// keeps going.
+	MaxImportFailures int = 1000
+
+	ErrImportSource ServiceError = "unable to read import source"
+	ErrInvalidLine  ServiceError = "invalid line"
+)
+
+// ImportItem is one line of an import source. Err holds the reason why the
+// source could not decode it, in which case the line is reported as failed.
+type ImportItem struct {
+	Position  int
+	ID        string
+	CreatedAt time.Time
+	Data      string
+	Err       error
+}
+
+// ImportSource yields the lines to import, and io.EOF after the last one.
+// Any other error aborts the import.
+type ImportSource interface {
+	Next() (ImportItem, error)
+}
+
+type ImportProgress struct {
+	Read    int64
+	Written int64
+	Failed  int64
+}
+
+type ImportFailure struct {
+	Position int
+	ID       string
+	Err      error
+}
+
+type ImportReport struct {
+	ImportProgress
+	Failures []ImportFailure
+}
+
+// ImportExamplesRequest imports the lines of Source. The lines get new
+// identifiers and creation times unless KeepIDs and KeepTimestamps are set.
+// Workers batches of BatchSize lines are written at most at once, the source
+// is not read further until one of them is done. Progress, when set, is
+// called after every batch.
+type ImportExamplesRequest struct {
+	Source         ImportSource
+	KeepIDs        bool
+	KeepTimestamps bool
+	Workers        int
+	BatchSize      int
+	Progress       func(ImportProgress)
+}
+
+type ImportLinesRequestHandler interface {
+	Handle(ctx context.Context, command ImportExamplesRequest) (ImportReport, error)
+}
+
+type importExamplesRequestHandler struct {
+	repo       example.LineRepository
+	idProvider example.IdentityProvider
+	audit      example.AuditLog
+	now        func() time.Time
+}
+
+func NewImportExamplesRequestHandler(repo example.LineRepository, idProvider example.IdentityProvider, audit example.AuditLog) ImportLinesRequestHandler {
+	return importExamplesRequestHandler{
+		repo:       repo,
+		idProvider: idProvider,
+		audit:      audit,
+		now:        time.Now,
+	}
+}
+
+type pendingLine struct {
+	position int
+	line     example.Line
+}
+
+func (h importExamplesRequestHandler) Handle(ctx context.Context, command ImportExamplesRequest) (ImportReport, error) {
+	workers := command.Workers
+	if workers <= 0 {
+		workers = DefaultImportWorkers
+	}
+
+	batchSize := command.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultImportBatchSize
+	}
+
+	if batchSize > MaxBatchSize {
+		batchSize = MaxBatchSize
+	}
+
+	tracker := &importTracker{progress: command.Progress}
+
+	// The channel holds no more than one batch per worker, so a slow store
+	// slows the reading of the source down.
+	batches := make(chan []pendingLine, workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for batch := range batches {
+				h.write(ctx, batch, tracker)
+			}
+		}()
+	}
+
+	sourceErr := h.read(ctx, command, batchSize, batches, tracker)
+
+	close(batches)
+	wg.Wait()
+
+	report := tracker.report()
+
+	if sourceErr != nil {
+		return report, sourceErr
+	}
+
+	if err := ctx.Err(); err != nil {
+		return report, serviceError(ctx, err)
+	}
+
+	return report, nil
+}
+
+func (h importExamplesRequestHandler) read(ctx context.Context, command ImportExamplesRequest, batchSize int, batches chan<- []pendingLine, tracker *importTracker) error {
+	batch := make([]pendingLine, 0, batchSize)
+
+	send := func() bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case batches <- batch:
+			batch = make([]pendingLine, 0, batchSize)
+			return true
+		}
+	}
+
+	for {
+		item, err := command.Source.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", err.Error(), ErrImportSource)
+		}
+
+		tracker.read()
+
+		line, err := h.line(item, command)
+		if err != nil {
+			tracker.fail(ImportFailure{Position: item.Position, ID: item.ID, Err: err})
+			continue
+		}
+
+		batch = append(batch, pendingLine{position: item.Position, line: line})
+		if len(batch) == batchSize && !send() {
+			return nil
+		}
+	}
+
+	if len(batch) > 0 {
+		send()
+	}
+
+	return nil
+}
+
+func (h importExamplesRequestHandler) line(item ImportItem, command ImportExamplesRequest) (example.Line, error) {
+	if item.Err != nil {
+		return example.Line{}, fmt.Errorf("%s: %w", item.Err.Error(), ErrInvalidLine)
+	}
+
+	line := example.Line{
+		ID:      h.idProvider.NewID(),
+		Created: h.now().UTC(),
+		Data:    item.Data,
+	}
+
+	if command.KeepIDs {
+		id, err := h.idProvider.ParseID(item.ID)
+		if err != nil {
+			return example.Line{}, fmt.Errorf("%s: %w", err.Error(), ErrInvalidLine)
+		}
+
+		line.ID = id
+	}
+
+	if command.KeepTimestamps {
+		line.Created = item.CreatedAt
+	}
+
+	return line, nil
+}
+
+func (h importExamplesRequestHandler) write(ctx context.Context, batch []pendingLine, tracker *importTracker) {
+	lines := make([]example.Line, len(batch))
+	for i, pending := range batch {
+		lines[i] = pending.line
+	}
+
+	errs := batchErrors(h.repo.WriteMany(ctx, lines), len(lines))
+
+	at := h.now().UTC()
+
+	var failures []ImportFailure
+	var records []example.AuditRecord
+	for i, pending := range batch {
+		if errs[i] != nil {
+			failures = append(failures, ImportFailure{
+				Position: pending.position,
+				ID:       pending.line.ID.String(),
+				Err:      serviceError(ctx, errs[i]),
+			})
+		} else {
+			records = append(records, auditRecord(ctx, example.AuditCreate, at, nil, &lines[i]))
+		}
+	}
+
+	appendAudit(ctx, h.audit, records)
+
+	tracker.written(int64(len(batch)-len(failures)), failures)
+}
+
+type importTracker struct {
+	mu       sync.Mutex
+	current  ImportReport
+	progress func(ImportProgress)
+}
+
+func (it *importTracker) read() {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	it.current.Read++
+}
+
+func (it *importTracker) fail(failure ImportFailure) {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	it.failed(failure)
+}
+
+func (it *importTracker) failed(failure ImportFailure) {
+	it.current.Failed++
+	if len(it.current.Failures) < MaxImportFailures {
+		it.current.Failures = append(it.current.Failures, failure)
+	}
+}
+
+func (it *importTracker) written(written int64, failures []ImportFailure) {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	it.current.Written += written
+	for _, failure := range failures {
+		it.failed(failure)
+	}
+
+	if it.progress != nil {
+		it.progress(it.current.ImportProgress)
+	}
+}
+
+func (it *importTracker) report() ImportReport {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	report := it.current
+	report.Failures = append([]ImportFailure(nil), it.current.Failures...)
+
+	return report
+}
@@ -0,0 +1,221 @@
+package commands
+
+import (
+	"clean-arquitecture-template/internal/domain/example"
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type sliceSource struct {
+	items []ImportItem
+	err   error
+}
+
+func (ss *sliceSource) Next() (ImportItem, error) {
+	if len(ss.items) == 0 {
+		if ss.err != nil {
+			return ImportItem{}, ss.err
+		}
+
+		return ImportItem{}, io.EOF
+	}
+
+	item := ss.items[0]
+	ss.items = ss.items[1:]
+
+	return item, nil
+}
+
+// recordingRepository collects the lines written by concurrent batches, and
+// fails every one of them with err. With short set it returns no errors at all.
+type recordingRepository struct {
+	example.MockRepository
+
+	mu    sync.Mutex
+	lines []example.Line
+	err   error
+	short bool
+}
+
+func (rr *recordingRepository) WriteMany(ctx context.Context, lines []example.Line) []error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	rr.lines = append(rr.lines, lines...)
+	if rr.short {
+		return nil
+	}
+
+	errs := make([]error, len(lines))
+	for i := range errs {
+		errs[i] = rr.err
+	}
+
+	return errs
+}
+
+func Test_ImportExamplesRequestHandlerHandle(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, time.May, 1, 12, 0, 0, 0, time.FixedZone("", 2*60*60))
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+
+	items := func() []ImportItem {
+		return []ImportItem{
+			{Position: 1, ID: "one", CreatedAt: tstamp, Data: "first-line"},
+			{Position: 2, Err: errors.New("malformed")},
+			{Position: 4, ID: "bad", CreatedAt: tstamp, Data: "third-line"},
+			{Position: 5, ID: "two", CreatedAt: tstamp, Data: "fourth-line"},
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		request          ImportExamplesRequest
+		writeErr         error
+		short            bool
+		auditErr         error
+		expectedLines    []example.Line
+		expectedProgress ImportProgress
+		expectedFailures []int
+		expectedError    error
+	}{
+		{
+			name:    "new-ids-case",
+			request: ImportExamplesRequest{Source: &sliceSource{items: items()}, BatchSize: 2},
+			expectedLines: []example.Line{
+				{ID: example.MockIdentifier("new"), Created: now.UTC(), Data: "first-line"},
+				{ID: example.MockIdentifier("new"), Created: now.UTC(), Data: "fourth-line"},
+				{ID: example.MockIdentifier("new"), Created: now.UTC(), Data: "third-line"},
+			},
+			expectedProgress: ImportProgress{Read: 4, Written: 3, Failed: 1},
+			expectedFailures: []int{2},
+		},
+		{
+			name:    "keep-ids-and-timestamps-case",
+			request: ImportExamplesRequest{Source: &sliceSource{items: items()}, KeepIDs: true, KeepTimestamps: true, Workers: 1},
+			expectedLines: []example.Line{
+				{ID: example.MockIdentifier("one"), Created: tstamp, Data: "first-line"},
+				{ID: example.MockIdentifier("two"), Created: tstamp, Data: "fourth-line"},
+			},
+			expectedProgress: ImportProgress{Read: 4, Written: 2, Failed: 2},
+			expectedFailures: []int{2, 4},
+		},
+		{
+			name:     "write-error-case",
+			request:  ImportExamplesRequest{Source: &sliceSource{items: items()}, KeepIDs: true},
+			writeErr: example.ErrTimeout,
+			expectedLines: []example.Line{
+				{ID: example.MockIdentifier("one"), Created: now.UTC(), Data: "first-line"},
+				{ID: example.MockIdentifier("two"), Created: now.UTC(), Data: "fourth-line"},
+			},
+			expectedProgress: ImportProgress{Read: 4, Written: 0, Failed: 4},
+			expectedFailures: []int{1, 2, 4, 5},
+		},
+		{
+			name:    "missing-errors-case",
+			request: ImportExamplesRequest{Source: &sliceSource{items: items()}, KeepIDs: true},
+			short:   true,
+			expectedLines: []example.Line{
+				{ID: example.MockIdentifier("one"), Created: now.UTC(), Data: "first-line"},
+				{ID: example.MockIdentifier("two"), Created: now.UTC(), Data: "fourth-line"},
+			},
+			expectedProgress: ImportProgress{Read: 4, Written: 0, Failed: 4},
+			expectedFailures: []int{1, 2, 4, 5},
+		},
+		{
+			name:     "audit-error-case",
+			request:  ImportExamplesRequest{Source: &sliceSource{items: items()}, KeepIDs: true},
+			auditErr: errors.New("some-error"),
+			expectedLines: []example.Line{
+				{ID: example.MockIdentifier("one"), Created: now.UTC(), Data: "first-line"},
+				{ID: example.MockIdentifier("two"), Created: now.UTC(), Data: "fourth-line"},
+			},
+			expectedProgress: ImportProgress{Read: 4, Written: 2, Failed: 2},
+			expectedFailures: []int{2, 4},
+		},
+		{
+			name:             "source-error-case",
+			request:          ImportExamplesRequest{Source: &sliceSource{err: errors.New("broken pipe")}},
+			expectedProgress: ImportProgress{},
+			expectedError:    ErrImportSource,
+		},
+	}
+
+	for _, c := range testCases {
+		request := c.request
+		writeErr := c.writeErr
+		short := c.short
+		auditErr := c.auditErr
+		expectedLines := c.expectedLines
+		expectedProgress := c.expectedProgress
+		expectedFailures := c.expectedFailures
+		expectedError := c.expectedError
+
+		t.Run(c.name, func(t *testing.T) {
+			repo := &recordingRepository{err: writeErr, short: short}
+			calls := 0
+
+			provider := &example.MockIdentityProvider{}
+			provider.On("NewID").Return(example.MockIdentifier("new"))
+			provider.On("ParseID", "bad").Return(example.MockIdentifier(""), errors.New("invalid"))
+			provider.On("ParseID", "one").Return(example.MockIdentifier("one"), nil)
+			provider.On("ParseID", "two").Return(example.MockIdentifier("two"), nil)
+
+			request.Progress = func(ImportProgress) {
+				calls++
+			}
+
+			audit := &example.MockAuditLog{}
+			audit.On("Append", ctx, mock.Anything).Return(auditErr)
+
+			handler := importExamplesRequestHandler{
+				repo:       repo,
+				idProvider: provider,
+				audit:      audit,
+				now:        func() time.Time { return now },
+			}
+
+			report, err := handler.Handle(ctx, request)
+
+			assert.ErrorIs(t, err, expectedError)
+			assert.Equal(t, expectedProgress, report.ImportProgress)
+
+			sort.Slice(repo.lines, func(i, j int) bool {
+				return repo.lines[i].Data < repo.lines[j].Data
+			})
+			assert.Equal(t, expectedLines, repo.lines)
+
+			var failures []int
+			for _, failure := range report.Failures {
+				failures = append(failures, failure.Position)
+				assert.Error(t, failure.Err)
+			}
+			sort.Ints(failures)
+			assert.Equal(t, expectedFailures, failures)
+
+			if len(expectedLines) > 0 {
+				assert.Greater(t, calls, 0)
+			}
+
+			var audited int
+			for _, call := range audit.Calls {
+				for _, record := range call.Arguments.Get(1).([]example.AuditRecord) {
+					assert.Equal(t, example.AuditCreate, record.Action)
+					assert.Equal(t, now.UTC(), record.At)
+					audited++
+				}
+			}
+			if auditErr == nil {
+				assert.Equal(t, int(expectedProgress.Written), audited)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const ErrRetention ServiceError = "invalid retention"
+
+// PurgeExamplesRequest removes for good the lines deleted more than
+// Retention ago, leaving a purge record of each in the audit trail.
+type PurgeExamplesRequest struct {
+	Retention time.Duration
+}
+
+type PurgeLinesRequestHandler interface {
+	Handle(ctx context.Context, command PurgeExamplesRequest) (int64, error)
+}
+
+type purgeExamplesRequestHandler struct {
+	repo  example.LineRepository
+	audit example.AuditLog
+	now   func() time.Time
+}
+
+func NewPurgeExamplesRequestHandler(repo example.LineRepository, audit example.AuditLog) PurgeLinesRequestHandler {
+	return purgeExamplesRequestHandler{
+		repo:  repo,
+		audit: audit,
+		now:   time.Now,
+	}
+}
+
+func (h purgeExamplesRequestHandler) Handle(ctx context.Context, command PurgeExamplesRequest) (int64, error) {
+	if command.Retention < 0 {
+		return 0, ErrRetention
+	}
+
+	now := h.now().UTC()
+
+	purged, err := h.repo.Purge(ctx, now.Add(-command.Retention))
+	if err != nil {
+		return 0, serviceError(ctx, err)
+	}
+
+	if len(purged) == 0 {
+		return 0, nil
+	}
+
+	records := make([]example.AuditRecord, len(purged))
+	for i := range purged {
+		records[i] = auditRecord(ctx, example.AuditPurge, now, &purged[i], nil)
+	}
+
+	appendAudit(ctx, h.audit, records)
+
+	return int64(len(purged)), nil
+}
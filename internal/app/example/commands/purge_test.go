@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"clean-arquitecture-template/internal/domain/example"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PurgeExamplesRequestHandlerHandle(t *testing.T) {
+	ctx := example.WithRequestID(example.WithActor(context.Background(), "jobs:purge"), "r1")
+	now := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+	retention := 24 * time.Hour
+
+	purged := []example.Line{
+		{ID: example.MockIdentifier("one"), Created: now.Add(-72 * time.Hour), Data: "first-line", DeletedAt: now.Add(-48 * time.Hour)},
+		{ID: example.MockIdentifier("two"), Created: now.Add(-72 * time.Hour), Data: "second-line", DeletedAt: now.Add(-36 * time.Hour)},
+	}
+
+	records := []example.AuditRecord{
+		{LineID: example.MockIdentifier("one"), Action: example.AuditPurge, Actor: "jobs:purge", RequestID: "r1", At: now, Before: &purged[0]},
+		{LineID: example.MockIdentifier("two"), Action: example.AuditPurge, Actor: "jobs:purge", RequestID: "r1", At: now, Before: &purged[1]},
+	}
+
+	testCases := []struct {
+		name           string
+		repo           *example.MockRepository
+		audit          *example.MockAuditLog
+		retention      time.Duration
+		expectedPurged int64
+		expectedError  error
+	}{
+		{
+			name: "successfull-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Purge", ctx, now.Add(-retention)).Return(purged, nil)
+
+				return mr
+			}(),
+			audit: func() *example.MockAuditLog {
+				ma := &example.MockAuditLog{}
+				ma.On("Append", ctx, records).Return(nil)
+
+				return ma
+			}(),
+			retention:      retention,
+			expectedPurged: 2,
+		},
+		{
+			name: "no-retention-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Purge", ctx, now).Return(nil, nil)
+
+				return mr
+			}(),
+			audit: &example.MockAuditLog{},
+		},
+		{
+			name:          "negative-retention-case",
+			repo:          &example.MockRepository{},
+			audit:         &example.MockAuditLog{},
+			retention:     -time.Hour,
+			expectedError: ErrRetention,
+		},
+		{
+			name: "error-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Purge", ctx, now.Add(-retention)).Return(nil, errors.New("some-error"))
+
+				return mr
+			}(),
+			audit:         &example.MockAuditLog{},
+			retention:     retention,
+			expectedError: ErrSystem,
+		},
+		{
+			name: "audit-error-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Purge", ctx, now.Add(-retention)).Return(purged, nil)
+
+				return mr
+			}(),
+			audit: func() *example.MockAuditLog {
+				ma := &example.MockAuditLog{}
+				ma.On("Append", ctx, records).Return(errors.New("some-error"))
+
+				return ma
+			}(),
+			retention:      retention,
+			expectedPurged: 2,
+		},
+	}
+
+	for _, c := range testCases {
+		name := c.name
+		repo := c.repo
+		audit := c.audit
+		retention := c.retention
+		expectedPurged := c.expectedPurged
+		expectedError := c.expectedError
+
+		t.Run(name, func(t *testing.T) {
+			h := purgeExamplesRequestHandler{
+				repo:  repo,
+				audit: audit,
+				now:   func() time.Time { return now },
+			}
+
+			purged, err := h.Handle(ctx, PurgeExamplesRequest{Retention: retention})
+
+			assert.Equal(t, expectedPurged, purged)
+			assert.ErrorIs(t, err, expectedError)
+			repo.AssertExpectations(t)
+			audit.AssertExpectations(t)
+		})
+	}
+}
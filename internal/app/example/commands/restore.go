@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+// RestoreExampleRequest takes the line out of the trash. Restoring a line
+// that is not deleted changes nothing and leaves no audit record.
+type RestoreExampleRequest struct {
+	ID string
+}
+
+type RestoreLineRequestHandler interface {
+	Handle(ctx context.Context, command RestoreExampleRequest) error
+}
+
+type restoreExampleRequestHandler struct {
+	repo       example.LineRepository
+	idProvider example.IdentityProvider
+	audit      example.AuditLog
+	now        func() time.Time
+}
+
+func NewRestoreExampleRequestHandler(repo example.LineRepository, idProvider example.IdentityProvider, audit example.AuditLog) RestoreLineRequestHandler {
+	return restoreExampleRequestHandler{
+		repo:       repo,
+		idProvider: idProvider,
+		audit:      audit,
+		now:        time.Now,
+	}
+}
+
+func (h restoreExampleRequestHandler) Handle(ctx context.Context, command RestoreExampleRequest) error {
+	id, err := h.idProvider.ParseID(command.ID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", err.Error(), ErrInvalidID)
+	}
+
+	before, err := h.repo.Read(ctx, id)
+	if err != nil {
+		return serviceError(ctx, err)
+	}
+
+	if before == nil {
+		return ErrNotFound
+	}
+
+	if !before.Deleted() {
+		return nil
+	}
+
+	found, err := h.repo.Restore(ctx, id)
+	if err != nil {
+		return serviceError(ctx, err)
+	}
+
+	if !found {
+		return ErrNotFound
+	}
+
+	after := *before
+	after.DeletedAt = time.Time{}
+
+	appendAudit(ctx, h.audit, []example.AuditRecord{auditRecord(ctx, example.AuditRestore, h.now().UTC(), before, &after)})
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"clean-arquitecture-template/internal/domain/example"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_RestoreExampleRequestHandlerHandle(t *testing.T) {
+	ctx := example.WithActor(context.Background(), "alice")
+	now := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+	id := example.MockIdentifier("first")
+
+	live := &example.Line{ID: id, Created: now.Add(-time.Hour), Data: "first-line"}
+	deleted := &example.Line{ID: id, Created: now.Add(-time.Hour), Data: "first-line", DeletedAt: now.Add(-time.Minute)}
+
+	parsed := func() *example.MockIdentityProvider {
+		provider := &example.MockIdentityProvider{}
+		provider.On("ParseID", "first").Return(id, nil)
+
+		return provider
+	}
+
+	testCases := []struct {
+		name          string
+		repo          func() *example.MockRepository
+		idProvider    func() *example.MockIdentityProvider
+		audit         func() *example.MockAuditLog
+		expectedError error
+	}{
+		{
+			name: "successfull-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", ctx, id).Return(deleted, nil)
+				mr.On("Restore", ctx, id).Return(true, nil)
+
+				return mr
+			},
+			idProvider: parsed,
+			audit: func() *example.MockAuditLog {
+				ma := &example.MockAuditLog{}
+				ma.On("Append", ctx, []example.AuditRecord{{
+					LineID: id,
+					Action: example.AuditRestore,
+					Actor:  "alice",
+					At:     now,
+					Before: deleted,
+					After:  live,
+				}}).Return(nil)
+
+				return ma
+			},
+		},
+		{
+			name: "not-deleted-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", ctx, id).Return(live, nil)
+
+				return mr
+			},
+			idProvider: parsed,
+			audit: func() *example.MockAuditLog {
+				return &example.MockAuditLog{}
+			},
+		},
+		{
+			name: "not-found-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", ctx, id).Return((*example.Line)(nil), nil)
+
+				return mr
+			},
+			idProvider: parsed,
+			audit: func() *example.MockAuditLog {
+				return &example.MockAuditLog{}
+			},
+			expectedError: ErrNotFound,
+		},
+		{
+			name: "invalid-id-case",
+			repo: func() *example.MockRepository {
+				return &example.MockRepository{}
+			},
+			idProvider: func() *example.MockIdentityProvider {
+				provider := &example.MockIdentityProvider{}
+				provider.On("ParseID", "first").Return(example.MockIdentifier(""), errors.New("some-error"))
+
+				return provider
+			},
+			audit: func() *example.MockAuditLog {
+				return &example.MockAuditLog{}
+			},
+			expectedError: ErrInvalidID,
+		},
+		{
+			name: "timeout-error-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", ctx, id).Return(deleted, nil)
+				mr.On("Restore", ctx, id).Return(false, example.ErrTimeout)
+
+				return mr
+			},
+			idProvider: parsed,
+			audit: func() *example.MockAuditLog {
+				return &example.MockAuditLog{}
+			},
+			expectedError: ErrTimeout,
+		},
+		{
+			name: "audit-error-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", ctx, id).Return(deleted, nil)
+				mr.On("Restore", ctx, id).Return(true, nil)
+
+				return mr
+			},
+			idProvider: parsed,
+			audit: func() *example.MockAuditLog {
+				ma := &example.MockAuditLog{}
+				ma.On("Append", ctx, mock.Anything).Return(errors.New("some-error"))
+
+				return ma
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		name := c.name
+		repo := c.repo()
+		idProvider := c.idProvider()
+		audit := c.audit()
+		expectedError := c.expectedError
+
+		t.Run(name, func(t *testing.T) {
+			h := restoreExampleRequestHandler{
+				repo:       repo,
+				idProvider: idProvider,
+				audit:      audit,
+				now:        func() time.Time { return now },
+			}
+
+			err := h.Handle(ctx, RestoreExampleRequest{ID: "first"})
+
+			assert.ErrorIs(t, err, expectedError)
+			repo.AssertExpectations(t)
+			audit.AssertExpectations(t)
+		})
+	}
+}
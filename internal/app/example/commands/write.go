@@ -2,13 +2,18 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"clean-arquitecture-template/internal/domain/example"
 )
 
 const (
-	ErrSystem ServiceError = "system error"
+	ErrSystem      ServiceError = "system error"
+	ErrTimeout     ServiceError = "timeout error"
+	ErrUnavailable ServiceError = "service unavailable"
+	ErrDuplicate   ServiceError = "line already exists"
 )
 
 type ServiceError string
@@ -28,27 +33,51 @@ type CreateLineRequestHandler interface {
 type addExampleRequestHandler struct {
 	repo       example.LineRepository
 	idProvider example.IdentityProvider
+	audit      example.AuditLog
+	now        func() time.Time
 }
 
-func NewAddExampleRequestHandler(repo example.LineRepository, idProvider example.IdentityProvider) CreateLineRequestHandler {
+func NewAddExampleRequestHandler(repo example.LineRepository, idProvider example.IdentityProvider, audit example.AuditLog) CreateLineRequestHandler {
 	return addExampleRequestHandler{
 		repo:       repo,
 		idProvider: idProvider,
+		audit:      audit,
+		now:        time.Now,
 	}
 }
 
 func (h addExampleRequestHandler) Handle(ctx context.Context, command AddExampleRequest) (*string, error) {
+	now := h.now().UTC()
 	line := example.Line{
-		ID:   h.idProvider.NewID(),
-		Data: command.Data,
+		ID:      h.idProvider.NewID(),
+		Created: now,
+		Data:    command.Data,
 	}
 
 	err := h.repo.Write(ctx, line)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", err.Error(), ErrSystem)
+		return nil, serviceError(ctx, err)
 	}
 
+	appendAudit(ctx, h.audit, []example.AuditRecord{auditRecord(ctx, example.AuditCreate, now, nil, &line)})
+
 	id := line.ID.String()
 
 	return &id, nil
 }
+
+func serviceError(ctx context.Context, err error) error {
+	if errors.Is(err, example.ErrUnavailable) {
+		return fmt.Errorf("%s: %w", err.Error(), ErrUnavailable)
+	}
+
+	if errors.Is(err, example.ErrDuplicate) {
+		return fmt.Errorf("%s: %w", err.Error(), ErrDuplicate)
+	}
+
+	if errors.Is(err, example.ErrTimeout) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s: %w", err.Error(), ErrTimeout)
+	}
+
+	return fmt.Errorf("%s: %w", err.Error(), ErrSystem)
+}
@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const (
+	MaxBatchSize int = 1000
+
+	ErrBatchSize ServiceError = "batch too large"
+)
+
+type AddExamplesRequest struct {
+	Data []string
+}
+
+// AddExampleResult holds either the identifier of the new line or the error
+// that prevented it from being written.
+type AddExampleResult struct {
+	ID  string
+	Err error
+}
+
+type CreateLinesRequestHandler interface {
+	Handle(ctx context.Context, command AddExamplesRequest) ([]AddExampleResult, error)
+}
+
+type addExamplesRequestHandler struct {
+	repo       example.LineRepository
+	idProvider example.IdentityProvider
+	audit      example.AuditLog
+	now        func() time.Time
+}
+
+func NewAddExamplesRequestHandler(repo example.LineRepository, idProvider example.IdentityProvider, audit example.AuditLog) CreateLinesRequestHandler {
+	return addExamplesRequestHandler{
+		repo:       repo,
+		idProvider: idProvider,
+		audit:      audit,
+		now:        time.Now,
+	}
+}
+
+func (h addExamplesRequestHandler) Handle(ctx context.Context, command AddExamplesRequest) ([]AddExampleResult, error) {
+	if len(command.Data) > MaxBatchSize {
+		return nil, fmt.Errorf("%d lines over %d: %w", len(command.Data), MaxBatchSize, ErrBatchSize)
+	}
+
+	if len(command.Data) == 0 {
+		return []AddExampleResult{}, nil
+	}
+
+	at := h.now().UTC()
+
+	lines := make([]example.Line, len(command.Data))
+	for i, data := range command.Data {
+		lines[i] = example.Line{
+			ID:      h.idProvider.NewID(),
+			Created: at,
+			Data:    data,
+		}
+	}
+
+	errs := batchErrors(h.repo.WriteMany(ctx, lines), len(lines))
+
+	results := make([]AddExampleResult, len(lines))
+	records := make([]example.AuditRecord, 0, len(lines))
+	for i := range lines {
+		if errs[i] != nil {
+			results[i].Err = serviceError(ctx, errs[i])
+		} else {
+			results[i].ID = lines[i].ID.String()
+			records = append(records, auditRecord(ctx, example.AuditCreate, at, nil, &lines[i]))
+		}
+	}
+
+	appendAudit(ctx, h.audit, records)
+
+	return results, nil
+}
+
+// batchErrors fails every line of a batch when the repository does not return
+// one error per line, since the lines missing one cannot be told written.
+func batchErrors(errs []error, lines int) []error {
+	if len(errs) == lines {
+		return errs
+	}
+
+	err := fmt.Errorf("%d write errors for %d lines", len(errs), lines)
+
+	failed := make([]error, lines)
+	for i := range failed {
+		failed[i] = err
+	}
+
+	return failed
+}
@@ -0,0 +1,138 @@
+package commands
+
+import (
+	"clean-arquitecture-template/internal/domain/example"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_AddExamplesRequestHandlerHandle(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.FixedZone("", 2*60*60))
+
+	testCases := []struct {
+		name            string
+		repo            func() *example.MockRepository
+		request         AddExamplesRequest
+		auditErr        error
+		expectedResults []AddExampleResult
+		expectedErrors  []error
+		expectedError   error
+	}{
+		{
+			name: "per-item-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("WriteMany", ctx, []example.Line{
+					{ID: example.MockIdentifier("one"), Created: now.UTC(), Data: "first-line"},
+					{ID: example.MockIdentifier("one"), Created: now.UTC(), Data: "second-line"},
+					{ID: example.MockIdentifier("one"), Created: now.UTC(), Data: "third-line"},
+				}).Return([]error{nil, errors.New("some-error"), example.ErrTimeout})
+
+				return mr
+			},
+			request: AddExamplesRequest{Data: []string{"first-line", "second-line", "third-line"}},
+			expectedResults: []AddExampleResult{
+				{ID: "one"},
+				{},
+				{},
+			},
+			expectedErrors: []error{nil, ErrSystem, ErrTimeout},
+		},
+		{
+			name: "missing-errors-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("WriteMany", ctx, []example.Line{
+					{ID: example.MockIdentifier("one"), Created: now.UTC(), Data: "first-line"},
+					{ID: example.MockIdentifier("one"), Created: now.UTC(), Data: "second-line"},
+				}).Return([]error{nil})
+
+				return mr
+			},
+			request: AddExamplesRequest{Data: []string{"first-line", "second-line"}},
+			expectedResults: []AddExampleResult{
+				{},
+				{},
+			},
+			expectedErrors: []error{ErrSystem, ErrSystem},
+		},
+		{
+			name: "audit-error-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("WriteMany", ctx, []example.Line{
+					{ID: example.MockIdentifier("one"), Created: now.UTC(), Data: "first-line"},
+				}).Return([]error{nil})
+
+				return mr
+			},
+			request:         AddExamplesRequest{Data: []string{"first-line"}},
+			auditErr:        errors.New("some-error"),
+			expectedResults: []AddExampleResult{{ID: "one"}},
+			expectedErrors:  []error{nil},
+		},
+		{
+			name: "empty-case",
+			repo: func() *example.MockRepository {
+				return &example.MockRepository{}
+			},
+			request:         AddExamplesRequest{},
+			expectedResults: []AddExampleResult{},
+			expectedErrors:  []error{},
+		},
+		{
+			name: "too-large-case",
+			repo: func() *example.MockRepository {
+				return &example.MockRepository{}
+			},
+			request:       AddExamplesRequest{Data: make([]string, MaxBatchSize+1)},
+			expectedError: ErrBatchSize,
+		},
+	}
+
+	for _, c := range testCases {
+		repo := c.repo()
+		request := c.request
+		auditErr := c.auditErr
+		expectedResults := c.expectedResults
+		expectedErrors := c.expectedErrors
+		expectedError := c.expectedError
+
+		provider := &example.MockIdentityProvider{}
+		provider.On("NewID").Return(example.MockIdentifier("one"))
+
+		audit := &example.MockAuditLog{}
+		audit.On("Append", ctx, mock.Anything).Return(auditErr)
+
+		t.Run(c.name, func(t *testing.T) {
+			h := addExamplesRequestHandler{
+				repo:       repo,
+				idProvider: provider,
+				audit:      audit,
+				now:        func() time.Time { return now },
+			}
+			results, err := h.Handle(ctx, request)
+
+			assert.ErrorIs(t, err, expectedError)
+			assert.Len(t, results, len(expectedErrors))
+
+			for i := range results {
+				assert.Equal(t, expectedResults[i].ID, results[i].ID)
+				if expectedErrors[i] == nil {
+					assert.NoError(t, results[i].Err)
+				} else {
+					assert.ErrorIs(t, results[i].Err, expectedErrors[i])
+				}
+			}
+
+			repo.AssertExpectations(t)
+			repo.AssertNotCalled(t, "Write", mock.Anything, mock.Anything)
+		})
+	}
+}
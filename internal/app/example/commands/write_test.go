@@ -4,9 +4,12 @@ import (
 	"clean-arquitecture-template/internal/domain/example"
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func Test_AddExampleRequestHandlerHandle(t *testing.T) {
@@ -14,6 +17,7 @@ func Test_AddExampleRequestHandlerHandle(t *testing.T) {
 	data := "first-line"
 
 	newID := "hello"
+	now := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.FixedZone("", 2*60*60))
 
 	type fields struct {
 		repo       example.LineRepository
@@ -38,8 +42,9 @@ func Test_AddExampleRequestHandlerHandle(t *testing.T) {
 					mr := example.MockRepository{}
 
 					mr.On("Write", ctx, example.Line{
-						ID:   example.MockIdentifier(newID),
-						Data: data,
+						ID:      example.MockIdentifier(newID),
+						Created: now.UTC(),
+						Data:    data,
 					}).Return(nil)
 
 					return mr
@@ -66,8 +71,9 @@ func Test_AddExampleRequestHandlerHandle(t *testing.T) {
 					mr := example.MockRepository{}
 
 					mr.On("Write", ctx, example.Line{
-						ID:   example.MockIdentifier(newID),
-						Data: data,
+						ID:      example.MockIdentifier(newID),
+						Created: now.UTC(),
+						Data:    data,
 					}).Return(errors.New("some-error"))
 
 					return mr
@@ -87,6 +93,35 @@ func Test_AddExampleRequestHandlerHandle(t *testing.T) {
 			expectedNewID: nil,
 			expectedError: ErrSystem,
 		},
+		{
+			name: "timeout-error-case",
+			fields: fields{
+				repo: func() example.MockRepository {
+					mr := example.MockRepository{}
+
+					mr.On("Write", ctx, example.Line{
+						ID:      example.MockIdentifier(newID),
+						Created: now.UTC(),
+						Data:    data,
+					}).Return(example.ErrTimeout)
+
+					return mr
+				}(),
+				idProvider: func() example.MockIdentityProvider {
+					provider := example.MockIdentityProvider{}
+					provider.On("NewID").Return(example.MockIdentifier(newID))
+
+					return provider
+				}(),
+			},
+			args: args{
+				request: AddExampleRequest{
+					Data: "first-line",
+				},
+			},
+			expectedNewID: nil,
+			expectedError: ErrTimeout,
+		},
 	}
 
 	for _, c := range testCases {
@@ -101,11 +136,130 @@ func Test_AddExampleRequestHandlerHandle(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			ctx := context.Background()
 
-			h := NewAddExampleRequestHandler(repo, idProvider)
+			audit := &example.MockAuditLog{}
+			audit.On("Append", ctx, mock.Anything).Return(nil)
+
+			h := addExampleRequestHandler{
+				repo:       repo,
+				idProvider: idProvider,
+				audit:      audit,
+				now:        func() time.Time { return now },
+			}
 			newID, err := h.Handle(ctx, request)
 
 			assert.Equal(t, expectedNewID, newID)
 			assert.ErrorIs(t, err, expectedError)
+			mr := repo.(example.MockRepository)
+			mr.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_ServiceError(t *testing.T) {
+	expired, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	testCases := []struct {
+		name          string
+		ctx           context.Context
+		err           error
+		expectedError error
+	}{
+		{
+			name:          "system-error-case",
+			ctx:           context.Background(),
+			err:           errors.New("some-error"),
+			expectedError: ErrSystem,
+		},
+		{
+			name:          "domain-timeout-case",
+			ctx:           context.Background(),
+			err:           example.ErrTimeout,
+			expectedError: ErrTimeout,
+		},
+		{
+			name:          "domain-unavailable-case",
+			ctx:           context.Background(),
+			err:           example.ErrUnavailable,
+			expectedError: ErrUnavailable,
+		},
+		{
+			name:          "domain-duplicate-case",
+			ctx:           context.Background(),
+			err:           fmt.Errorf("%s: %w", "E11000", example.ErrDuplicate),
+			expectedError: ErrDuplicate,
+		},
+		{
+			name:          "deadline-exceeded-case",
+			ctx:           expired,
+			err:           errors.New("some-error"),
+			expectedError: ErrTimeout,
+		},
+	}
+
+	for _, c := range testCases {
+		name := c.name
+		ctx := c.ctx
+		err := c.err
+		expectedError := c.expectedError
+
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, serviceError(ctx, err), expectedError)
+		})
+	}
+}
+
+func Test_AddExampleRequestHandlerAudit(t *testing.T) {
+	now := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+	ctx := example.WithRequestID(example.WithActor(context.Background(), "alice"), "r1")
+	line := example.Line{ID: example.MockIdentifier("one"), Created: now, Data: "first-line"}
+
+	testCases := []struct {
+		name          string
+		auditErr      error
+		expectedError error
+	}{
+		{
+			name: "recorded-case",
+		},
+		{
+			name:     "audit-error-case",
+			auditErr: errors.New("some-error"),
+		},
+	}
+
+	for _, c := range testCases {
+		auditErr := c.auditErr
+		expectedError := c.expectedError
+
+		t.Run(c.name, func(t *testing.T) {
+			repo := &example.MockRepository{}
+			repo.On("Write", ctx, line).Return(nil)
+
+			provider := &example.MockIdentityProvider{}
+			provider.On("NewID").Return(example.MockIdentifier("one"))
+
+			audit := &example.MockAuditLog{}
+			audit.On("Append", ctx, []example.AuditRecord{{
+				LineID:    example.MockIdentifier("one"),
+				Action:    example.AuditCreate,
+				Actor:     "alice",
+				RequestID: "r1",
+				At:        now,
+				After:     &line,
+			}}).Return(auditErr)
+
+			h := addExampleRequestHandler{
+				repo:       repo,
+				idProvider: provider,
+				audit:      audit,
+				now:        func() time.Time { return now },
+			}
+
+			_, err := h.Handle(ctx, AddExampleRequest{Data: "first-line"})
+
+			assert.ErrorIs(t, err, expectedError)
+			audit.AssertExpectations(t)
 		})
 	}
 }
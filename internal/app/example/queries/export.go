@@ -0,0 +1,73 @@
+package queries
+
+import (
+	"context"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+type ExportExamplesRequest struct{}
+
+// ExampleCursor walks the lines of an export, deleted ones left out. Next
+// reports false once the lines are exhausted or an error happened, which Err
+// then returns.
+type ExampleCursor interface {
+	Next(ctx context.Context) bool
+	Result() GetExampleResult
+	Err() error
+	Close(ctx context.Context) error
+}
+
+type ExportExamplesRequestHandler interface {
+	Handle(ctx context.Context, req ExportExamplesRequest) (ExampleCursor, error)
+}
+
+type exportExamplesRequestHandler struct {
+	repo example.LineRepository
+}
+
+func NewExportExamplesRequestHandler(repo example.LineRepository) ExportExamplesRequestHandler {
+	return exportExamplesRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h exportExamplesRequestHandler) Handle(ctx context.Context, req ExportExamplesRequest) (ExampleCursor, error) {
+	cur, err := h.repo.Scan(ctx)
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+
+	return &exampleCursor{cursor: cur}, nil
+}
+
+type exampleCursor struct {
+	cursor example.LineCursor
+	err    error
+}
+
+func (ec *exampleCursor) Next(ctx context.Context) bool {
+	for ec.cursor.Next(ctx) {
+		if !ec.cursor.Line().Deleted() {
+			return true
+		}
+	}
+
+	if err := ec.cursor.Err(); err != nil {
+		ec.err = serviceError(ctx, err)
+	}
+
+	return false
+}
+
+func (ec *exampleCursor) Result() GetExampleResult {
+	return newResult(ec.cursor.Line())
+}
+
+func (ec *exampleCursor) Err() error {
+	return ec.err
+}
+
+func (ec *exampleCursor) Close(ctx context.Context) error {
+	return ec.cursor.Close(ctx)
+}
@@ -0,0 +1,99 @@
+package queries
+
+import (
+	"clean-arquitecture-template/internal/domain/example"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExportExamplesRequestHandlerHandle(t *testing.T) {
+	ctx := context.Background()
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name            string
+		repo            func(cursor *example.MockCursor) *example.MockRepository
+		cursor          *example.MockCursor
+		expectedResults []GetExampleResult
+		expectedErr     error
+		expectedScanErr error
+	}{
+		{
+			name: "lines-case",
+			repo: func(cursor *example.MockCursor) *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Scan", ctx).Return(cursor, nil)
+
+				return mr
+			},
+			cursor: &example.MockCursor{Lines: []example.Line{
+				{ID: example.MockIdentifier("one"), Created: tstamp, Data: "first-line"},
+				{ID: example.MockIdentifier("deleted"), Created: tstamp, Data: "deleted-line", DeletedAt: tstamp},
+				{ID: example.MockIdentifier("two"), Created: tstamp, Data: "second-line"},
+			}},
+			expectedResults: []GetExampleResult{
+				{ID: "one", CreatedAt: tstamp, Data: "first-line"},
+				{ID: "two", CreatedAt: tstamp, Data: "second-line"},
+			},
+		},
+		{
+			name: "cursor-error-case",
+			repo: func(cursor *example.MockCursor) *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Scan", ctx).Return(cursor, nil)
+
+				return mr
+			},
+			cursor: &example.MockCursor{
+				Lines: []example.Line{{ID: example.MockIdentifier("one"), Created: tstamp, Data: "first-line"}},
+				Error: example.ErrTimeout,
+			},
+			expectedResults: []GetExampleResult{{ID: "one", CreatedAt: tstamp, Data: "first-line"}},
+			expectedErr:     ErrTimeout,
+		},
+		{
+			name: "scan-error-case",
+			repo: func(cursor *example.MockCursor) *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Scan", ctx).Return((*example.MockCursor)(nil), errors.New("some-error"))
+
+				return mr
+			},
+			expectedScanErr: ErrSystem,
+		},
+	}
+
+	for _, c := range testCases {
+		cursor := c.cursor
+		repo := c.repo(cursor)
+		expectedResults := c.expectedResults
+		expectedErr := c.expectedErr
+		expectedScanErr := c.expectedScanErr
+
+		t.Run(c.name, func(t *testing.T) {
+			results, err := NewExportExamplesRequestHandler(repo).Handle(ctx, ExportExamplesRequest{})
+			if expectedScanErr != nil {
+				assert.ErrorIs(t, err, expectedScanErr)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			var got []GetExampleResult
+			for results.Next(ctx) {
+				got = append(got, results.Result())
+			}
+
+			assert.Equal(t, expectedResults, got)
+			assert.ErrorIs(t, results.Err(), expectedErr)
+
+			assert.NoError(t, results.Close(ctx))
+			assert.True(t, cursor.Closed())
+			repo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+type GetHistoryRequest struct {
+	ID string
+}
+
+// HistoryRecord is one change of a line. Before is nil for a creation.
+type HistoryRecord struct {
+	Action    string
+	Actor     string
+	RequestID string
+	At        time.Time
+	Before    *GetExampleResult
+	After     *GetExampleResult
+}
+
+type GetHistoryRequestHandler interface {
+	Handle(ctx context.Context, req GetHistoryRequest) ([]HistoryRecord, error)
+}
+
+type getHistoryRequestHandler struct {
+	audit      example.AuditLog
+	idProvider example.IdentityProvider
+}
+
+func NewGetHistoryRequestHandler(audit example.AuditLog, idProvider example.IdentityProvider) GetHistoryRequestHandler {
+	return getHistoryRequestHandler{
+		audit:      audit,
+		idProvider: idProvider,
+	}
+}
+
+// Handle returns the changes of the line, oldest first. A line changed
+// before the audit trail existed has none.
+func (h getHistoryRequestHandler) Handle(ctx context.Context, req GetHistoryRequest) ([]HistoryRecord, error) {
+	id, err := h.idProvider.ParseID(req.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrInvalidID)
+	}
+
+	records, err := h.audit.History(ctx, id)
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+
+	history := make([]HistoryRecord, len(records))
+	for i, record := range records {
+		history[i] = HistoryRecord{
+			Action:    string(record.Action),
+			Actor:     record.Actor,
+			RequestID: record.RequestID,
+			At:        record.At,
+			Before:    optionalResult(record.Before),
+			After:     optionalResult(record.After),
+		}
+	}
+
+	return history, nil
+}
+
+func optionalResult(line *example.Line) *GetExampleResult {
+	if line == nil {
+		return nil
+	}
+
+	result := newResult(*line)
+
+	return &result
+}
@@ -0,0 +1,103 @@
+package queries
+
+import (
+	"clean-arquitecture-template/internal/domain/example"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetHistoryRequestHandlerHandle(t *testing.T) {
+	ctx := context.Background()
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+	id := example.MockIdentifier("one")
+
+	created := &example.Line{ID: id, Created: tstamp, Data: "first-line"}
+	deleted := &example.Line{ID: id, Created: tstamp, Data: "first-line", DeletedAt: tstamp.Add(time.Hour)}
+
+	testCases := []struct {
+		name           string
+		audit          func() *example.MockAuditLog
+		parseErr       error
+		expectedResult []HistoryRecord
+		expectedError  error
+	}{
+		{
+			name: "records-case",
+			audit: func() *example.MockAuditLog {
+				ma := &example.MockAuditLog{}
+				ma.On("History", ctx, id).Return([]example.AuditRecord{
+					{LineID: id, Action: example.AuditCreate, Actor: "alice", RequestID: "r1", At: tstamp, After: created},
+					{LineID: id, Action: example.AuditDelete, Actor: "bob", RequestID: "r2", At: tstamp.Add(time.Hour), Before: created, After: deleted},
+				}, nil)
+
+				return ma
+			},
+			expectedResult: []HistoryRecord{
+				{
+					Action:    "create",
+					Actor:     "alice",
+					RequestID: "r1",
+					At:        tstamp,
+					After:     &GetExampleResult{ID: "one", CreatedAt: tstamp, Data: "first-line"},
+				},
+				{
+					Action:    "delete",
+					Actor:     "bob",
+					RequestID: "r2",
+					At:        tstamp.Add(time.Hour),
+					Before:    &GetExampleResult{ID: "one", CreatedAt: tstamp, Data: "first-line"},
+					After:     &GetExampleResult{ID: "one", CreatedAt: tstamp, Data: "first-line", DeletedAt: tstamp.Add(time.Hour)},
+				},
+			},
+		},
+		{
+			name: "no-records-case",
+			audit: func() *example.MockAuditLog {
+				ma := &example.MockAuditLog{}
+				ma.On("History", ctx, id).Return([]example.AuditRecord{}, nil)
+
+				return ma
+			},
+			expectedResult: []HistoryRecord{},
+		},
+		{
+			name: "invalid-id-case",
+			audit: func() *example.MockAuditLog {
+				return &example.MockAuditLog{}
+			},
+			parseErr:      errors.New("invalid-id"),
+			expectedError: ErrInvalidID,
+		},
+		{
+			name: "audit-log-error-case",
+			audit: func() *example.MockAuditLog {
+				ma := &example.MockAuditLog{}
+				ma.On("History", ctx, id).Return([]example.AuditRecord(nil), example.ErrTimeout)
+
+				return ma
+			},
+			expectedError: ErrTimeout,
+		},
+	}
+
+	for _, c := range testCases {
+		audit := c.audit()
+		parseErr := c.parseErr
+		expectedResult := c.expectedResult
+		expectedError := c.expectedError
+
+		t.Run(c.name, func(t *testing.T) {
+			provider := &example.MockIdentityProvider{}
+			provider.On("ParseID", "one").Return(id, parseErr)
+
+			result, err := NewGetHistoryRequestHandler(audit, provider).Handle(ctx, GetHistoryRequest{ID: "one"})
+
+			assert.Equal(t, expectedResult, result)
+			assert.ErrorIs(t, err, expectedError)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const (
+	DefaultListLimit int = 100
+	MaxListLimit     int = 1000
+
+	SortAscending  string = "asc"
+	SortDescending string = "desc"
+
+	ErrListQuery ServiceError = "invalid list query"
+)
+
+// ListExamplesRequest selects the lines created from CreatedAfter, included,
+// up to CreatedBefore, excluded, whose data starts with DataPrefix. Sort is
+// SortAscending, the default, or SortDescending, and a zero Limit means
+// DefaultListLimit. Deleted lines are left out unless IncludeDeleted is set.
+type ListExamplesRequest struct {
+	CreatedAfter   time.Time
+	CreatedBefore  time.Time
+	DataPrefix     string
+	Sort           string
+	Limit          int
+	IncludeDeleted bool
+}
+
+type ListExamplesRequestHandler interface {
+	Handle(ctx context.Context, req ListExamplesRequest) ([]GetExampleResult, error)
+}
+
+type listExamplesRequestHandler struct {
+	repo example.LineRepository
+}
+
+func NewListExamplesRequestHandler(repo example.LineRepository) ListExamplesRequestHandler {
+	return listExamplesRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h listExamplesRequestHandler) Handle(ctx context.Context, req ListExamplesRequest) ([]GetExampleResult, error) {
+	spec, err := specification(req)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := h.repo.Find(ctx, spec)
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+
+	results := make([]GetExampleResult, len(lines))
+	for i, line := range lines {
+		results[i] = newResult(line)
+	}
+
+	return results, nil
+}
+
+func specification(req ListExamplesRequest) (example.LineSpecification, error) {
+	spec := example.LineSpecification{
+		CreatedAfter:   req.CreatedAfter,
+		CreatedBefore:  req.CreatedBefore,
+		DataPrefix:     req.DataPrefix,
+		Limit:          req.Limit,
+		IncludeDeleted: req.IncludeDeleted,
+	}
+
+	if !req.CreatedAfter.IsZero() && !req.CreatedBefore.IsZero() && !req.CreatedAfter.Before(req.CreatedBefore) {
+		return spec, fmt.Errorf("created after %s is not before %s: %w", req.CreatedAfter, req.CreatedBefore, ErrListQuery)
+	}
+
+	switch req.Sort {
+	case "", SortAscending:
+		spec.Sort = example.SortAscending
+	case SortDescending:
+		spec.Sort = example.SortDescending
+	default:
+		return spec, fmt.Errorf("unknown sort %q: %w", req.Sort, ErrListQuery)
+	}
+
+	if req.Limit < 0 || req.Limit > MaxListLimit {
+		return spec, fmt.Errorf("limit %d out of range: %w", req.Limit, ErrListQuery)
+	}
+
+	if req.Limit == 0 {
+		spec.Limit = DefaultListLimit
+	}
+
+	return spec, nil
+}
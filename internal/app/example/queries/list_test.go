@@ -0,0 +1,126 @@
+package queries
+
+import (
+	"clean-arquitecture-template/internal/domain/example"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ListExamplesRequestHandlerHandle(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2018, time.September, 16, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name            string
+		repo            func() *example.MockRepository
+		request         ListExamplesRequest
+		expectedResults []GetExampleResult
+		expectedError   error
+	}{
+		{
+			name: "defaults-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Find", ctx, example.LineSpecification{Limit: DefaultListLimit}).Return([]example.Line{
+					{ID: example.MockIdentifier("one"), Created: day, Data: "first-line"},
+				}, nil)
+
+				return mr
+			},
+			request:         ListExamplesRequest{},
+			expectedResults: []GetExampleResult{{ID: "one", CreatedAt: day, Data: "first-line"}},
+		},
+		{
+			name: "full-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Find", ctx, example.LineSpecification{
+					CreatedAfter:   day,
+					CreatedBefore:  day.Add(24 * time.Hour),
+					DataPrefix:     "first",
+					Sort:           example.SortDescending,
+					Limit:          MaxListLimit,
+					IncludeDeleted: true,
+				}).Return([]example.Line{
+					{ID: example.MockIdentifier("one"), Created: day, Data: "first-line", DeletedAt: day},
+				}, nil)
+
+				return mr
+			},
+			request: ListExamplesRequest{
+				CreatedAfter:   day,
+				CreatedBefore:  day.Add(24 * time.Hour),
+				DataPrefix:     "first",
+				Sort:           SortDescending,
+				Limit:          MaxListLimit,
+				IncludeDeleted: true,
+			},
+			expectedResults: []GetExampleResult{{ID: "one", CreatedAt: day, Data: "first-line", DeletedAt: day}},
+		},
+		{
+			name: "inverted-range-case",
+			repo: func() *example.MockRepository {
+				return &example.MockRepository{}
+			},
+			request:       ListExamplesRequest{CreatedAfter: day, CreatedBefore: day},
+			expectedError: ErrListQuery,
+		},
+		{
+			name: "unknown-sort-case",
+			repo: func() *example.MockRepository {
+				return &example.MockRepository{}
+			},
+			request:       ListExamplesRequest{Sort: "sideways"},
+			expectedError: ErrListQuery,
+		},
+		{
+			name: "limit-too-large-case",
+			repo: func() *example.MockRepository {
+				return &example.MockRepository{}
+			},
+			request:       ListExamplesRequest{Limit: MaxListLimit + 1},
+			expectedError: ErrListQuery,
+		},
+		{
+			name: "repository-timeout-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Find", ctx, example.LineSpecification{Limit: DefaultListLimit}).Return([]example.Line(nil), example.ErrTimeout)
+
+				return mr
+			},
+			request:       ListExamplesRequest{},
+			expectedError: ErrTimeout,
+		},
+		{
+			name: "repository-error-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Find", ctx, example.LineSpecification{Limit: DefaultListLimit}).Return([]example.Line(nil), errors.New("some-error"))
+
+				return mr
+			},
+			request:       ListExamplesRequest{},
+			expectedError: ErrSystem,
+		},
+	}
+
+	for _, c := range testCases {
+		repo := c.repo()
+		request := c.request
+		expectedResults := c.expectedResults
+		expectedError := c.expectedError
+
+		t.Run(c.name, func(t *testing.T) {
+			results, err := NewListExamplesRequestHandler(repo).Handle(ctx, request)
+
+			assert.Equal(t, expectedResults, results)
+			assert.ErrorIs(t, err, expectedError)
+			repo.AssertExpectations(t)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package queries
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,8 +10,11 @@ import (
 )
 
 const (
-	ErrSystem    ServiceError = "system error"
-	ErrInvalidID ServiceError = "invalid id parameter"
+	ErrSystem      ServiceError = "system error"
+	ErrTimeout     ServiceError = "timeout error"
+	ErrUnavailable ServiceError = "service unavailable"
+	ErrInvalidID   ServiceError = "invalid id parameter"
+	ErrNotFound    ServiceError = "line not found"
 )
 
 type ServiceError string
@@ -20,14 +24,27 @@ func (se ServiceError) Error() string {
 }
 
 // Read(context.Context, Identifier) (*Line, error)
+// Deleted lines are not found unless IncludeDeleted is set.
 type GetExampleRequest struct {
-	ID string
+	ID             string
+	IncludeDeleted bool
 }
 
+// GetExampleResult has a zero DeletedAt unless the line is deleted.
 type GetExampleResult struct {
 	ID        string
 	Data      string
 	CreatedAt time.Time
+	DeletedAt time.Time
+}
+
+func newResult(line example.Line) GetExampleResult {
+	return GetExampleResult{
+		ID:        line.ID.String(),
+		CreatedAt: line.Created,
+		Data:      line.Data,
+		DeletedAt: line.DeletedAt,
+	}
 }
 
 type GetExampleRequestHandler interface {
@@ -54,12 +71,26 @@ func (h getExampleRequestHandler) Handle(ctx context.Context, req GetExampleRequ
 
 	line, err := h.repo.Read(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", err.Error(), ErrSystem)
+		return nil, serviceError(ctx, err)
 	}
 
-	return &GetExampleResult{
-		ID:        line.ID.String(),
-		CreatedAt: line.Created,
-		Data:      line.Data,
-	}, nil
+	if line == nil || (line.Deleted() && !req.IncludeDeleted) {
+		return nil, ErrNotFound
+	}
+
+	result := newResult(*line)
+
+	return &result, nil
+}
+
+func serviceError(ctx context.Context, err error) error {
+	if errors.Is(err, example.ErrUnavailable) {
+		return fmt.Errorf("%s: %w", err.Error(), ErrUnavailable)
+	}
+
+	if errors.Is(err, example.ErrTimeout) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s: %w", err.Error(), ErrTimeout)
+	}
+
+	return fmt.Errorf("%s: %w", err.Error(), ErrSystem)
 }
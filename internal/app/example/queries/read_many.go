@@ -0,0 +1,87 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const (
+	MaxBatchSize int = 1000
+
+	ErrBatchSize ServiceError = "batch too large"
+)
+
+type GetExamplesRequest struct {
+	IDs            []string
+	IncludeDeleted bool
+}
+
+// GetExamplesResult holds either the line read for ID or the error that
+// prevented it from being read.
+type GetExamplesResult struct {
+	ID   string
+	Line *GetExampleResult
+	Err  error
+}
+
+type GetExamplesRequestHandler interface {
+	Handle(ctx context.Context, req GetExamplesRequest) ([]GetExamplesResult, error)
+}
+
+type getExamplesRequestHandler struct {
+	repo       example.LineRepository
+	idProvider example.IdentityProvider
+}
+
+func NewGetExamplesRequestHandler(repo example.LineRepository, idProvider example.IdentityProvider) GetExamplesRequestHandler {
+	return getExamplesRequestHandler{
+		repo:       repo,
+		idProvider: idProvider,
+	}
+}
+
+func (h getExamplesRequestHandler) Handle(ctx context.Context, req GetExamplesRequest) ([]GetExamplesResult, error) {
+	if len(req.IDs) > MaxBatchSize {
+		return nil, fmt.Errorf("%d ids over %d: %w", len(req.IDs), MaxBatchSize, ErrBatchSize)
+	}
+
+	results := make([]GetExamplesResult, len(req.IDs))
+	ids := make([]example.Identifier, 0, len(req.IDs))
+	positions := make([]int, 0, len(req.IDs))
+
+	for i, key := range req.IDs {
+		results[i].ID = key
+
+		id, err := h.idProvider.ParseID(key)
+		if err != nil {
+			results[i].Err = fmt.Errorf("%s: %w", err.Error(), ErrInvalidID)
+			continue
+		}
+
+		ids = append(ids, id)
+		positions = append(positions, i)
+	}
+
+	if len(ids) == 0 {
+		return results, nil
+	}
+
+	lines, err := h.repo.ReadMany(ctx, ids)
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+
+	for j, i := range positions {
+		if j >= len(lines) || lines[j] == nil || (lines[j].Deleted() && !req.IncludeDeleted) {
+			results[i].Err = ErrNotFound
+			continue
+		}
+
+		result := newResult(*lines[j])
+		results[i].Line = &result
+	}
+
+	return results, nil
+}
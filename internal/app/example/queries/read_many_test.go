@@ -0,0 +1,139 @@
+package queries
+
+import (
+	"clean-arquitecture-template/internal/domain/example"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetExamplesRequestHandlerHandle(t *testing.T) {
+	ctx := context.Background()
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name            string
+		repo            func() *example.MockRepository
+		request         GetExamplesRequest
+		expectedResults []GetExamplesResult
+		expectedErrors  []error
+		expectedError   error
+	}{
+		{
+			name: "per-item-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("ReadMany", ctx, []example.Identifier{
+					example.MockIdentifier("one"),
+					example.MockIdentifier("two"),
+				}).Return([]*example.Line{
+					{ID: example.MockIdentifier("one"), Created: tstamp, Data: "first-line"},
+					nil,
+				}, nil)
+
+				return mr
+			},
+			request: GetExamplesRequest{IDs: []string{"one", "bad", "two"}},
+			expectedResults: []GetExamplesResult{
+				{ID: "one", Line: &GetExampleResult{ID: "one", CreatedAt: tstamp, Data: "first-line"}},
+				{ID: "bad"},
+				{ID: "two"},
+			},
+			expectedErrors: []error{nil, ErrInvalidID, ErrNotFound},
+		},
+		{
+			name: "deleted-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("ReadMany", ctx, []example.Identifier{example.MockIdentifier("one")}).Return([]*example.Line{
+					{ID: example.MockIdentifier("one"), Created: tstamp, Data: "first-line", DeletedAt: tstamp},
+				}, nil)
+
+				return mr
+			},
+			request:         GetExamplesRequest{IDs: []string{"one"}},
+			expectedResults: []GetExamplesResult{{ID: "one"}},
+			expectedErrors:  []error{ErrNotFound},
+		},
+		{
+			name: "include-deleted-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("ReadMany", ctx, []example.Identifier{example.MockIdentifier("one")}).Return([]*example.Line{
+					{ID: example.MockIdentifier("one"), Created: tstamp, Data: "first-line", DeletedAt: tstamp},
+				}, nil)
+
+				return mr
+			},
+			request: GetExamplesRequest{IDs: []string{"one"}, IncludeDeleted: true},
+			expectedResults: []GetExamplesResult{
+				{ID: "one", Line: &GetExampleResult{ID: "one", CreatedAt: tstamp, Data: "first-line", DeletedAt: tstamp}},
+			},
+			expectedErrors: []error{nil},
+		},
+		{
+			name: "only-invalid-ids-case",
+			repo: func() *example.MockRepository {
+				return &example.MockRepository{}
+			},
+			request:         GetExamplesRequest{IDs: []string{"bad"}},
+			expectedResults: []GetExamplesResult{{ID: "bad"}},
+			expectedErrors:  []error{ErrInvalidID},
+		},
+		{
+			name: "repository-error-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("ReadMany", ctx, []example.Identifier{example.MockIdentifier("one")}).
+					Return([]*example.Line(nil), errors.New("some-error"))
+
+				return mr
+			},
+			request:       GetExamplesRequest{IDs: []string{"one"}},
+			expectedError: ErrSystem,
+		},
+		{
+			name: "too-large-case",
+			repo: func() *example.MockRepository {
+				return &example.MockRepository{}
+			},
+			request:       GetExamplesRequest{IDs: make([]string, MaxBatchSize+1)},
+			expectedError: ErrBatchSize,
+		},
+	}
+
+	for _, c := range testCases {
+		repo := c.repo()
+		request := c.request
+		expectedResults := c.expectedResults
+		expectedErrors := c.expectedErrors
+		expectedError := c.expectedError
+
+		provider := &example.MockIdentityProvider{}
+		provider.On("ParseID", "bad").Return(example.MockIdentifier(""), errors.New("bad id"))
+		provider.On("ParseID", "one").Return(example.MockIdentifier("one"), nil)
+		provider.On("ParseID", "two").Return(example.MockIdentifier("two"), nil)
+
+		t.Run(c.name, func(t *testing.T) {
+			results, err := NewGetExamplesRequestHandler(repo, provider).Handle(ctx, request)
+
+			assert.ErrorIs(t, err, expectedError)
+			assert.Len(t, results, len(expectedErrors))
+
+			for i := range results {
+				assert.Equal(t, expectedResults[i].ID, results[i].ID)
+				assert.Equal(t, expectedResults[i].Line, results[i].Line)
+				if expectedErrors[i] == nil {
+					assert.NoError(t, results[i].Err)
+				} else {
+					assert.ErrorIs(t, results[i].Err, expectedErrors[i])
+				}
+			}
+
+			repo.AssertExpectations(t)
+		})
+	}
+}
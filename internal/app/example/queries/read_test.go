@@ -26,6 +26,19 @@ func Test_NewGetExampleRequestHandlerHandle(t *testing.T) {
 		req GetExampleRequest
 	}
 
+	deletedRepo := func() example.MockRepository {
+		mr := example.MockRepository{}
+
+		mr.On("Read", ctx, example.MockIdentifier(newID)).Return(&example.Line{
+			ID:        example.MockIdentifier(newID),
+			Created:   tstamp,
+			Data:      data,
+			DeletedAt: tstamp.Add(time.Hour),
+		}, nil)
+
+		return mr
+	}
+
 	testCases := []struct {
 		testName       string
 		fields         fields
@@ -77,6 +90,75 @@ func Test_NewGetExampleRequestHandlerHandle(t *testing.T) {
 			},
 			expectedError: ErrSystem,
 		},
+		{
+			testName: "timeout-error-case",
+			fields: fields{
+				repo: func() example.MockRepository {
+					mr := example.MockRepository{}
+					mr.On("Read", ctx, example.MockIdentifier(newID)).Return(&example.Line{}, example.ErrTimeout)
+					return mr
+				}(),
+
+				provider: func() example.MockIdentityProvider {
+					idProvider := example.MockIdentityProvider{}
+					idProvider.On("ParseID", newID).Return(example.MockIdentifier(newID), nil)
+
+					return idProvider
+				}(),
+			},
+			args: args{
+				req: GetExampleRequest{
+					ID: newID,
+				},
+			},
+			expectedError: ErrTimeout,
+		},
+		{
+			testName: "unavailable-error-case",
+			fields: fields{
+				repo: func() example.MockRepository {
+					mr := example.MockRepository{}
+					mr.On("Read", ctx, example.MockIdentifier(newID)).Return(&example.Line{}, example.ErrUnavailable)
+					return mr
+				}(),
+
+				provider: func() example.MockIdentityProvider {
+					idProvider := example.MockIdentityProvider{}
+					idProvider.On("ParseID", newID).Return(example.MockIdentifier(newID), nil)
+
+					return idProvider
+				}(),
+			},
+			args: args{
+				req: GetExampleRequest{
+					ID: newID,
+				},
+			},
+			expectedError: ErrUnavailable,
+		},
+		{
+			testName: "not-found-case",
+			fields: fields{
+				repo: func() example.MockRepository {
+					mr := example.MockRepository{}
+					mr.On("Read", ctx, example.MockIdentifier(newID)).Return((*example.Line)(nil), nil)
+					return mr
+				}(),
+
+				provider: func() example.MockIdentityProvider {
+					idProvider := example.MockIdentityProvider{}
+					idProvider.On("ParseID", newID).Return(example.MockIdentifier(newID), nil)
+
+					return idProvider
+				}(),
+			},
+			args: args{
+				req: GetExampleRequest{
+					ID: newID,
+				},
+			},
+			expectedError: ErrNotFound,
+		},
 		{
 			testName: "success-case",
 			fields: fields{
@@ -110,6 +192,48 @@ func Test_NewGetExampleRequestHandlerHandle(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		{
+			testName: "deleted-case",
+			fields: fields{
+				repo: deletedRepo(),
+				provider: func() example.MockIdentityProvider {
+					idProvider := example.MockIdentityProvider{}
+					idProvider.On("ParseID", newID).Return(example.MockIdentifier(newID), nil)
+
+					return idProvider
+				}(),
+			},
+			args: args{
+				req: GetExampleRequest{
+					ID: newID,
+				},
+			},
+			expectedError: ErrNotFound,
+		},
+		{
+			testName: "include-deleted-case",
+			fields: fields{
+				repo: deletedRepo(),
+				provider: func() example.MockIdentityProvider {
+					idProvider := example.MockIdentityProvider{}
+					idProvider.On("ParseID", newID).Return(example.MockIdentifier(newID), nil)
+
+					return idProvider
+				}(),
+			},
+			args: args{
+				req: GetExampleRequest{
+					ID:             newID,
+					IncludeDeleted: true,
+				},
+			},
+			expectedResult: &GetExampleResult{
+				ID:        newID,
+				Data:      data,
+				CreatedAt: tstamp,
+				DeletedAt: tstamp.Add(time.Hour),
+			},
+		},
 	}
 
 	for _, c := range testCases {
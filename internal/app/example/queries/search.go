@@ -0,0 +1,172 @@
+package queries
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const (
+	DefaultSearchLimit int = 20
+	MaxSearchLimit     int = 100
+
+	// highlightContext is the number of words kept around every match of a
+	// snippet, and maxHighlights the number of snippets per hit.
+	highlightContext int = 5
+	maxHighlights    int = 3
+
+	highlightStart string = "<em>"
+	highlightEnd   string = "</em>"
+
+	ErrSearchQuery ServiceError = "invalid search query"
+)
+
+// SearchExamplesRequest asks for the page of Limit hits after the first
+// Offset ones. A zero Limit means DefaultSearchLimit. Deleted lines are left
+// out unless IncludeDeleted is set.
+type SearchExamplesRequest struct {
+	Query          string
+	Offset         int
+	Limit          int
+	Highlight      bool
+	IncludeDeleted bool
+}
+
+// SearchExampleHit holds, when asked, the snippets of the data around the
+// query terms, these wrapped in <em> tags. The data of the snippets is HTML
+// escaped, the tags are the only markup in them.
+type SearchExampleHit struct {
+	GetExampleResult
+	Score      float64
+	Highlights []string
+}
+
+type SearchExamplesResult struct {
+	Hits   []SearchExampleHit
+	Total  int64
+	Offset int
+	Limit  int
+}
+
+type SearchExamplesRequestHandler interface {
+	Handle(ctx context.Context, req SearchExamplesRequest) (*SearchExamplesResult, error)
+}
+
+type searchExamplesRequestHandler struct {
+	searcher example.Searcher
+}
+
+func NewSearchExamplesRequestHandler(searcher example.Searcher) SearchExamplesRequestHandler {
+	return searchExamplesRequestHandler{
+		searcher: searcher,
+	}
+}
+
+func (h searchExamplesRequestHandler) Handle(ctx context.Context, req SearchExamplesRequest) (*SearchExamplesResult, error) {
+	if len(example.Terms(req.Query)) == 0 {
+		return nil, ErrSearchQuery
+	}
+
+	if req.Offset < 0 || req.Limit < 0 || req.Limit > MaxSearchLimit {
+		return nil, ErrSearchQuery
+	}
+
+	if req.Limit == 0 {
+		req.Limit = DefaultSearchLimit
+	}
+
+	found, err := h.searcher.Search(ctx, example.SearchQuery{
+		Text:           req.Query,
+		Offset:         req.Offset,
+		Limit:          req.Limit,
+		IncludeDeleted: req.IncludeDeleted,
+	})
+	if err != nil {
+		return nil, serviceError(ctx, err)
+	}
+
+	result := &SearchExamplesResult{
+		Hits:   make([]SearchExampleHit, len(found.Hits)),
+		Total:  found.Total,
+		Offset: req.Offset,
+		Limit:  req.Limit,
+	}
+
+	terms := example.Terms(req.Query)
+	for i, hit := range found.Hits {
+		result.Hits[i] = SearchExampleHit{
+			GetExampleResult: newResult(hit.Line),
+			Score:            hit.Score,
+		}
+
+		if req.Highlight {
+			result.Hits[i].Highlights = highlight(hit.Line.Data, terms)
+		}
+	}
+
+	return result, nil
+}
+
+// highlight cuts data into snippets around the words matching terms. Matches
+// close enough to share their context end up in the same snippet.
+func highlight(data string, terms []string) []string {
+	wanted := make(map[string]struct{}, len(terms))
+	for _, term := range terms {
+		wanted[term] = struct{}{}
+	}
+
+	tokens := example.Tokenize(data)
+
+	var snippets []string
+	next := 0
+	for i := 0; i < len(tokens) && len(snippets) < maxHighlights; i++ {
+		if _, match := wanted[tokens[i].Term]; !match {
+			continue
+		}
+
+		first := i - highlightContext
+		if first < next {
+			first = next
+		}
+
+		last := i + highlightContext
+		for j := i + 1; j < len(tokens) && j <= last; j++ {
+			if _, match := wanted[tokens[j].Term]; match {
+				last = j + highlightContext
+			}
+		}
+
+		if last >= len(tokens) {
+			last = len(tokens) - 1
+		}
+
+		snippets = append(snippets, snippet(data, tokens[first:last+1], wanted))
+		i = last
+		next = last + 1
+	}
+
+	return snippets
+}
+
+func snippet(data string, tokens []example.Token, wanted map[string]struct{}) string {
+	var b strings.Builder
+
+	position := tokens[0].Start
+	for _, token := range tokens {
+		if _, match := wanted[token.Term]; !match {
+			continue
+		}
+
+		b.WriteString(html.EscapeString(data[position:token.Start]))
+		b.WriteString(highlightStart)
+		b.WriteString(html.EscapeString(data[token.Start:token.End]))
+		b.WriteString(highlightEnd)
+		position = token.End
+	}
+
+	b.WriteString(html.EscapeString(data[position:tokens[len(tokens)-1].End]))
+
+	return b.String()
+}
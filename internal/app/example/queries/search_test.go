@@ -0,0 +1,187 @@
+package queries
+
+import (
+	"clean-arquitecture-template/internal/domain/example"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_SearchExamplesRequestHandlerHandle(t *testing.T) {
+	ctx := context.Background()
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+
+	found := example.SearchResult{
+		Hits: []example.SearchHit{
+			{Line: example.Line{ID: example.MockIdentifier("one"), Created: tstamp, Data: "the quick brown fox"}, Score: 2},
+		},
+		Total: 7,
+	}
+
+	testCases := []struct {
+		name           string
+		searcher       func() *example.MockSearcher
+		request        SearchExamplesRequest
+		expectedResult *SearchExamplesResult
+		expectedError  error
+	}{
+		{
+			name: "default-limit-case",
+			searcher: func() *example.MockSearcher {
+				ms := &example.MockSearcher{}
+				ms.On("Search", ctx, example.SearchQuery{Text: "fox", Offset: 3, Limit: DefaultSearchLimit}).Return(found, nil)
+
+				return ms
+			},
+			request: SearchExamplesRequest{Query: "fox", Offset: 3},
+			expectedResult: &SearchExamplesResult{
+				Hits: []SearchExampleHit{
+					{GetExampleResult: GetExampleResult{ID: "one", CreatedAt: tstamp, Data: "the quick brown fox"}, Score: 2},
+				},
+				Total:  7,
+				Offset: 3,
+				Limit:  DefaultSearchLimit,
+			},
+		},
+		{
+			name: "highlight-case",
+			searcher: func() *example.MockSearcher {
+				ms := &example.MockSearcher{}
+				ms.On("Search", ctx, example.SearchQuery{Text: "Fox quick", Limit: 1, IncludeDeleted: true}).Return(found, nil)
+
+				return ms
+			},
+			request: SearchExamplesRequest{Query: "Fox quick", Limit: 1, Highlight: true, IncludeDeleted: true},
+			expectedResult: &SearchExamplesResult{
+				Hits: []SearchExampleHit{
+					{
+						GetExampleResult: GetExampleResult{ID: "one", CreatedAt: tstamp, Data: "the quick brown fox"},
+						Score:            2,
+						Highlights:       []string{"the <em>quick</em> brown <em>fox</em>"},
+					},
+				},
+				Total: 7,
+				Limit: 1,
+			},
+		},
+		{
+			name: "empty-query-case",
+			searcher: func() *example.MockSearcher {
+				return &example.MockSearcher{}
+			},
+			request:       SearchExamplesRequest{Query: " ?! "},
+			expectedError: ErrSearchQuery,
+		},
+		{
+			name: "limit-too-large-case",
+			searcher: func() *example.MockSearcher {
+				return &example.MockSearcher{}
+			},
+			request:       SearchExamplesRequest{Query: "fox", Limit: MaxSearchLimit + 1},
+			expectedError: ErrSearchQuery,
+		},
+		{
+			name: "negative-offset-case",
+			searcher: func() *example.MockSearcher {
+				return &example.MockSearcher{}
+			},
+			request:       SearchExamplesRequest{Query: "fox", Offset: -1},
+			expectedError: ErrSearchQuery,
+		},
+		{
+			name: "searcher-error-case",
+			searcher: func() *example.MockSearcher {
+				ms := &example.MockSearcher{}
+				ms.On("Search", ctx, mock.Anything).Return(example.SearchResult{}, errors.New("some-error"))
+
+				return ms
+			},
+			request:       SearchExamplesRequest{Query: "fox"},
+			expectedError: ErrSystem,
+		},
+	}
+
+	for _, c := range testCases {
+		searcher := c.searcher()
+		request := c.request
+		expectedResult := c.expectedResult
+		expectedError := c.expectedError
+
+		t.Run(c.name, func(t *testing.T) {
+			result, err := NewSearchExamplesRequestHandler(searcher).Handle(ctx, request)
+
+			assert.Equal(t, expectedResult, result)
+			assert.ErrorIs(t, err, expectedError)
+			searcher.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_Highlight(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     string
+		terms    []string
+		expected []string
+	}{
+		{
+			name:     "no-match-case",
+			data:     "the quick brown fox",
+			terms:    []string{"dog"},
+			expected: nil,
+		},
+		{
+			name:     "case-insensitive-case",
+			data:     "The FOX, and the fox.",
+			terms:    []string{"fox"},
+			expected: []string{"The <em>FOX</em>, and the <em>fox</em>"},
+		},
+		{
+			name:     "escaped-case",
+			data:     `<script>alert("fox")</script> & <b>fox</b>`,
+			terms:    []string{"fox", "script"},
+			expected: []string{"<em>script</em>&gt;alert(&#34;<em>fox</em>&#34;)&lt;/<em>script</em>&gt; &amp; &lt;b&gt;<em>fox</em>&lt;/b"},
+		},
+		{
+			name:  "context-case",
+			data:  "one two three four five six seven match eight nine ten eleven twelve thirteen",
+			terms: []string{"match"},
+			expected: []string{
+				"three four five six seven <em>match</em> eight nine ten eleven twelve",
+			},
+		},
+		{
+			name:  "separate-snippets-case",
+			data:  "fox a b c d e f g h i j k l fox",
+			terms: []string{"fox"},
+			expected: []string{
+				"<em>fox</em> a b c d e",
+				"h i j k l <em>fox</em>",
+			},
+		},
+		{
+			name:  "max-snippets-case",
+			data:  "x 1 2 3 4 5 6 7 8 9 10 11 x 1 2 3 4 5 6 7 8 9 10 11 x 1 2 3 4 5 6 7 8 9 10 11 x",
+			terms: []string{"x"},
+			expected: []string{
+				"<em>x</em> 1 2 3 4 5",
+				"7 8 9 10 11 <em>x</em> 1 2 3 4 5",
+				"7 8 9 10 11 <em>x</em> 1 2 3 4 5",
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		data := c.data
+		terms := c.terms
+		expected := c.expected
+
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, expected, highlight(data, terms))
+		})
+	}
+}
@@ -7,11 +7,21 @@ import (
 )
 
 type Commands struct {
-	CreateExampleHandler commands.CreateLineRequestHandler
+	CreateExampleHandler  commands.CreateLineRequestHandler
+	CreateExamplesHandler commands.CreateLinesRequestHandler
+	ImportExamplesHandler commands.ImportLinesRequestHandler
+	DeleteExampleHandler  commands.DeleteLineRequestHandler
+	RestoreExampleHandler commands.RestoreLineRequestHandler
+	PurgeExamplesHandler  commands.PurgeLinesRequestHandler
 }
 
 type Queries struct {
-	ReadExampleHandler queries.GetExampleRequestHandler
+	ReadExampleHandler    queries.GetExampleRequestHandler
+	ReadExamplesHandler   queries.GetExamplesRequestHandler
+	ExportExamplesHandler queries.ExportExamplesRequestHandler
+	SearchExamplesHandler queries.SearchExamplesRequestHandler
+	ListExamplesHandler   queries.ListExamplesRequestHandler
+	HistoryExampleHandler queries.GetHistoryRequestHandler
 }
 
 type ExampleServices struct {
@@ -23,14 +33,24 @@ type Services struct {
 	ExampleService ExampleServices
 }
 
-func NewServices(examRepo example.LineRepository, idProdiver example.IdentityProvider) Services {
+func NewServices(examRepo example.LineRepository, idProdiver example.IdentityProvider, searcher example.Searcher, audit example.AuditLog) Services {
 	return Services{
 		ExampleService: ExampleServices{
 			Commands: Commands{
-				CreateExampleHandler: commands.NewAddExampleRequestHandler(examRepo, idProdiver),
+				CreateExampleHandler:  commands.NewAddExampleRequestHandler(examRepo, idProdiver, audit),
+				CreateExamplesHandler: commands.NewAddExamplesRequestHandler(examRepo, idProdiver, audit),
+				ImportExamplesHandler: commands.NewImportExamplesRequestHandler(examRepo, idProdiver, audit),
+				DeleteExampleHandler:  commands.NewDeleteExampleRequestHandler(examRepo, idProdiver, audit),
+				RestoreExampleHandler: commands.NewRestoreExampleRequestHandler(examRepo, idProdiver, audit),
+				PurgeExamplesHandler:  commands.NewPurgeExamplesRequestHandler(examRepo, audit),
 			},
 			Queries: Queries{
-				ReadExampleHandler: queries.NewGetExampleRequestHandler(examRepo, idProdiver),
+				ReadExampleHandler:    queries.NewGetExampleRequestHandler(examRepo, idProdiver),
+				ReadExamplesHandler:   queries.NewGetExamplesRequestHandler(examRepo, idProdiver),
+				ExportExamplesHandler: queries.NewExportExamplesRequestHandler(examRepo),
+				SearchExamplesHandler: queries.NewSearchExamplesRequestHandler(searcher),
+				ListExamplesHandler:   queries.NewListExamplesRequestHandler(examRepo),
+				HistoryExampleHandler: queries.NewGetHistoryRequestHandler(audit, idProdiver),
 			},
 		},
 	}
@@ -0,0 +1,65 @@
+package example
+
+import (
+	"context"
+	"time"
+)
+
+/**************************************************
+* This file constains the audit trail recording   *
+* every change made to the lines.                 *
+***************************************************/
+
+type AuditAction string
+
+const (
+	AuditCreate  AuditAction = "create"
+	AuditDelete  AuditAction = "delete"
+	AuditRestore AuditAction = "restore"
+	AuditPurge   AuditAction = "purge"
+)
+
+// AuditRecord is one change of a line, made by Actor while serving the
+// request RequestID. Before is nil for a creation, After for a purge.
+type AuditRecord struct {
+	LineID    Identifier
+	Action    AuditAction
+	Actor     string
+	RequestID string
+	At        time.Time
+	Before    *Line
+	After     *Line
+}
+
+// AuditLog keeps the audit records, which are never changed once appended.
+// History returns the records of a line, oldest first.
+type AuditLog interface {
+	Append(context.Context, []AuditRecord) error
+	History(context.Context, Identifier) ([]AuditRecord, error)
+}
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	requestIDKey
+)
+
+// WithActor tells who is behind the changes made with ctx.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey).(string)
+	return actor
+}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
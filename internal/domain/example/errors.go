@@ -0,0 +1,18 @@
+package example
+
+/**************************************************
+* This file constains the errors every repository *
+* implementation reports in the same way.         *
+***************************************************/
+
+const (
+	ErrTimeout     DomainError = "operation timed out"
+	ErrUnavailable DomainError = "storage unavailable"
+	ErrDuplicate   DomainError = "line already exists"
+)
+
+type DomainError string
+
+func (de DomainError) Error() string {
+	return string(de)
+}
@@ -13,8 +13,34 @@ type Identifier interface {
 	String() string
 }
 
+// ID is the text form of an Identifier. Identifiers of different adapters
+// are different types, IDs are plain strings that compare with == whichever
+// adapter made them, so they can be map keys, stored and transported. An ID
+// is an Identifier itself, for the adapters that store identifiers of any
+// kind as text.
+type ID string
+
+func (id ID) String() string {
+	return string(id)
+}
+
+func IDOf(id Identifier) ID {
+	if id == nil {
+		return ""
+	}
+
+	return ID(id.String())
+}
+
+// Line is soft deleted when DeletedAt is set, and stays stored until it is
+// purged.
 type Line struct {
-	ID      Identifier
-	Created time.Time
-	Data    string
+	ID        Identifier
+	Created   time.Time
+	Data      string
+	DeletedAt time.Time
+}
+
+func (l Line) Deleted() bool {
+	return !l.DeletedAt.IsZero()
 }
@@ -2,6 +2,7 @@ package example
 
 import (
 	"context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -20,6 +21,78 @@ func (mr MockRepository) Read(ctx context.Context, id Identifier) (*Line, error)
 	return args.Get(0).(*Line), args.Error(1)
 }
 
+func (mr MockRepository) WriteMany(ctx context.Context, lines []Line) []error {
+	args := mr.Called(ctx, lines)
+	return args.Get(0).([]error)
+}
+
+func (mr MockRepository) ReadMany(ctx context.Context, ids []Identifier) ([]*Line, error) {
+	args := mr.Called(ctx, ids)
+	return args.Get(0).([]*Line), args.Error(1)
+}
+
+func (mr MockRepository) Scan(ctx context.Context) (LineCursor, error) {
+	args := mr.Called(ctx)
+	return args.Get(0).(LineCursor), args.Error(1)
+}
+
+func (mr MockRepository) Find(ctx context.Context, spec LineSpecification) ([]Line, error) {
+	args := mr.Called(ctx, spec)
+	return args.Get(0).([]Line), args.Error(1)
+}
+
+func (mr MockRepository) Delete(ctx context.Context, id Identifier, at time.Time) (bool, error) {
+	args := mr.Called(ctx, id, at)
+	return args.Bool(0), args.Error(1)
+}
+
+func (mr MockRepository) Restore(ctx context.Context, id Identifier) (bool, error) {
+	args := mr.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (mr MockRepository) Purge(ctx context.Context, before time.Time) ([]Line, error) {
+	args := mr.Called(ctx, before)
+	purged, _ := args.Get(0).([]Line)
+	return purged, args.Error(1)
+}
+
+// MockCursor walks Lines and then reports Error.
+type MockCursor struct {
+	Lines []Line
+	Error error
+
+	position int
+	closed   bool
+}
+
+func (mc *MockCursor) Next(ctx context.Context) bool {
+	if mc.position >= len(mc.Lines) {
+		return false
+	}
+
+	mc.position++
+
+	return true
+}
+
+func (mc *MockCursor) Line() Line {
+	return mc.Lines[mc.position-1]
+}
+
+func (mc *MockCursor) Err() error {
+	return mc.Error
+}
+
+func (mc *MockCursor) Close(ctx context.Context) error {
+	mc.closed = true
+	return nil
+}
+
+func (mc *MockCursor) Closed() bool {
+	return mc.closed
+}
+
 type MockIdentityProvider struct {
 	mock.Mock
 }
@@ -41,3 +114,26 @@ func (mip MockIdentityProvider) ParseID(ids string) (Identifier, error) {
 
 	return args.Get(0).(Identifier), args.Error(1)
 }
+
+type MockSearcher struct {
+	mock.Mock
+}
+
+func (ms *MockSearcher) Search(ctx context.Context, query SearchQuery) (SearchResult, error) {
+	args := ms.Called(ctx, query)
+	return args.Get(0).(SearchResult), args.Error(1)
+}
+
+type MockAuditLog struct {
+	mock.Mock
+}
+
+func (ma *MockAuditLog) Append(ctx context.Context, records []AuditRecord) error {
+	args := ma.Called(ctx, records)
+	return args.Error(0)
+}
+
+func (ma *MockAuditLog) History(ctx context.Context, id Identifier) ([]AuditRecord, error) {
+	args := ma.Called(ctx, id)
+	return args.Get(0).([]AuditRecord), args.Error(1)
+}
@@ -1,7 +1,10 @@
 // name of the package
 package example
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 /**************************************************
 * This file constains domain functionality to be  *
@@ -14,7 +17,33 @@ type IdentityProvider interface {
 	ParseID(string) (Identifier, error)
 }
 
+// LineRepository stores the lines. WriteMany reports one error per line,
+// nil for the lines written, and ReadMany one line per identifier, nil for
+// the lines not found, both in the order they were given. Scan walks every
+// stored line, deleted ones included, without loading them all at once. Find
+// returns the lines selected by a specification.
+//
+// Delete marks a line as deleted at the given time, a line already deleted
+// keeps its time, and Restore unmarks it. Both report whether the line
+// exists. Purge removes for good the lines deleted before the given time and
+// returns them.
 type LineRepository interface {
 	Write(context.Context, Line) error
 	Read(context.Context, Identifier) (*Line, error)
+	WriteMany(context.Context, []Line) []error
+	ReadMany(context.Context, []Identifier) ([]*Line, error)
+	Scan(context.Context) (LineCursor, error)
+	Find(context.Context, LineSpecification) ([]Line, error)
+	Delete(context.Context, Identifier, time.Time) (bool, error)
+	Restore(context.Context, Identifier) (bool, error)
+	Purge(context.Context, time.Time) ([]Line, error)
+}
+
+// LineCursor iterates over the lines of a Scan. Next reports false once the
+// lines are exhausted or an error happened, which Err then returns.
+type LineCursor interface {
+	Next(context.Context) bool
+	Line() Line
+	Err() error
+	Close(context.Context) error
 }
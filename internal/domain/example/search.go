@@ -0,0 +1,143 @@
+package example
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+/**************************************************
+* This file constains the full-text search over   *
+* the data of the lines.                          *
+***************************************************/
+
+// Searcher finds the lines whose data contains any of the terms of the query,
+// best matches first. The terms between double quotes form a phrase, and the
+// lines must also contain every phrase, its terms one right after the other.
+// Total counts every match, not only the returned page. Deleted lines are
+// left out unless IncludeDeleted is set.
+type Searcher interface {
+	Search(context.Context, SearchQuery) (SearchResult, error)
+}
+
+type SearchQuery struct {
+	Text           string
+	Offset         int
+	Limit          int
+	IncludeDeleted bool
+}
+
+type SearchHit struct {
+	Line  Line
+	Score float64
+}
+
+type SearchResult struct {
+	Hits  []SearchHit
+	Total int64
+}
+
+// Token is a word of a text, lower cased, with the byte range it spans in
+// the text.
+type Token struct {
+	Term  string
+	Start int
+	End   int
+}
+
+// Tokenize splits text into the runs of letters and digits, every other rune
+// is a separator.
+func Tokenize(text string) []Token {
+	var tokens []Token
+
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+
+			continue
+		}
+
+		if start >= 0 {
+			tokens = append(tokens, Token{Term: strings.ToLower(text[start:i]), Start: start, End: i})
+			start = -1
+		}
+	}
+
+	if start >= 0 {
+		tokens = append(tokens, Token{Term: strings.ToLower(text[start:]), Start: start, End: len(text)})
+	}
+
+	return tokens
+}
+
+// Terms returns the distinct terms of text, in the order they appear.
+func Terms(text string) []string {
+	seen := make(map[string]struct{})
+
+	var terms []string
+	for _, token := range Tokenize(text) {
+		if _, exists := seen[token.Term]; !exists {
+			seen[token.Term] = struct{}{}
+			terms = append(terms, token.Term)
+		}
+	}
+
+	return terms
+}
+
+// Phrases returns the parts of text between double quotes as their terms,
+// leaving out the parts without any. A quote left open runs to the end of the
+// text.
+func Phrases(text string) [][]string {
+	parts := strings.Split(text, `"`)
+
+	var phrases [][]string
+	for i := 1; i < len(parts); i += 2 {
+		tokens := Tokenize(parts[i])
+		if len(tokens) == 0 {
+			continue
+		}
+
+		phrase := make([]string, len(tokens))
+		for j, token := range tokens {
+			phrase[j] = token.Term
+		}
+
+		phrases = append(phrases, phrase)
+	}
+
+	return phrases
+}
+
+// ContainsPhrases reports whether the terms of every phrase follow one
+// another somewhere in tokens.
+func ContainsPhrases(tokens []Token, phrases [][]string) bool {
+	for _, phrase := range phrases {
+		if !containsPhrase(tokens, phrase) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsPhrase(tokens []Token, phrase []string) bool {
+	for start := 0; start+len(phrase) <= len(tokens); start++ {
+		matched := true
+		for i, term := range phrase {
+			if tokens[start+i].Term != term {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,66 @@
+package example
+
+import (
+	"strings"
+	"time"
+)
+
+/**************************************************
+* This file constains the specification selecting *
+* lines by creation time and data.                *
+***************************************************/
+
+type SortDirection int
+
+const (
+	SortAscending SortDirection = iota
+	SortDescending
+)
+
+// LineSpecification selects the lines created from CreatedAfter, included,
+// up to CreatedBefore, excluded, whose data starts with DataPrefix. Zero
+// bounds and an empty prefix select everything. Deleted lines are left out
+// unless IncludeDeleted is set. The lines are sorted by
+// creation time, then by identifier, and at most Limit are kept when Limit
+// is positive.
+type LineSpecification struct {
+	CreatedAfter   time.Time
+	CreatedBefore  time.Time
+	DataPrefix     string
+	Sort           SortDirection
+	Limit          int
+	IncludeDeleted bool
+}
+
+func (ls LineSpecification) IsSatisfiedBy(line Line) bool {
+	if line.Deleted() && !ls.IncludeDeleted {
+		return false
+	}
+
+	if !ls.CreatedAfter.IsZero() && line.Created.Before(ls.CreatedAfter) {
+		return false
+	}
+
+	if !ls.CreatedBefore.IsZero() && !line.Created.Before(ls.CreatedBefore) {
+		return false
+	}
+
+	return strings.HasPrefix(line.Data, ls.DataPrefix)
+}
+
+// Less orders a before b following the sort direction.
+func (ls LineSpecification) Less(a, b Line) bool {
+	if !a.Created.Equal(b.Created) {
+		if ls.Sort == SortDescending {
+			return a.Created.After(b.Created)
+		}
+
+		return a.Created.Before(b.Created)
+	}
+
+	if ls.Sort == SortDescending {
+		return a.ID.String() > b.ID.String()
+	}
+
+	return a.ID.String() < b.ID.String()
+}
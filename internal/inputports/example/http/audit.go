@@ -0,0 +1,65 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/labstack/echo/v4"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const (
+	headerActor string = "X-Actor"
+
+	adminActor       string = "admin"
+	anonymousActor   string = "anonymous"
+	unverifiedPrefix string = "unverified:"
+)
+
+// auditContext puts on the request context who made the request and its
+// identifier, which the application services record with every change. The
+// identifier is the one sent by the client, or a new one, and is sent back.
+func (s Server) auditContext(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+
+		requestID := req.Header.Get(echo.HeaderXRequestID)
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+
+		actor := s.actor(c)
+
+		ctx := example.WithRequestID(example.WithActor(req.Context(), actor), requestID)
+		c.SetRequest(req.WithContext(ctx))
+		c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+
+		return next(c)
+	}
+}
+
+// actor is the admin for the requests bearing the admin token, the only
+// identity the server verifies. Any other actor is the one the client claims,
+// recorded as unverified.
+func (s Server) actor(c echo.Context) string {
+	if s.admin(c) {
+		return adminActor
+	}
+
+	claimed := c.Request().Header.Get(headerActor)
+	if claimed == "" {
+		return anonymousActor
+	}
+
+	return unverifiedPrefix + claimed
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"clean-arquitecture-template/internal/app/example/commands"
+	"clean-arquitecture-template/internal/app/example/queries"
+	"clean-arquitecture-template/internal/inputports/problem"
+)
+
+const (
+	batchWritePath string = "/batch"
+	batchReadPath  string = "/batch/read"
+
+	batchWriteRoute string = "batch-write"
+	batchReadRoute  string = "batch-read"
+)
+
+type ReadExampleRequest struct {
+	ID string `json:"id"`
+}
+
+// batchItem reports the outcome of one item of a batch, with the status the
+// item would have had as a single request.
+type batchItem struct {
+	Status int              `json:"status"`
+	Result interface{}      `json:"result,omitempty"`
+	Error  *problem.Problem `json:"error,omitempty"`
+}
+
+func failedItem(err error, instance string) batchItem {
+	p := newProblem(err, instance)
+
+	return batchItem{
+		Status: p.Status,
+		Error:  &p,
+	}
+}
+
+func (s Server) writeAppExamples(c echo.Context) error {
+	return s.writeExamples(c, v1)
+}
+
+func (s Server) writeAppExamplesV2(c echo.Context) error {
+	return s.writeExamples(c, v2)
+}
+
+func (s Server) writeExamples(c echo.Context, v version) error {
+	var data []WriteExampleRequest
+
+	response := NewResponser(c)
+	if err := decodeBody(c, &data); err != nil {
+		return response.WithError(err).Response()
+	}
+
+	command := commands.AddExamplesRequest{Data: make([]string, len(data))}
+	for i, item := range data {
+		command.Data[i] = item.Data
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), s.timeout(batchWriteRoute))
+	defer cancel()
+
+	results, err := s.exampleServices.ExampleService.Commands.CreateExamplesHandler.Handle(ctx, command)
+	if err != nil {
+		return response.WithError(err).Response()
+	}
+
+	items := make([]batchItem, len(results))
+	for i, result := range results {
+		if result.Err != nil {
+			items[i] = failedItem(result.Err, instance(c))
+		} else {
+			items[i] = batchItem{Status: http.StatusOK, Result: v.writeResponse(result.ID)}
+		}
+	}
+
+	return response.WithPayload(http.StatusOK, items).Response()
+}
+
+func (s Server) readAppExamples(c echo.Context) error {
+	return s.readExamples(c, v1)
+}
+
+func (s Server) readAppExamplesV2(c echo.Context) error {
+	return s.readExamples(c, v2)
+}
+
+func (s Server) readExamples(c echo.Context, v version) error {
+	var data []ReadExampleRequest
+
+	response := NewResponser(c)
+	if err := decodeBody(c, &data); err != nil {
+		return response.WithError(err).Response()
+	}
+
+	req := queries.GetExamplesRequest{IDs: make([]string, len(data))}
+	for i, item := range data {
+		req.IDs[i] = item.ID
+	}
+
+	var err error
+	if req.IncludeDeleted, err = s.includeDeleted(c); err != nil {
+		return response.WithError(err).Response()
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), s.timeout(batchReadRoute))
+	defer cancel()
+
+	results, err := s.exampleServices.ExampleService.Queries.ReadExamplesHandler.Handle(ctx, req)
+	if err != nil {
+		return response.WithError(err).Response()
+	}
+
+	items := make([]batchItem, len(results))
+	for i, result := range results {
+		if result.Err != nil {
+			items[i] = failedItem(result.Err, instance(c))
+		} else {
+			items[i] = batchItem{Status: http.StatusOK, Result: v.readResponse(result.Line)}
+		}
+	}
+
+	return response.WithPayload(http.StatusOK, items).Response()
+}
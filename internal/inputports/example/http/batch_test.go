@@ -0,0 +1,174 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	app "clean-arquitecture-template/internal/app/example"
+	"clean-arquitecture-template/internal/app/example/commands"
+	"clean-arquitecture-template/internal/app/example/queries"
+)
+
+type mockCommandCreateLinesHandler struct {
+	Handler func(context.Context, commands.AddExamplesRequest) ([]commands.AddExampleResult, error)
+}
+
+func (m mockCommandCreateLinesHandler) Handle(ctx context.Context, command commands.AddExamplesRequest) ([]commands.AddExampleResult, error) {
+	return m.Handler(ctx, command)
+}
+
+type mockCommandReadLinesHandler struct {
+	Handler func(context.Context, queries.GetExamplesRequest) ([]queries.GetExamplesResult, error)
+}
+
+func (m mockCommandReadLinesHandler) Handle(ctx context.Context, req queries.GetExamplesRequest) ([]queries.GetExamplesResult, error) {
+	return m.Handler(ctx, req)
+}
+
+func Test_Batch(t *testing.T) {
+	tstamp := time.Date(2018, time.September, 16, 10, 0, 0, 0, time.UTC)
+
+	services := app.Services{
+		ExampleService: app.ExampleServices{
+			Commands: app.Commands{
+				CreateExamplesHandler: mockCommandCreateLinesHandler{Handler: func(ctx context.Context, req commands.AddExamplesRequest) ([]commands.AddExampleResult, error) {
+					if len(req.Data) > 2 {
+						return nil, commands.ErrBatchSize
+					}
+
+					results := make([]commands.AddExampleResult, len(req.Data))
+					for i, data := range req.Data {
+						if data == "" {
+							results[i].Err = commands.ErrSystem
+						} else {
+							results[i].ID = data + "-id"
+						}
+					}
+
+					return results, nil
+				}},
+			},
+			Queries: app.Queries{
+				ReadExamplesHandler: mockCommandReadLinesHandler{Handler: func(ctx context.Context, req queries.GetExamplesRequest) ([]queries.GetExamplesResult, error) {
+					if len(req.IDs) == 0 {
+						return nil, errors.New("some-error")
+					}
+
+					results := make([]queries.GetExamplesResult, len(req.IDs))
+					for i, id := range req.IDs {
+						results[i].ID = id
+						if id == "x" {
+							results[i].Err = queries.ErrNotFound
+						} else {
+							results[i].Line = &queries.GetExampleResult{ID: id, Data: "first-line", CreatedAt: tstamp}
+						}
+					}
+
+					return results, nil
+				}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		testName         string
+		path             string
+		contentType      string
+		accept           string
+		body             string
+		expectedHTTPCode int
+		expectedResponse string
+	}{
+		{
+			testName:         "v2-write-case",
+			path:             "/v2/example/batch",
+			body:             `[{"data":"a"},{"data":""}]`,
+			expectedHTTPCode: http.StatusOK,
+			expectedResponse: "[{\"status\":200,\"result\":{\"newId\":\"a-id\"}},{\"status\":500,\"error\":{\"type\":\"/problems/system_error\",\"title\":\"System error\",\"status\":500,\"instance\":\"/v2/example/batch\",\"code\":\"system_error\"}}]\n",
+		},
+		{
+			testName:         "v1-write-case",
+			path:             "/v1/example/batch",
+			body:             `[{"data":"a"}]`,
+			expectedHTTPCode: http.StatusOK,
+			expectedResponse: "[{\"status\":200,\"result\":{\"new_id\":\"a-id\"}}]\n",
+		},
+		{
+			testName:         "csv-write-case",
+			path:             "/v2/example/batch",
+			contentType:      MIMETextCSV,
+			body:             "data\na\nb\n",
+			expectedHTTPCode: http.StatusOK,
+			expectedResponse: "[{\"status\":200,\"result\":{\"newId\":\"a-id\"}},{\"status\":200,\"result\":{\"newId\":\"b-id\"}}]\n",
+		},
+		{
+			testName:         "too-large-case",
+			path:             "/v2/example/batch",
+			body:             `[{"data":"a"},{"data":"b"},{"data":"c"}]`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedResponse: "{\"type\":\"/problems/batch_too_large\",\"title\":\"Batch too large\",\"status\":400,\"detail\":\"batch too large\",\"instance\":\"/v2/example/batch\",\"code\":\"batch_too_large\"}\n",
+		},
+		{
+			testName:         "invalid-body-case",
+			path:             "/v2/example/batch",
+			body:             `{"data":"a"}`,
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+		{
+			testName:         "v2-read-case",
+			path:             "/v2/example/batch/read",
+			body:             `[{"id":"1000"},{"id":"x"}]`,
+			expectedHTTPCode: http.StatusOK,
+			expectedResponse: "[{\"status\":200,\"result\":{\"id\":\"1000\",\"createdAt\":\"2018-09-16T10:00:00Z\",\"data\":\"first-line\"}},{\"status\":404,\"error\":{\"type\":\"/problems/not_found\",\"title\":\"Line not found\",\"status\":404,\"detail\":\"line not found\",\"instance\":\"/v2/example/batch/read\",\"code\":\"not_found\"}}]\n",
+		},
+		{
+			testName:         "csv-read-case",
+			path:             "/v2/example/batch/read",
+			accept:           MIMETextCSV,
+			body:             `[{"id":"1000"},{"id":"x"}]`,
+			expectedHTTPCode: http.StatusOK,
+			expectedResponse: "status,result,error\n200,\"{\"\"createdAt\"\":\"\"2018-09-16T10:00:00Z\"\",\"\"data\"\":\"\"first-line\"\",\"\"id\"\":\"\"1000\"\"}\",\n404,,\"{\"\"code\"\":\"\"not_found\"\",\"\"detail\"\":\"\"line not found\"\",\"\"instance\"\":\"\"/v2/example/batch/read\"\",\"\"status\"\":404,\"\"title\"\":\"\"Line not found\"\",\"\"type\"\":\"\"/problems/not_found\"\"}\"\n",
+		},
+		{
+			testName:         "read-error-case",
+			path:             "/v2/example/batch/read",
+			body:             `[]`,
+			expectedHTTPCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+
+		t.Run(c.testName, func(t *testing.T) {
+			server := NewServer(context.Background(), services, config{OpenAPI: openAPIConfig{Validate: true}})
+
+			contentType := c.contentType
+			if contentType == "" {
+				contentType = echo.MIMEApplicationJSON
+			}
+
+			req := httptest.NewRequest(http.MethodPost, c.path, strings.NewReader(c.body))
+			req.Header.Set(echo.HeaderContentType, contentType)
+			if c.accept != "" {
+				req.Header.Set(echo.HeaderAccept, c.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			server.server.ServeHTTP(rec, req)
+
+			assert.Equal(t, c.expectedHTTPCode, rec.Code)
+			if c.expectedResponse != "" {
+				assert.Equal(t, c.expectedResponse, rec.Body.String())
+			}
+		})
+	}
+}
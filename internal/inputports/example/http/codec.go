@@ -0,0 +1,391 @@
+package http
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"clean-arquitecture-template/internal/inputports/problem"
+)
+
+/**************************************************
+* This file constains the encoders and decoders   *
+* selected by the Accept and Content-Type headers *
+***************************************************/
+
+const (
+	MIMEApplicationXMsgpack  string = "application/x-msgpack"
+	MIMEApplicationXProtobuf string = "application/x-protobuf"
+	MIMETextCSV              string = "text/csv"
+
+	prettyParam  string = "pretty"
+	prettyIndent string = " "
+
+	ErrNotAcceptable        = problem.ErrNotAcceptable
+	ErrUnsupportedMediaType = problem.ErrUnsupportedMediaType
+)
+
+type codec interface {
+	contentType() string
+	encode(w io.Writer, v interface{}) error
+	decode(r io.Reader, v interface{}) error
+}
+
+// codecs maps every supported media type, aliases included, to its codec.
+var codecs = map[string]codec{
+	echo.MIMEApplicationJSON:     jsonCodec{},
+	echo.MIMEApplicationMsgpack:  msgpackCodec{},
+	MIMEApplicationXMsgpack:      msgpackCodec{},
+	echo.MIMEApplicationProtobuf: protobufCodec{},
+	MIMEApplicationXProtobuf:     protobufCodec{},
+	MIMETextCSV:                  csvCodec{},
+}
+
+type jsonCodec struct {
+	indent string
+}
+
+func (jc jsonCodec) contentType() string {
+	return echo.MIMEApplicationJSONCharsetUTF8
+}
+
+func (jc jsonCodec) encode(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	if jc.indent != "" {
+		enc.SetIndent("", jc.indent)
+	}
+
+	return enc.Encode(v)
+}
+
+func (jc jsonCodec) decode(r io.Reader, v interface{}) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+// msgpackCodec names the fields after their json tags, so every format shares
+// the same field names.
+type msgpackCodec struct{}
+
+func (mc msgpackCodec) contentType() string {
+	return echo.MIMEApplicationMsgpack
+}
+
+func (mc msgpackCodec) encode(w io.Writer, v interface{}) error {
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+
+	return enc.Encode(v)
+}
+
+func (mc msgpackCodec) decode(r io.Reader, v interface{}) error {
+	dec := msgpack.NewDecoder(r)
+	dec.SetCustomStructTag("json")
+
+	return dec.Decode(v)
+}
+
+// protobufCodec exchanges google.protobuf.Value messages holding the same
+// document as the json representation.
+type protobufCodec struct{}
+
+func (pc protobufCodec) contentType() string {
+	return echo.MIMEApplicationProtobuf
+}
+
+func (pc protobufCodec) encode(w io.Writer, v interface{}) error {
+	var generic interface{}
+	if err := convert(v, &generic); err != nil {
+		return err
+	}
+
+	value, err := structpb.NewValue(generic)
+	if err != nil {
+		return err
+	}
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+
+	return err
+}
+
+func (pc protobufCodec) decode(r io.Reader, v interface{}) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	value := &structpb.Value{}
+	if err = proto.Unmarshal(data, value); err != nil {
+		return err
+	}
+
+	return convert(value.AsInterface(), v)
+}
+
+// csvCodec writes lists as a header row, named after the json tags of the
+// items, followed by one row per item. Anything but a list is not acceptable
+// as csv.
+type csvCodec struct{}
+
+func (cc csvCodec) contentType() string {
+	return MIMETextCSV
+}
+
+func (cc csvCodec) encode(w io.Writer, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return fmt.Errorf("%T is not a list: %w", v, ErrNotAcceptable)
+	}
+
+	var rows []map[string]interface{}
+	if err := convert(v, &rows); err != nil {
+		return fmt.Errorf("%s: %w", err.Error(), ErrNotAcceptable)
+	}
+
+	header := columns(value, rows)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(header))
+		for i, column := range header {
+			record[i] = cell(row[column])
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// decode reads the rows as string fields. A single row decodes into an
+// object, any number of rows into a list.
+func (cc csvCodec) decode(r io.Reader, v interface{}) error {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		return io.EOF
+	}
+
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(records[0]))
+		for i, column := range records[0] {
+			if i < len(record) {
+				row[column] = record[i]
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	if target := reflect.ValueOf(v); target.Kind() == reflect.Pointer && target.Elem().Kind() == reflect.Slice {
+		return convert(rows, v)
+	}
+
+	if len(rows) != 1 {
+		return fmt.Errorf("expected one csv row, got %d", len(rows))
+	}
+
+	return convert(rows[0], v)
+}
+
+// columns follows the field order of the items when they are structs, the
+// ones in an interface list included, and the sorted keys of every row
+// otherwise, so the header does not depend on the omitted empty fields.
+func columns(list reflect.Value, rows []map[string]interface{}) []string {
+	t := list.Type().Elem()
+	if t.Kind() == reflect.Interface && list.Len() > 0 && list.Index(0).Elem().IsValid() {
+		t = list.Index(0).Elem().Type()
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var header []string
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			header = append(header, name)
+		}
+
+		return header
+	}
+
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		for column := range row {
+			if !seen[column] {
+				seen[column] = true
+				header = append(header, column)
+			}
+		}
+	}
+	sort.Strings(header)
+
+	return header
+}
+
+func cell(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	}
+
+	data, _ := json.Marshal(v)
+
+	return string(data)
+}
+
+// convert copies from into to through their json representation.
+func convert(from interface{}, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, to)
+}
+
+type acceptedType struct {
+	mediaType string
+	quality   float64
+}
+
+// negotiate picks the codec of the preferred media type of the Accept header
+// among the supported ones. Wildcards and a missing header select json,
+// indented with indent.
+func negotiate(header string, indent string) (codec, bool) {
+	jc := jsonCodec{indent: indent}
+
+	if strings.TrimSpace(header) == "" {
+		return jc, true
+	}
+
+	var accepted []acceptedType
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, exists := params["q"]; exists {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+
+		if quality > 0 {
+			accepted = append(accepted, acceptedType{mediaType: mediaType, quality: quality})
+		}
+	}
+
+	sort.SliceStable(accepted, func(i, j int) bool {
+		return accepted[i].quality > accepted[j].quality
+	})
+
+	for _, a := range accepted {
+		switch a.mediaType {
+		case "*/*", "application/*", echo.MIMEApplicationJSON:
+			return jc, true
+		}
+
+		if cd, exists := codecs[a.mediaType]; exists {
+			return cd, true
+		}
+	}
+
+	return nil, false
+}
+
+// bodyCodec returns the codec of the Content-Type of a request with a body.
+func bodyCodec(req *http.Request) (codec, error) {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrUnsupportedMediaType)
+	}
+
+	cd, exists := codecs[mediaType]
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", mediaType, ErrUnsupportedMediaType)
+	}
+
+	return cd, nil
+}
+
+// decodeBody decodes the request body with the codec of its Content-Type.
+// Empty bodies are left untouched.
+func decodeBody(c echo.Context, v interface{}) error {
+	req := c.Request()
+	if req.ContentLength == 0 {
+		return nil
+	}
+
+	cd, err := bodyCodec(req)
+	if err != nil {
+		return err
+	}
+
+	if err = cd.decode(req.Body, v); err != nil {
+		return fmt.Errorf("%s: %w", err.Error(), ErrInputParam)
+	}
+
+	return nil
+}
+
+func encodeBody(cd codec, v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := cd.encode(&buf, v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,323 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	app "clean-arquitecture-template/internal/app/example"
+	"clean-arquitecture-template/internal/app/example/commands"
+	"clean-arquitecture-template/internal/app/example/queries"
+)
+
+func Test_Negotiate(t *testing.T) {
+	testCases := []struct {
+		testName      string
+		accept        string
+		expectedCodec codec
+		expectedFound bool
+	}{
+		{
+			testName:      "missing-header-case",
+			accept:        "",
+			expectedCodec: jsonCodec{},
+			expectedFound: true,
+		},
+		{
+			testName:      "wildcard-case",
+			accept:        "*/*",
+			expectedCodec: jsonCodec{},
+			expectedFound: true,
+		},
+		{
+			testName:      "json-case",
+			accept:        "application/json",
+			expectedCodec: jsonCodec{},
+			expectedFound: true,
+		},
+		{
+			testName:      "msgpack-case",
+			accept:        "application/msgpack",
+			expectedCodec: msgpackCodec{},
+			expectedFound: true,
+		},
+		{
+			testName:      "msgpack-alias-case",
+			accept:        "application/x-msgpack",
+			expectedCodec: msgpackCodec{},
+			expectedFound: true,
+		},
+		{
+			testName:      "protobuf-case",
+			accept:        "application/x-protobuf",
+			expectedCodec: protobufCodec{},
+			expectedFound: true,
+		},
+		{
+			testName:      "csv-case",
+			accept:        "text/csv",
+			expectedCodec: csvCodec{},
+			expectedFound: true,
+		},
+		{
+			testName:      "unsupported-skipped-case",
+			accept:        "text/html, application/msgpack;q=0.5",
+			expectedCodec: msgpackCodec{},
+			expectedFound: true,
+		},
+		{
+			testName:      "quality-case",
+			accept:        "application/json;q=0.1, application/msgpack",
+			expectedCodec: msgpackCodec{},
+			expectedFound: true,
+		},
+		{
+			testName:      "unsupported-case",
+			accept:        "text/html",
+			expectedFound: false,
+		},
+		{
+			testName:      "refused-case",
+			accept:        "application/msgpack;q=0",
+			expectedFound: false,
+		},
+	}
+
+	for _, c := range testCases {
+		accept := c.accept
+		expectedCodec := c.expectedCodec
+		expectedFound := c.expectedFound
+
+		t.Run(c.testName, func(t *testing.T) {
+			cd, found := negotiate(accept, "")
+
+			assert.Equal(t, expectedFound, found)
+			assert.Equal(t, expectedCodec, cd)
+		})
+	}
+}
+
+func Test_CodecRoundTrip(t *testing.T) {
+	for mediaType, cd := range codecs {
+		cd := cd
+
+		t.Run(mediaType, func(t *testing.T) {
+			var buf bytes.Buffer
+			in := []WriteExampleRequest{{Data: "first-line"}, {Data: "second-line"}}
+
+			require.NoError(t, cd.encode(&buf, in))
+
+			var out []WriteExampleRequest
+			require.NoError(t, cd.decode(&buf, &out))
+
+			assert.Equal(t, in, out)
+		})
+	}
+}
+
+func Test_CSVCodec(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := csvCodec{}.encode(&buf, []readAppExampleResponseV2{
+		{ID: "1", CreatedAt: "2018-09-16T10:00:00Z", Data: "first, line"},
+		{ID: "2", CreatedAt: "2018-09-16T11:00:00Z", Data: "second-line"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "id,createdAt,data,deletedAt\n1,2018-09-16T10:00:00Z,\"first, line\",\n2,2018-09-16T11:00:00Z,second-line,\n", buf.String())
+
+	buf.Reset()
+	err = csvCodec{}.encode(&buf, []interface{}{
+		&readAppExampleResponseV2{ID: "1", CreatedAt: "2018-09-16T10:00:00Z", Data: "first-line"},
+		&readAppExampleResponseV2{ID: "2", CreatedAt: "2018-09-16T11:00:00Z", Data: "second-line", DeletedAt: "2018-09-16T12:00:00Z"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "id,createdAt,data,deletedAt\n1,2018-09-16T10:00:00Z,first-line,\n2,2018-09-16T11:00:00Z,second-line,2018-09-16T12:00:00Z\n", buf.String())
+
+	buf.Reset()
+	err = csvCodec{}.encode(&buf, []map[string]interface{}{{"b": 1}, {"a": "x"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "a,b\n,1\nx,\n", buf.String())
+
+	err = csvCodec{}.encode(&buf, readAppExampleResponseV2{ID: "1"})
+	assert.ErrorIs(t, err, ErrNotAcceptable)
+
+	var req WriteExampleRequest
+	assert.NoError(t, csvCodec{}.decode(strings.NewReader("data\nfirst-line\n"), &req))
+	assert.Equal(t, WriteExampleRequest{Data: "first-line"}, req)
+
+	assert.Error(t, csvCodec{}.decode(strings.NewReader("data\nfirst-line\nsecond-line\n"), &req))
+}
+
+func Test_ContentNegotiation(t *testing.T) {
+	tstamp := time.Date(2018, time.September, 16, 10, 0, 0, 0, time.UTC)
+
+	var received string
+
+	services := app.Services{
+		ExampleService: app.ExampleServices{
+			Commands: app.Commands{
+				CreateExampleHandler: mockCommandCreateLineHandler{Handler: func(ctx context.Context, req commands.AddExampleRequest) (*string, error) {
+					received = req.Data
+					newID := "1234567890"
+					return &newID, nil
+				}},
+			},
+			Queries: app.Queries{
+				ReadExampleHandler: mockCommandReadLineHandler{Handler: func(ctx context.Context, req queries.GetExampleRequest) (*queries.GetExampleResult, error) {
+					return &queries.GetExampleResult{ID: req.ID, Data: "first-line", CreatedAt: tstamp}, nil
+				}},
+			},
+		},
+	}
+
+	encoded := func(cd codec, v interface{}) string {
+		body, err := encodeBody(cd, v)
+		require.NoError(t, err)
+
+		return string(body)
+	}
+
+	testCases := []struct {
+		testName            string
+		method              string
+		path                string
+		contentType         string
+		accept              string
+		body                string
+		expectedHTTPCode    int
+		expectedContentType string
+		expectedResponse    string
+		expectedReceived    string
+	}{
+		{
+			testName:            "compact-json-case",
+			method:              http.MethodGet,
+			path:                "/v2/example/read/1000",
+			expectedHTTPCode:    http.StatusOK,
+			expectedContentType: echo.MIMEApplicationJSONCharsetUTF8,
+			expectedResponse:    "{\"id\":\"1000\",\"createdAt\":\"2018-09-16T10:00:00Z\",\"data\":\"first-line\"}\n",
+		},
+		{
+			testName:            "pretty-json-case",
+			method:              http.MethodGet,
+			path:                "/v2/example/read/1000?pretty",
+			expectedHTTPCode:    http.StatusOK,
+			expectedContentType: echo.MIMEApplicationJSONCharsetUTF8,
+			expectedResponse:    "{\n \"id\": \"1000\",\n \"createdAt\": \"2018-09-16T10:00:00Z\",\n \"data\": \"first-line\"\n}\n",
+		},
+		{
+			testName:            "msgpack-case",
+			method:              http.MethodGet,
+			path:                "/v2/example/read/1000",
+			accept:              echo.MIMEApplicationMsgpack,
+			expectedHTTPCode:    http.StatusOK,
+			expectedContentType: echo.MIMEApplicationMsgpack,
+			expectedResponse:    encoded(msgpackCodec{}, readAppExampleResponseV2{ID: "1000", CreatedAt: "2018-09-16T10:00:00Z", Data: "first-line"}),
+		},
+		{
+			testName:            "protobuf-case",
+			method:              http.MethodGet,
+			path:                "/v2/example/read/1000",
+			accept:              MIMEApplicationXProtobuf,
+			expectedHTTPCode:    http.StatusOK,
+			expectedContentType: echo.MIMEApplicationProtobuf,
+			expectedResponse:    encoded(protobufCodec{}, readAppExampleResponseV2{ID: "1000", CreatedAt: "2018-09-16T10:00:00Z", Data: "first-line"}),
+		},
+		{
+			testName:            "csv-single-line-case",
+			method:              http.MethodGet,
+			path:                "/v2/example/read/1000",
+			accept:              MIMETextCSV,
+			expectedHTTPCode:    http.StatusNotAcceptable,
+			expectedContentType: "application/problem+json",
+		},
+		{
+			testName:            "unsupported-accept-case",
+			method:              http.MethodGet,
+			path:                "/v2/example/read/1000",
+			accept:              "text/html",
+			expectedHTTPCode:    http.StatusNotAcceptable,
+			expectedContentType: "application/problem+json",
+		},
+		{
+			testName:            "msgpack-body-case",
+			method:              http.MethodPost,
+			path:                "/v2/example/write",
+			contentType:         echo.MIMEApplicationMsgpack,
+			body:                encoded(msgpackCodec{}, WriteExampleRequest{Data: "x"}),
+			expectedHTTPCode:    http.StatusOK,
+			expectedContentType: echo.MIMEApplicationJSONCharsetUTF8,
+			expectedResponse:    "{\"newId\":\"1234567890\"}\n",
+			expectedReceived:    "x",
+		},
+		{
+			testName:            "protobuf-body-case",
+			method:              http.MethodPost,
+			path:                "/v2/example/write",
+			contentType:         echo.MIMEApplicationProtobuf,
+			body:                encoded(protobufCodec{}, WriteExampleRequest{Data: "x"}),
+			expectedHTTPCode:    http.StatusOK,
+			expectedContentType: echo.MIMEApplicationJSONCharsetUTF8,
+			expectedResponse:    "{\"newId\":\"1234567890\"}\n",
+			expectedReceived:    "x",
+		},
+		{
+			testName:            "csv-body-case",
+			method:              http.MethodPost,
+			path:                "/v2/example/write",
+			contentType:         MIMETextCSV,
+			body:                "data\nx\n",
+			expectedHTTPCode:    http.StatusOK,
+			expectedContentType: echo.MIMEApplicationJSONCharsetUTF8,
+			expectedResponse:    "{\"newId\":\"1234567890\"}\n",
+			expectedReceived:    "x",
+		},
+		{
+			testName:            "unsupported-body-case",
+			method:              http.MethodPost,
+			path:                "/v2/example/write",
+			contentType:         "text/html",
+			body:                "<p>x</p>",
+			expectedHTTPCode:    http.StatusUnsupportedMediaType,
+			expectedContentType: "application/problem+json",
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+
+		t.Run(c.testName, func(t *testing.T) {
+			received = ""
+			server := NewServer(context.Background(), services, config{OpenAPI: openAPIConfig{Validate: true}})
+
+			req := httptest.NewRequest(c.method, c.path, strings.NewReader(c.body))
+			if c.contentType != "" {
+				req.Header.Set(echo.HeaderContentType, c.contentType)
+			}
+			if c.accept != "" {
+				req.Header.Set(echo.HeaderAccept, c.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			server.server.ServeHTTP(rec, req)
+
+			assert.Equal(t, c.expectedHTTPCode, rec.Code)
+			assert.Equal(t, c.expectedContentType, rec.Header().Get(echo.HeaderContentType))
+			if c.expectedResponse != "" {
+				assert.Equal(t, c.expectedResponse, rec.Body.String())
+			}
+			assert.Equal(t, c.expectedReceived, received)
+		})
+	}
+}
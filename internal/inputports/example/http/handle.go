@@ -2,9 +2,7 @@ package http
 
 import (
 	"context"
-	"fmt"
 	"net/http"
-	"time"
 
 	"github.com/labstack/echo/v4"
 
@@ -21,26 +19,32 @@ type WriteExampleResponse struct {
 }
 
 func (s Server) writeAppExample(c echo.Context) error {
+	return s.writeExample(c, v1)
+}
+
+func (s Server) writeAppExampleV2(c echo.Context) error {
+	return s.writeExample(c, v2)
+}
+
+func (s Server) writeExample(c echo.Context, v version) error {
 	data := new(WriteExampleRequest)
 
 	response := NewResponser(c)
 
-	if err := c.Bind(data); err != nil {
-		response.WithHTTPError(fmt.Errorf("%s: %w", err.Error(), ErrInputParam))
+	if err := decodeBody(c, data); err != nil {
+		response.WithError(err)
 	} else {
-		ctx, cancel := context.WithTimeout(s.ctx, time.Second)
+		ctx, cancel := context.WithTimeout(c.Request().Context(), s.timeout(writeRoute))
 		defer cancel()
 
 		id, err := s.exampleServices.ExampleService.Commands.CreateExampleHandler.Handle(ctx, commands.AddExampleRequest{Data: data.Data})
 		if err != nil {
-			response.WithJSONError(err)
+			response.WithError(err)
 		} else {
 			if id == nil {
-				response.WithJSONError(commands.ErrSystem)
+				response.WithError(commands.ErrSystem)
 			} else {
-				response.WithJSON(http.StatusOK, WriteExampleResponse{
-					NewID: *id,
-				})
+				response.WithPayload(http.StatusOK, v.writeResponse(*id))
 			}
 		}
 	}
@@ -52,23 +56,34 @@ type readAppExampleResponse struct {
 	ID        string `json:"id"`
 	CreatedAT string `json:"created_at"`
 	Data      string `json:"data"`
+	DeletedAT string `json:"deleted_at,omitempty"`
 }
 
 func (s Server) readAppExample(c echo.Context) error {
-	idParam := c.Param("id")
+	return s.readExample(c, v1)
+}
 
-	ctx, cancel := context.WithTimeout(s.ctx, time.Second)
-	defer cancel()
+func (s Server) readAppExampleV2(c echo.Context) error {
+	return s.readExample(c, v2)
+}
+
+func (s Server) readExample(c echo.Context, v version) error {
+	req := queries.GetExampleRequest{ID: c.Param("id")}
 
 	response := NewResponser(c)
-	if result, err := s.exampleServices.ExampleService.Queries.ReadExampleHandler.Handle(ctx, queries.GetExampleRequest{ID: idParam}); err != nil {
-		response.WithJSONError(err)
+
+	var err error
+	if req.IncludeDeleted, err = s.includeDeleted(c); err != nil {
+		return response.WithError(err).Response()
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), s.timeout(readRoute))
+	defer cancel()
+
+	if result, err := s.exampleServices.ExampleService.Queries.ReadExampleHandler.Handle(ctx, req); err != nil {
+		response.WithError(err)
 	} else {
-		response.WithJSON(http.StatusOK, readAppExampleResponse{
-			ID:        result.ID,
-			CreatedAT: result.CreatedAt.String(),
-			Data:      result.Data,
-		})
+		response.WithPayload(http.StatusOK, v.readResponse(result))
 	}
 
 	return response.Response()
@@ -40,7 +40,7 @@ func Test_WriteAppExampleHandler(t *testing.T) {
 				return nil, commands.ErrSystem
 			}},
 			expectedHTTPCode: 500,
-			expectedResponse: "\"system error\"\n",
+			expectedResponse: "{\"type\":\"/problems/system_error\",\"title\":\"System error\",\"status\":500,\"instance\":\"/example/write\",\"code\":\"system_error\"}\n",
 		},
 		{
 			testName: "unknown-error-test",
@@ -48,7 +48,7 @@ func Test_WriteAppExampleHandler(t *testing.T) {
 				return nil, nil
 			}},
 			expectedHTTPCode: 500,
-			expectedResponse: "\"system error\"\n",
+			expectedResponse: "{\"type\":\"/problems/system_error\",\"title\":\"System error\",\"status\":500,\"instance\":\"/example/write\",\"code\":\"system_error\"}\n",
 		},
 
 		{
@@ -59,17 +59,17 @@ func Test_WriteAppExampleHandler(t *testing.T) {
 			}},
 			requestData:      `{"data":"x"}`,
 			expectedHTTPCode: 200,
-			expectedResponse: "{\n \"new_id\": \"1234567890\"\n}\n",
+			expectedResponse: "{\"new_id\":\"1234567890\"}\n",
 		},
 		{
-			testName: "success-test",
+			testName: "bad-request-test",
 			handler: mockCommandCreateLineHandler{Handler: func(ctx context.Context, req commands.AddExampleRequest) (*string, error) {
 				newID := "1234567890"
 				return &newID, nil
 			}},
 			requestData:      `{"data":"x"`,
 			expectedHTTPCode: 400,
-			expectedResponse: "code=400, message=code=400, message=unexpected EOF, internal=unexpected EOF: input param error",
+			expectedResponse: "{\"type\":\"/problems/invalid_input\",\"title\":\"Invalid input\",\"status\":400,\"detail\":\"unexpected EOF: input param error\",\"instance\":\"/example/write\",\"code\":\"invalid_input\"}\n",
 		},
 	}
 
@@ -101,17 +101,9 @@ func Test_WriteAppExampleHandler(t *testing.T) {
 			code := rec.Code
 			response := rec.Body.String()
 
-			if err != nil {
-				he, ok := err.(*echo.HTTPError)
-				if ok {
-					assert.Equal(t, expectedCode, he.Code)
-					assert.Equal(t, expectedResponse, err.Error())
-				}
-			} else {
-				assert.NoError(t, err)
-				assert.Equal(t, expectedCode, code)
-				assert.Equal(t, expectedResponse, response)
-			}
+			assert.NoError(t, err)
+			assert.Equal(t, expectedCode, code)
+			assert.Equal(t, expectedResponse, response)
 		})
 	}
 }
@@ -141,12 +133,32 @@ func Test_ReadAppExample(t *testing.T) {
 		{
 			testName: "system-error-test",
 			handler: mockCommandReadLineHandler{Handler: func(ctx context.Context, req queries.GetExampleRequest) (*queries.GetExampleResult, error) {
-				return nil, commands.ErrSystem
+				return nil, queries.ErrSystem
 			}},
 			requestPath:      "/example/read/1000",
 			requestValue:     "1000",
 			expectedHTTPCode: 500,
-			expectedResponse: "\"system error\"\n",
+			expectedResponse: "{\"type\":\"/problems/system_error\",\"title\":\"System error\",\"status\":500,\"instance\":\"/example/read/1000\",\"code\":\"system_error\"}\n",
+		},
+		{
+			testName: "not-found-test",
+			handler: mockCommandReadLineHandler{Handler: func(ctx context.Context, req queries.GetExampleRequest) (*queries.GetExampleResult, error) {
+				return nil, queries.ErrNotFound
+			}},
+			requestPath:      "/example/read/1000",
+			requestValue:     "1000",
+			expectedHTTPCode: 404,
+			expectedResponse: "{\"type\":\"/problems/not_found\",\"title\":\"Line not found\",\"status\":404,\"detail\":\"line not found\",\"instance\":\"/example/read/1000\",\"code\":\"not_found\"}\n",
+		},
+		{
+			testName: "timeout-error-test",
+			handler: mockCommandReadLineHandler{Handler: func(ctx context.Context, req queries.GetExampleRequest) (*queries.GetExampleResult, error) {
+				return nil, queries.ErrTimeout
+			}},
+			requestPath:      "/example/read/1000",
+			requestValue:     "1000",
+			expectedHTTPCode: 504,
+			expectedResponse: "{\"type\":\"/problems/timeout\",\"title\":\"Operation timed out\",\"status\":504,\"instance\":\"/example/read/1000\",\"code\":\"timeout\"}\n",
 		},
 		{
 			testName: "success-test",
@@ -160,7 +172,7 @@ func Test_ReadAppExample(t *testing.T) {
 			requestPath:      "/example/read/1000",
 			requestValue:     "1000",
 			expectedHTTPCode: 200,
-			expectedResponse: "{\n \"id\": \"1000\",\n \"created_at\": \"2018-09-16 10:00:00 +0000 UTC\",\n \"data\": \"first-line\"\n}\n",
+			expectedResponse: "{\"id\":\"1000\",\"created_at\":\"2018-09-16 10:00:00 +0000 UTC\",\"data\":\"first-line\"}\n",
 		},
 	}
 
@@ -204,3 +216,179 @@ func Test_ReadAppExample(t *testing.T) {
 		})
 	}
 }
+
+type requestCtxKey struct{}
+
+func Test_HandlerContext(t *testing.T) {
+	const routeTimeout time.Duration = 50 * time.Millisecond
+
+	var (
+		writeCtx context.Context
+		readCtx  context.Context
+	)
+
+	server := Server{
+		server: echo.New(),
+		timeouts: newRouteTimeouts(map[string]time.Duration{
+			writeRoute: routeTimeout,
+			readRoute:  routeTimeout,
+		}),
+		exampleServices: app.Services{
+			ExampleService: app.ExampleServices{
+				Commands: app.Commands{
+					CreateExampleHandler: mockCommandCreateLineHandler{Handler: func(ctx context.Context, req commands.AddExampleRequest) (*string, error) {
+						writeCtx = ctx
+						newID := "1234567890"
+						return &newID, nil
+					}},
+				},
+				Queries: app.Queries{
+					ReadExampleHandler: mockCommandReadLineHandler{Handler: func(ctx context.Context, req queries.GetExampleRequest) (*queries.GetExampleResult, error) {
+						readCtx = ctx
+						return &queries.GetExampleResult{ID: req.ID}, nil
+					}},
+				},
+			},
+		},
+	}
+
+	reqCtx := context.WithValue(context.Background(), requestCtxKey{}, "request")
+
+	req := httptest.NewRequest(http.MethodPost, "/example/write", strings.NewReader(`{"data":"x"}`)).WithContext(reqCtx)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	assert.NoError(t, server.writeAppExample(server.server.NewContext(req, httptest.NewRecorder())))
+
+	req = httptest.NewRequest(http.MethodGet, "/example/read/1000", nil).WithContext(reqCtx)
+	assert.NoError(t, server.readAppExample(server.server.NewContext(req, httptest.NewRecorder())))
+
+	for _, ctx := range []context.Context{writeCtx, readCtx} {
+		deadline, hasDeadline := ctx.Deadline()
+
+		assert.Equal(t, "request", ctx.Value(requestCtxKey{}))
+		assert.True(t, hasDeadline)
+		assert.WithinDuration(t, time.Now().Add(routeTimeout), deadline, routeTimeout)
+		assert.Error(t, ctx.Err())
+	}
+}
+
+func Test_ServerTimeout(t *testing.T) {
+	server := Server{
+		timeouts: newRouteTimeouts(map[string]time.Duration{
+			writeRoute: 3 * time.Second,
+			readRoute:  0,
+		}),
+	}
+
+	assert.Equal(t, 3*time.Second, server.timeout(writeRoute))
+	assert.Equal(t, defaultRouteTimeout, server.timeout(readRoute))
+	assert.Equal(t, defaultRouteTimeout, server.timeout("unknown"))
+	assert.Equal(t, defaultRouteTimeout, Server{}.timeout(writeRoute))
+}
+
+func Test_ServerApplyConfig(t *testing.T) {
+	server := Server{timeouts: newRouteTimeouts(map[string]time.Duration{writeRoute: time.Second})}
+	handlers := server
+
+	err := server.ApplyConfig(strings.NewReader(`{"timeouts": {"write": "two seconds"}}`))
+	assert.ErrorIs(t, err, ErrReadConfig)
+	assert.Equal(t, time.Second, handlers.timeout(writeRoute))
+
+	err = server.ApplyConfig(strings.NewReader(`{"timeouts": {"write": "3s"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 3*time.Second, handlers.timeout(writeRoute))
+}
+
+func Test_Versions(t *testing.T) {
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.FixedZone("", 2*60*60))
+
+	services := app.Services{
+		ExampleService: app.ExampleServices{
+			Commands: app.Commands{
+				CreateExampleHandler: mockCommandCreateLineHandler{Handler: func(ctx context.Context, req commands.AddExampleRequest) (*string, error) {
+					newID := "1234567890"
+					return &newID, nil
+				}},
+			},
+			Queries: app.Queries{
+				ReadExampleHandler: mockCommandReadLineHandler{Handler: func(ctx context.Context, req queries.GetExampleRequest) (*queries.GetExampleResult, error) {
+					return &queries.GetExampleResult{ID: req.ID, Data: "first-line", CreatedAt: tstamp}, nil
+				}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		testName            string
+		method              string
+		path                string
+		body                string
+		expectedResponse    string
+		expectedDeprecation string
+		expectedLink        string
+	}{
+		{
+			testName:            "legacy-write-case",
+			method:              http.MethodPost,
+			path:                "/example/write",
+			body:                `{"data":"x"}`,
+			expectedResponse:    "{\"new_id\":\"1234567890\"}\n",
+			expectedDeprecation: "true",
+			expectedLink:        "</v2/example>; rel=\"successor-version\"",
+		},
+		{
+			testName:            "v1-write-case",
+			method:              http.MethodPost,
+			path:                "/v1/example/write",
+			body:                `{"data":"x"}`,
+			expectedResponse:    "{\"new_id\":\"1234567890\"}\n",
+			expectedDeprecation: "true",
+			expectedLink:        "</v2/example>; rel=\"successor-version\"",
+		},
+		{
+			testName:         "v2-write-case",
+			method:           http.MethodPost,
+			path:             "/v2/example/write",
+			body:             `{"data":"x"}`,
+			expectedResponse: "{\"newId\":\"1234567890\"}\n",
+		},
+		{
+			testName:            "v1-read-case",
+			method:              http.MethodGet,
+			path:                "/v1/example/read/1000",
+			expectedResponse:    "{\"id\":\"1000\",\"created_at\":\"2018-09-16 12:00:00 +0200 +0200\",\"data\":\"first-line\"}\n",
+			expectedDeprecation: "true",
+			expectedLink:        "</v2/example>; rel=\"successor-version\"",
+		},
+		{
+			testName:         "v2-read-case",
+			method:           http.MethodGet,
+			path:             "/v2/example/read/1000",
+			expectedResponse: "{\"id\":\"1000\",\"createdAt\":\"2018-09-16T10:00:00Z\",\"data\":\"first-line\"}\n",
+		},
+	}
+
+	for _, c := range testCases {
+		testName := c.testName
+		method := c.method
+		path := c.path
+		body := c.body
+		expectedResponse := c.expectedResponse
+		expectedDeprecation := c.expectedDeprecation
+		expectedLink := c.expectedLink
+
+		t.Run(testName, func(t *testing.T) {
+			server := NewServer(context.Background(), services, config{OpenAPI: openAPIConfig{Validate: true}})
+
+			req := httptest.NewRequest(method, path, strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			server.server.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, expectedResponse, rec.Body.String())
+			assert.Equal(t, expectedDeprecation, rec.Header().Get(headerDeprecation))
+			assert.Equal(t, expectedLink, rec.Header().Get(headerLink))
+		})
+	}
+}
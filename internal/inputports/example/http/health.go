@@ -0,0 +1,197 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	healthzPath string = "/healthz"
+	readyzPath  string = "/readyz"
+
+	healthCheckTimeout  time.Duration = 2 * time.Second
+	healthCheckCacheTTL time.Duration = 5 * time.Second
+
+	statusUp   string = "up"
+	statusDown string = "down"
+
+	ErrShuttingDown err = "server is shutting down"
+)
+
+// HealthChecker is implemented by every dependency that takes part in the
+// readiness report.
+type HealthChecker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+type checkReport struct {
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Duration  string    `json:"duration"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type healthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkReport `json:"checks,omitempty"`
+}
+
+type cachedCheck struct {
+	report  checkReport
+	expires time.Time
+}
+
+type health struct {
+	checkers     []HealthChecker
+	timeout      time.Duration
+	ttl          time.Duration
+	now          func() time.Time
+	shuttingDown atomic.Bool
+
+	mu    sync.Mutex
+	cache map[string]cachedCheck
+}
+
+func newHealth(checkers ...HealthChecker) *health {
+	return &health{
+		checkers: checkers,
+		timeout:  healthCheckTimeout,
+		ttl:      healthCheckCacheTTL,
+		now:      time.Now,
+		cache:    make(map[string]cachedCheck),
+	}
+}
+
+func (h *health) shutdown() {
+	if h == nil {
+		return
+	}
+
+	h.shuttingDown.Store(true)
+}
+
+func (h *health) ready(ctx context.Context) healthReport {
+	if h == nil {
+		return healthReport{Status: statusUp}
+	}
+
+	report := healthReport{
+		Status: statusUp,
+		Checks: h.check(ctx),
+	}
+
+	if h.shuttingDown.Load() {
+		report.Status = statusDown
+		report.Checks["server"] = checkReport{
+			Status:    statusDown,
+			Error:     ErrShuttingDown.Error(),
+			CheckedAt: h.now(),
+		}
+	}
+
+	for _, c := range report.Checks {
+		if c.Status != statusUp {
+			report.Status = statusDown
+		}
+	}
+
+	return report
+}
+
+// check runs the expired checks concurrently, each one bounded by its own
+// timeout, and returns the cached result for every registered dependency.
+func (h *health) check(ctx context.Context) map[string]checkReport {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	results := make(map[string]checkReport, len(h.checkers))
+	expired := make([]HealthChecker, 0, len(h.checkers))
+
+	for _, checker := range h.checkers {
+		if cached, exists := h.cache[checker.Name()]; exists && now.Before(cached.expires) {
+			results[checker.Name()] = cached.report
+		} else {
+			expired = append(expired, checker)
+		}
+	}
+
+	var (
+		wg    sync.WaitGroup
+		resMu sync.Mutex
+	)
+
+	for _, checker := range expired {
+		wg.Add(1)
+		go func(checker HealthChecker) {
+			defer wg.Done()
+
+			report := h.run(ctx, checker)
+
+			resMu.Lock()
+			results[checker.Name()] = report
+			h.cache[checker.Name()] = cachedCheck{
+				report:  report,
+				expires: report.CheckedAt.Add(h.ttl),
+			}
+			resMu.Unlock()
+		}(checker)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+func (h *health) run(ctx context.Context, checker HealthChecker) checkReport {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := h.now()
+	errc := make(chan error, 1)
+
+	go func() {
+		errc <- checker.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case <-ctx.Done():
+		err = ctx.Err()
+	case err = <-errc:
+	}
+
+	report := checkReport{
+		Status:    statusUp,
+		Duration:  h.now().Sub(start).String(),
+		CheckedAt: start,
+	}
+
+	if err != nil {
+		report.Status = statusDown
+		report.Error = err.Error()
+	}
+
+	return report
+}
+
+func (s Server) liveness(c echo.Context) error {
+	return NewResponser(c).WithPayload(http.StatusOK, healthReport{Status: statusUp}).Response()
+}
+
+func (s Server) readiness(c echo.Context) error {
+	report := s.health.ready(c.Request().Context())
+
+	code := http.StatusOK
+	if report.Status != statusUp {
+		code = http.StatusServiceUnavailable
+	}
+
+	return NewResponser(c).WithPayload(code, report).Response()
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	app "clean-arquitecture-template/internal/app/example"
+)
+
+type healthCheckerMock struct {
+	name  string
+	calls *int32
+	f     func(ctx context.Context) error
+}
+
+func (hc healthCheckerMock) Name() string {
+	return hc.name
+}
+
+func (hc healthCheckerMock) Check(ctx context.Context) error {
+	if hc.calls != nil {
+		atomic.AddInt32(hc.calls, 1)
+	}
+
+	return hc.f(ctx)
+}
+
+func Test_Liveness(t *testing.T) {
+	server := NewServer(context.Background(), app.Services{}, config{})
+
+	req := httptest.NewRequest(http.MethodGet, healthzPath, nil)
+	rec := httptest.NewRecorder()
+
+	server.server.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "{\"status\":\"up\"}\n", rec.Body.String())
+}
+
+func Test_Readiness(t *testing.T) {
+	testCases := []struct {
+		testName         string
+		checkers         []HealthChecker
+		shutdown         bool
+		expectedHTTPCode int
+		expectedStatus   map[string]string
+	}{
+		{
+			testName:         "no-checkers-case",
+			expectedHTTPCode: http.StatusOK,
+			expectedStatus:   map[string]string{},
+		},
+		{
+			testName: "all-up-case",
+			checkers: []HealthChecker{
+				healthCheckerMock{name: "mongodb", f: func(ctx context.Context) error { return nil }},
+				healthCheckerMock{name: "memory", f: func(ctx context.Context) error { return nil }},
+			},
+			expectedHTTPCode: http.StatusOK,
+			expectedStatus:   map[string]string{"mongodb": statusUp, "memory": statusUp},
+		},
+		{
+			testName: "one-down-case",
+			checkers: []HealthChecker{
+				healthCheckerMock{name: "mongodb", f: func(ctx context.Context) error { return errors.New("some-error") }},
+				healthCheckerMock{name: "memory", f: func(ctx context.Context) error { return nil }},
+			},
+			expectedHTTPCode: http.StatusServiceUnavailable,
+			expectedStatus:   map[string]string{"mongodb": statusDown, "memory": statusUp},
+		},
+		{
+			testName: "timeout-case",
+			checkers: []HealthChecker{
+				healthCheckerMock{name: "mongodb", f: func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}},
+			},
+			expectedHTTPCode: http.StatusServiceUnavailable,
+			expectedStatus:   map[string]string{"mongodb": statusDown},
+		},
+		{
+			testName: "shutting-down-case",
+			checkers: []HealthChecker{
+				healthCheckerMock{name: "mongodb", f: func(ctx context.Context) error { return nil }},
+			},
+			shutdown:         true,
+			expectedHTTPCode: http.StatusServiceUnavailable,
+			expectedStatus:   map[string]string{"mongodb": statusUp, "server": statusDown},
+		},
+	}
+
+	for _, c := range testCases {
+		testName := c.testName
+		checkers := c.checkers
+		shutdown := c.shutdown
+		expectedHTTPCode := c.expectedHTTPCode
+		expectedStatus := c.expectedStatus
+
+		t.Run(testName, func(t *testing.T) {
+			h := newHealth(checkers...)
+			h.timeout = 50 * time.Millisecond
+
+			if shutdown {
+				h.shutdown()
+			}
+
+			server := Server{server: echo.New(), health: h}
+
+			req := httptest.NewRequest(http.MethodGet, readyzPath, nil)
+			rec := httptest.NewRecorder()
+
+			err := server.readiness(server.server.NewContext(req, rec))
+			assert.NoError(t, err)
+			assert.Equal(t, expectedHTTPCode, rec.Code)
+
+			report := h.ready(context.Background())
+			status := make(map[string]string, len(report.Checks))
+			for name, check := range report.Checks {
+				status[name] = check.Status
+			}
+
+			assert.Equal(t, expectedStatus, status)
+		})
+	}
+}
+
+func Test_ReadinessCache(t *testing.T) {
+	calls := new(int32)
+	now := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+
+	h := newHealth(healthCheckerMock{name: "mongodb", calls: calls, f: func(ctx context.Context) error { return nil }})
+	h.now = func() time.Time { return now }
+
+	h.ready(context.Background())
+	h.ready(context.Background())
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+
+	now = now.Add(healthCheckCacheTTL)
+
+	h.ready(context.Background())
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"clean-arquitecture-template/internal/app/example/queries"
+)
+
+const (
+	historyPath string = "/:id/history"
+
+	historyRoute string = "history"
+)
+
+func (s Server) historyAppExample(c echo.Context) error {
+	return s.historyExample(c, v1)
+}
+
+func (s Server) historyAppExampleV2(c echo.Context) error {
+	return s.historyExample(c, v2)
+}
+
+func (s Server) historyExample(c echo.Context, v version) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), s.timeout(historyRoute))
+	defer cancel()
+
+	response := NewResponser(c)
+
+	records, err := s.exampleServices.ExampleService.Queries.HistoryExampleHandler.Handle(ctx, queries.GetHistoryRequest{ID: c.Param("id")})
+	if err != nil {
+		return response.WithError(err).Response()
+	}
+
+	payload := make([]interface{}, len(records))
+	for i := range records {
+		payload[i] = v.historyResponse(&records[i])
+	}
+
+	return response.WithPayload(http.StatusOK, payload).Response()
+}
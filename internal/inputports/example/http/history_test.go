@@ -0,0 +1,175 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	app "clean-arquitecture-template/internal/app/example"
+	"clean-arquitecture-template/internal/app/example/commands"
+	"clean-arquitecture-template/internal/app/example/queries"
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+type mockCommandHistoryLineHandler struct {
+	Handler func(context.Context, queries.GetHistoryRequest) ([]queries.HistoryRecord, error)
+}
+
+func (m mockCommandHistoryLineHandler) Handle(ctx context.Context, req queries.GetHistoryRequest) ([]queries.HistoryRecord, error) {
+	return m.Handler(ctx, req)
+}
+
+func Test_History(t *testing.T) {
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+
+	services := app.Services{
+		ExampleService: app.ExampleServices{
+			Queries: app.Queries{
+				HistoryExampleHandler: mockCommandHistoryLineHandler{Handler: func(ctx context.Context, req queries.GetHistoryRequest) ([]queries.HistoryRecord, error) {
+					if req.ID == "bad" {
+						return nil, queries.ErrInvalidID
+					}
+
+					return []queries.HistoryRecord{
+						{
+							Action:    "create",
+							Actor:     "alice",
+							RequestID: "r1",
+							At:        tstamp,
+							After:     &queries.GetExampleResult{ID: "1000", CreatedAt: tstamp, Data: "first-line"},
+						},
+					}, nil
+				}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		testName         string
+		path             string
+		expectedHTTPCode int
+		expectedResponse string
+	}{
+		{
+			testName:         "v2-case",
+			path:             "/v2/example/1000/history",
+			expectedHTTPCode: http.StatusOK,
+			expectedResponse: "[{\"action\":\"create\",\"actor\":\"alice\",\"requestId\":\"r1\",\"at\":\"2018-09-16T12:00:00Z\",\"after\":{\"id\":\"1000\",\"createdAt\":\"2018-09-16T12:00:00Z\",\"data\":\"first-line\"}}]\n",
+		},
+		{
+			testName:         "v1-case",
+			path:             "/v1/example/1000/history",
+			expectedHTTPCode: http.StatusOK,
+			expectedResponse: "[{\"action\":\"create\",\"actor\":\"alice\",\"request_id\":\"r1\",\"at\":\"2018-09-16 12:00:00 +0000 UTC\",\"after\":{\"id\":\"1000\",\"created_at\":\"2018-09-16 12:00:00 +0000 UTC\",\"data\":\"first-line\"}}]\n",
+		},
+		{
+			testName:         "invalid-id-case",
+			path:             "/example/bad/history",
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+
+		t.Run(c.testName, func(t *testing.T) {
+			server := NewServer(context.Background(), services, config{OpenAPI: openAPIConfig{Validate: true}})
+
+			req := httptest.NewRequest(http.MethodGet, c.path, nil)
+			rec := httptest.NewRecorder()
+
+			server.server.ServeHTTP(rec, req)
+
+			assert.Equal(t, c.expectedHTTPCode, rec.Code)
+			if c.expectedResponse != "" {
+				assert.Equal(t, c.expectedResponse, rec.Body.String())
+			}
+		})
+	}
+}
+
+func Test_AuditContext(t *testing.T) {
+	testCases := []struct {
+		testName          string
+		actor             string
+		adminToken        string
+		requestID         string
+		expectedActor     string
+		expectedRequestID string
+	}{
+		{
+			testName:          "claimed-case",
+			actor:             "alice",
+			requestID:         "r1",
+			expectedActor:     "unverified:alice",
+			expectedRequestID: "r1",
+		},
+		{
+			testName:      "admin-case",
+			actor:         "alice",
+			adminToken:    "secret",
+			expectedActor: adminActor,
+		},
+		{
+			testName:      "wrong-admin-token-case",
+			actor:         "alice",
+			adminToken:    "guess",
+			expectedActor: "unverified:alice",
+		},
+		{
+			testName:      "anonymous-case",
+			expectedActor: anonymousActor,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+
+		t.Run(c.testName, func(t *testing.T) {
+			var actor, requestID string
+
+			services := app.Services{
+				ExampleService: app.ExampleServices{
+					Commands: app.Commands{
+						DeleteExampleHandler: mockCommandDeleteLineHandler{Handler: func(ctx context.Context, command commands.DeleteExampleRequest) error {
+							actor = example.ActorFrom(ctx)
+							requestID = example.RequestIDFrom(ctx)
+
+							return nil
+						}},
+					},
+				},
+			}
+
+			server := NewServer(context.Background(), services, config{Admin: "secret"})
+
+			req := httptest.NewRequest(http.MethodDelete, "/v2/example/1000", nil)
+			if c.actor != "" {
+				req.Header.Set(headerActor, c.actor)
+			}
+			if c.adminToken != "" {
+				req.Header.Set(headerAdminToken, c.adminToken)
+			}
+			if c.requestID != "" {
+				req.Header.Set(echo.HeaderXRequestID, c.requestID)
+			}
+			rec := httptest.NewRecorder()
+
+			server.server.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+			assert.Equal(t, c.expectedActor, actor)
+			assert.Equal(t, requestID, rec.Header().Get(echo.HeaderXRequestID))
+			if c.expectedRequestID != "" {
+				assert.Equal(t, c.expectedRequestID, requestID)
+			} else {
+				assert.Len(t, requestID, 32)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"clean-arquitecture-template/internal/app/example/queries"
+)
+
+const (
+	listPath string = ""
+
+	listRoute string = "list"
+
+	createdAfterParam  string = "created_after"
+	createdBeforeParam string = "created_before"
+	prefixParam        string = "prefix"
+	sortParam          string = "sort"
+
+	dateLayout string = "2006-01-02"
+)
+
+func (s Server) listAppExamples(c echo.Context) error {
+	return s.listExamples(c, v1)
+}
+
+func (s Server) listAppExamplesV2(c echo.Context) error {
+	return s.listExamples(c, v2)
+}
+
+func (s Server) listExamples(c echo.Context, v version) error {
+	response := NewResponser(c)
+
+	req := queries.ListExamplesRequest{
+		DataPrefix: c.QueryParam(prefixParam),
+		Sort:       c.QueryParam(sortParam),
+	}
+
+	var err error
+	if req.CreatedAfter, err = timestamp(c, createdAfterParam); err != nil {
+		return response.WithError(err).Response()
+	}
+
+	if req.CreatedBefore, err = timestamp(c, createdBeforeParam); err != nil {
+		return response.WithError(err).Response()
+	}
+
+	if req.Limit, err = number(c, limitParam); err != nil {
+		return response.WithError(err).Response()
+	}
+
+	if req.IncludeDeleted, err = s.includeDeleted(c); err != nil {
+		return response.WithError(err).Response()
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), s.timeout(listRoute))
+	defer cancel()
+
+	results, err := s.exampleServices.ExampleService.Queries.ListExamplesHandler.Handle(ctx, req)
+	if err != nil {
+		return response.WithError(err).Response()
+	}
+
+	payload := make([]interface{}, len(results))
+	for i := range results {
+		payload[i] = v.readResponse(&results[i])
+	}
+
+	return response.WithPayload(http.StatusOK, payload).Response()
+}
+
+// timestamp reads an RFC 3339 time, or a date standing for its midnight UTC,
+// from a query parameter. It is zero when missing.
+func timestamp(c echo.Context, name string) (time.Time, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(dateLayout, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %s: %w", name, err.Error(), ErrInputParam)
+	}
+
+	return t, nil
+}
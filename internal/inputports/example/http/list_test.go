@@ -0,0 +1,103 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	app "clean-arquitecture-template/internal/app/example"
+	"clean-arquitecture-template/internal/app/example/queries"
+)
+
+type mockCommandListLinesHandler struct {
+	Handler func(context.Context, queries.ListExamplesRequest) ([]queries.GetExampleResult, error)
+}
+
+func (m mockCommandListLinesHandler) Handle(ctx context.Context, req queries.ListExamplesRequest) ([]queries.GetExampleResult, error) {
+	return m.Handler(ctx, req)
+}
+
+func Test_List(t *testing.T) {
+	day := time.Date(2018, time.September, 16, 0, 0, 0, 0, time.UTC)
+
+	var received queries.ListExamplesRequest
+
+	services := app.Services{
+		ExampleService: app.ExampleServices{
+			Queries: app.Queries{
+				ListExamplesHandler: mockCommandListLinesHandler{Handler: func(ctx context.Context, req queries.ListExamplesRequest) ([]queries.GetExampleResult, error) {
+					received = req
+
+					if req.Sort == "sideways" {
+						return nil, queries.ErrListQuery
+					}
+
+					return []queries.GetExampleResult{{ID: "1000", CreatedAt: day, Data: "first-line"}}, nil
+				}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		testName         string
+		path             string
+		expectedHTTPCode int
+		expectedResponse string
+		expectedRequest  queries.ListExamplesRequest
+	}{
+		{
+			testName:         "v2-case",
+			path:             "/v2/example?created_after=2018-09-16&created_before=2018-09-17T00:00:00Z&prefix=first&sort=desc&limit=5",
+			expectedHTTPCode: http.StatusOK,
+			expectedResponse: "[{\"id\":\"1000\",\"createdAt\":\"2018-09-16T00:00:00Z\",\"data\":\"first-line\"}]\n",
+			expectedRequest: queries.ListExamplesRequest{
+				CreatedAfter:  day,
+				CreatedBefore: day.Add(24 * time.Hour),
+				DataPrefix:    "first",
+				Sort:          queries.SortDescending,
+				Limit:         5,
+			},
+		},
+		{
+			testName:         "v1-case",
+			path:             "/v1/example",
+			expectedHTTPCode: http.StatusOK,
+			expectedResponse: "[{\"id\":\"1000\",\"created_at\":\"2018-09-16 00:00:00 +0000 UTC\",\"data\":\"first-line\"}]\n",
+		},
+		{
+			testName:         "invalid-sort-case",
+			path:             "/v2/example?sort=sideways",
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedRequest:  queries.ListExamplesRequest{Sort: "sideways"},
+		},
+		{
+			testName:         "invalid-time-case",
+			path:             "/v2/example?created_after=yesterday",
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+
+		t.Run(c.testName, func(t *testing.T) {
+			received = queries.ListExamplesRequest{}
+			server := NewServer(context.Background(), services, config{})
+
+			req := httptest.NewRequest(http.MethodGet, c.path, nil)
+			rec := httptest.NewRecorder()
+
+			server.server.ServeHTTP(rec, req)
+
+			assert.Equal(t, c.expectedHTTPCode, rec.Code)
+			if c.expectedResponse != "" {
+				assert.Equal(t, c.expectedResponse, rec.Body.String())
+			}
+			assert.Equal(t, c.expectedRequest, received)
+		})
+	}
+}
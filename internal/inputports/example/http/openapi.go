@@ -0,0 +1,289 @@
+package http
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+
+	"clean-arquitecture-template/internal/inputports/problem"
+)
+
+const (
+	openAPIPath   string = "/openapi.json"
+	openAPIUIPath string = "/docs"
+
+	// streamedExtension marks the operations streaming their bodies, which
+	// the validator leaves alone instead of holding them in memory.
+	streamedExtension string = "x-streamed"
+
+	ErrOpenAPISpec err = "invalid openapi spec"
+)
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+const openAPIUIPage string = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <title>Example API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css" />
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "` + openAPIPath + `", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
+
+// The validator decodes the bodies of every negotiated format with the same
+// codecs as the handlers.
+func init() {
+	for mediaType, cd := range codecs {
+		if mediaType != echo.MIMEApplicationJSON {
+			openapi3filter.RegisterBodyDecoder(mediaType, bodyDecoder(cd))
+		}
+	}
+}
+
+func bodyDecoder(cd codec) openapi3filter.BodyDecoder {
+	return func(body io.Reader, _ http.Header, schema *openapi3.SchemaRef, _ openapi3filter.EncodingFn) (interface{}, error) {
+		var (
+			value interface{}
+			err   error
+		)
+
+		if schema != nil && schema.Value != nil && schema.Value.Type == openapi3.TypeArray {
+			var list []interface{}
+			err = cd.decode(body, &list)
+			value = list
+		} else {
+			var object map[string]interface{}
+			err = cd.decode(body, &object)
+			value = object
+		}
+
+		// Decoded numbers are normalized to the float64 the schemas expect.
+		var normalized interface{}
+		if err == nil {
+			err = convert(value, &normalized)
+		}
+
+		if err != nil {
+			return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+		}
+
+		if _, isCSV := cd.(csvCodec); isCSV && schema != nil {
+			normalized = coerceCSV(normalized, schema.Value)
+		}
+
+		return normalized, nil
+	}
+}
+
+// coerceCSV restores, following schema, the types the csv cells lost. Empty
+// cells of non string fields are dropped, as json omits them.
+func coerceCSV(v interface{}, schema *openapi3.Schema) interface{} {
+	if schema == nil {
+		return v
+	}
+
+	switch value := v.(type) {
+	case []interface{}:
+		if schema.Items != nil {
+			for i := range value {
+				value[i] = coerceCSV(value[i], schema.Items.Value)
+			}
+		}
+	case map[string]interface{}:
+		for k, item := range value {
+			if property, exists := schema.Properties[k]; exists {
+				if coerced := coerceCSV(item, property.Value); coerced != nil {
+					value[k] = coerced
+				} else {
+					delete(value, k)
+				}
+			}
+		}
+	case string:
+		return coerceCell(value, schema)
+	}
+
+	return v
+}
+
+func coerceCell(cell string, schema *openapi3.Schema) interface{} {
+	if schema.Type == openapi3.TypeString || schema.Type == "" {
+		return cell
+	}
+
+	if cell == "" {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(cell), &value); err != nil {
+		return cell
+	}
+
+	return value
+}
+
+func loadOpenAPI() (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+
+	doc, err := loader.LoadFromData(openAPISpec)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrOpenAPISpec)
+	}
+
+	if err = doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrOpenAPISpec)
+	}
+
+	return doc, nil
+}
+
+func (s Server) openAPI(c echo.Context) error {
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSONCharsetUTF8, openAPISpec)
+}
+
+func (s Server) openAPIDocs(c echo.Context) error {
+	return c.HTML(http.StatusOK, openAPIUIPage)
+}
+
+// bodyRecorder holds the response back until it has been validated, so a
+// response drifting from the spec never reaches the client.
+type bodyRecorder struct {
+	http.ResponseWriter
+	code int
+	body bytes.Buffer
+}
+
+func (br *bodyRecorder) WriteHeader(code int) {
+	br.code = code
+}
+
+func (br *bodyRecorder) Write(b []byte) (int, error) {
+	return br.body.Write(b)
+}
+
+func (br *bodyRecorder) Flush() {}
+
+// newOpenAPIValidator checks every request against the spec before it reaches
+// the handlers, and every response before it is sent. Invalid requests are
+// reported as invalid input, invalid responses as server errors. Routes
+// missing from the spec, and streamed ones, are left to the router.
+func newOpenAPIValidator() (echo.MiddlewareFunc, error) {
+	doc, err := loadOpenAPI()
+	if err != nil {
+		return nil, err
+	}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrOpenAPISpec)
+	}
+
+	options := &openapi3filter.Options{
+		IncludeResponseStatus: true,
+		AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			route, params, err := router.FindRoute(req)
+			if err != nil || streamed(route) {
+				return next(c)
+			}
+
+			if req.ContentLength != 0 {
+				if _, err := bodyCodec(req); err != nil {
+					return NewResponser(c).WithError(err).Response()
+				}
+			}
+
+			requestInput := &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: params,
+				Route:      route,
+				Options:    options,
+			}
+			if err := openapi3filter.ValidateRequest(req.Context(), requestInput); err != nil {
+				return NewResponser(c).WithError(fmt.Errorf("%s: %w", err.Error(), ErrInputParam)).Response()
+			}
+
+			writer := c.Response().Writer
+			recorder := &bodyRecorder{ResponseWriter: writer, code: http.StatusOK}
+			c.Response().Writer = recorder
+
+			handlerErr := next(c)
+			if handlerErr != nil && !c.Response().Committed {
+				c.Error(handlerErr)
+			}
+
+			c.Response().Writer = writer
+
+			return validateResponse(c, requestInput, recorder)
+		}
+	}, nil
+}
+
+func validateResponse(c echo.Context, requestInput *openapi3filter.RequestValidationInput, recorder *bodyRecorder) error {
+	responseInput := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: requestInput,
+		Status:                 recorder.code,
+		Header:                 c.Response().Header(),
+		Body:                   io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+		Options:                requestInput.Options,
+	}
+
+	if err := openapi3filter.ValidateResponse(c.Request().Context(), responseInput); err != nil {
+		log.WithError(err).WithField("route", routeName(requestInput.Route)).Error("response does not match the openapi spec")
+
+		c.Response().Committed = false
+		c.Response().Size = 0
+
+		return NewResponser(c).withProblem(problem.FromStatus(http.StatusInternalServerError, "", instance(c))).Response()
+	}
+
+	c.Response().Writer.WriteHeader(recorder.code)
+	_, err := c.Response().Writer.Write(recorder.body.Bytes())
+
+	return err
+}
+
+func streamed(route *routers.Route) bool {
+	if route == nil || route.Operation == nil {
+		return false
+	}
+
+	is, _ := route.Operation.Extensions[streamedExtension].(bool)
+
+	return is
+}
+
+func routeName(route *routers.Route) string {
+	if route == nil || route.Operation == nil {
+		return ""
+	}
+
+	return route.Operation.OperationID
+}
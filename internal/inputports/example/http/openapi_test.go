@@ -0,0 +1,240 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	app "clean-arquitecture-template/internal/app/example"
+	"clean-arquitecture-template/internal/app/example/commands"
+	"clean-arquitecture-template/internal/app/example/queries"
+	"clean-arquitecture-template/internal/inputports/problem"
+)
+
+func Test_OpenAPISpecCoversRoutes(t *testing.T) {
+	doc, err := loadOpenAPI()
+	require.NoError(t, err)
+
+	server := NewServer(context.Background(), app.Services{}, config{})
+
+	for _, route := range server.server.Routes() {
+		path := strings.ReplaceAll(route.Path, ":id", "{id}")
+
+		item := doc.Paths.Find(path)
+		if assert.NotNil(t, item, "route %s %s is missing from the spec", route.Method, route.Path) {
+			assert.NotNil(t, item.GetOperation(route.Method), "route %s %s is missing from the spec", route.Method, route.Path)
+		}
+	}
+}
+
+func Test_OpenAPIValidation(t *testing.T) {
+	tstamp := time.Date(2018, time.September, 16, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		testName         string
+		writeHandler     func(context.Context, commands.AddExampleRequest) (*string, error)
+		readHandler      func(context.Context, queries.GetExampleRequest) (*queries.GetExampleResult, error)
+		method           string
+		path             string
+		body             string
+		expectedHTTPCode int
+		expectedCode     string
+	}{
+		{
+			testName: "write-success-case",
+			writeHandler: func(ctx context.Context, req commands.AddExampleRequest) (*string, error) {
+				newID := "1234567890"
+				return &newID, nil
+			},
+			method:           http.MethodPost,
+			path:             "/example/write",
+			body:             `{"data":"x"}`,
+			expectedHTTPCode: http.StatusOK,
+		},
+		{
+			testName:         "write-missing-data-case",
+			method:           http.MethodPost,
+			path:             "/example/write",
+			body:             `{}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedCode:     "invalid_input",
+		},
+		{
+			testName:         "write-invalid-data-case",
+			method:           http.MethodPost,
+			path:             "/example/write",
+			body:             `{"data":1}`,
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedCode:     "invalid_input",
+		},
+		{
+			testName: "write-system-error-case",
+			writeHandler: func(ctx context.Context, req commands.AddExampleRequest) (*string, error) {
+				return nil, commands.ErrSystem
+			},
+			method:           http.MethodPost,
+			path:             "/example/write",
+			body:             `{"data":"x"}`,
+			expectedHTTPCode: http.StatusInternalServerError,
+			expectedCode:     "system_error",
+		},
+		{
+			testName: "write-undocumented-status-case",
+			writeHandler: func(ctx context.Context, req commands.AddExampleRequest) (*string, error) {
+				return nil, queries.ErrNotFound
+			},
+			method:           http.MethodPost,
+			path:             "/example/write",
+			body:             `{"data":"x"}`,
+			expectedHTTPCode: http.StatusInternalServerError,
+			expectedCode:     "internal_server_error",
+		},
+		{
+			testName: "read-success-case",
+			readHandler: func(ctx context.Context, req queries.GetExampleRequest) (*queries.GetExampleResult, error) {
+				return &queries.GetExampleResult{ID: req.ID, Data: "first-line", CreatedAt: tstamp}, nil
+			},
+			method:           http.MethodGet,
+			path:             "/example/read/1000",
+			expectedHTTPCode: http.StatusOK,
+		},
+		{
+			testName: "read-not-found-case",
+			readHandler: func(ctx context.Context, req queries.GetExampleRequest) (*queries.GetExampleResult, error) {
+				return nil, queries.ErrNotFound
+			},
+			method:           http.MethodGet,
+			path:             "/example/read/1000",
+			expectedHTTPCode: http.StatusNotFound,
+			expectedCode:     "not_found",
+		},
+		{
+			testName: "read-timeout-case",
+			readHandler: func(ctx context.Context, req queries.GetExampleRequest) (*queries.GetExampleResult, error) {
+				return nil, queries.ErrTimeout
+			},
+			method:           http.MethodGet,
+			path:             "/example/read/1000",
+			expectedHTTPCode: http.StatusGatewayTimeout,
+			expectedCode:     "timeout",
+		},
+		{
+			testName: "read-unavailable-case",
+			readHandler: func(ctx context.Context, req queries.GetExampleRequest) (*queries.GetExampleResult, error) {
+				return nil, queries.ErrUnavailable
+			},
+			method:           http.MethodGet,
+			path:             "/example/read/1000",
+			expectedHTTPCode: http.StatusServiceUnavailable,
+			expectedCode:     "unavailable",
+		},
+		{
+			testName:         "liveness-case",
+			method:           http.MethodGet,
+			path:             healthzPath,
+			expectedHTTPCode: http.StatusOK,
+		},
+		{
+			testName:         "readiness-case",
+			method:           http.MethodGet,
+			path:             readyzPath,
+			expectedHTTPCode: http.StatusOK,
+		},
+		{
+			testName:         "spec-case",
+			method:           http.MethodGet,
+			path:             openAPIPath,
+			expectedHTTPCode: http.StatusOK,
+		},
+		{
+			testName:         "vars-without-admin-token-case",
+			method:           http.MethodGet,
+			path:             varsPath,
+			expectedHTTPCode: http.StatusForbidden,
+			expectedCode:     "forbidden",
+		},
+		{
+			testName:         "unknown-route-case",
+			method:           http.MethodGet,
+			path:             "/unknown",
+			expectedHTTPCode: http.StatusNotFound,
+			expectedCode:     "not_found",
+		},
+	}
+
+	for _, c := range testCases {
+		testName := c.testName
+		method := c.method
+		path := c.path
+		body := c.body
+		expectedHTTPCode := c.expectedHTTPCode
+		expectedCode := c.expectedCode
+
+		services := app.Services{
+			ExampleService: app.ExampleServices{
+				Commands: app.Commands{
+					CreateExampleHandler: mockCommandCreateLineHandler{Handler: c.writeHandler},
+				},
+				Queries: app.Queries{
+					ReadExampleHandler: mockCommandReadLineHandler{Handler: c.readHandler},
+				},
+			},
+		}
+
+		t.Run(testName, func(t *testing.T) {
+			server := NewServer(context.Background(), services, config{OpenAPI: openAPIConfig{Validate: true}})
+
+			req := httptest.NewRequest(method, path, strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			server.server.ServeHTTP(rec, req)
+
+			assert.Equal(t, expectedHTTPCode, rec.Code)
+			if expectedCode != "" {
+				assert.Equal(t, problem.MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+				assert.Contains(t, rec.Body.String(), `"code":"`+expectedCode+`"`)
+			}
+		})
+	}
+}
+
+func Test_OpenAPIUI(t *testing.T) {
+	testCases := []struct {
+		testName         string
+		ui               bool
+		expectedHTTPCode int
+	}{
+		{
+			testName:         "enabled-case",
+			ui:               true,
+			expectedHTTPCode: http.StatusOK,
+		},
+		{
+			testName:         "disabled-case",
+			ui:               false,
+			expectedHTTPCode: http.StatusNotFound,
+		},
+	}
+
+	for _, c := range testCases {
+		ui := c.ui
+		expectedHTTPCode := c.expectedHTTPCode
+
+		t.Run(c.testName, func(t *testing.T) {
+			server := NewServer(context.Background(), app.Services{}, config{OpenAPI: openAPIConfig{UI: ui}})
+
+			rec := httptest.NewRecorder()
+			server.server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, openAPIUIPath, nil))
+
+			assert.Equal(t, expectedHTTPCode, rec.Code)
+		})
+	}
+}
@@ -2,33 +2,26 @@ package http
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 	log "github.com/sirupsen/logrus"
 
-	"clean-arquitecture-template/internal/app/example/commands"
-	"clean-arquitecture-template/internal/app/example/queries"
+	"clean-arquitecture-template/internal/inputports/problem"
 )
 
 const (
-	httpErrorResponse responseType = iota
-	jsonErrorResponse
-	jsonResponse
-	notFoundResponse
+	problemResponse responseType = iota
+	payloadResponse
+	noContentResponse
 
 	defaultResponserError string = "response not set"
 	defaultResponserCode  int    = http.StatusNotImplemented
 
-	ErrInputParam Error = "input param error"
+	ErrInputParam = problem.ErrInputParam
 )
 
-type Error string
-
-func (e Error) Error() string {
-	return string(e)
-}
-
 type responseType int
 
 type responser struct {
@@ -45,69 +38,89 @@ func NewResponser(c echo.Context) *responser {
 	}
 }
 
-func (r *responser) withError(err error) *responser {
-	if r == nil {
-		return r
+func instance(c echo.Context) string {
+	if c == nil || c.Request() == nil || c.Request().URL == nil {
+		return ""
 	}
 
-	if errors.Is(err, commands.ErrSystem) || errors.Is(err, queries.ErrSystem) {
-		r.code = http.StatusInternalServerError
-	}
-
-	if errors.Is(err, queries.ErrInvalidID) || errors.Is(err, ErrInputParam) {
-		r.code = http.StatusBadRequest
-	}
+	return c.Request().URL.Path
+}
 
-	r.payload = err.Error()
+func (r *responser) withProblem(p problem.Problem) *responser {
+	r.responseType = problemResponse
+	r.code = p.Status
+	r.payload = p
 
 	return r
 }
 
-func (r *responser) WithHTTPError(err error) *responser {
+func (r *responser) WithError(err error) *responser {
 	if r == nil {
 		return r
 	}
 
-	r.withError(err)
-	r.responseType = httpErrorResponse
-
-	return r
+	return r.withProblem(newProblem(err, instance(r.echoContext)))
 }
 
-func (r *responser) WithJSONError(err error) *responser {
+// WithExtendedError sends the problem of err with the extension members
+// extend adds to it, as RFC 7807 allows.
+func (r *responser) WithExtendedError(err error, extend func(problem.Problem) interface{}) *responser {
 	if r == nil {
 		return r
 	}
 
-	r.withError(err)
-	r.responseType = jsonErrorResponse
+	p := newProblem(err, instance(r.echoContext))
+	r.withProblem(p)
+	r.payload = extend(p)
 
 	return r
 }
 
-func (r *responser) WithJSON(code int, payload interface{}) *responser {
+// newProblem builds the problem for err from the registry shared by every
+// input port. Server errors are logged since their detail is not sent back.
+func newProblem(err error, instance string) problem.Problem {
+	p := problem.Default.New(err, instance)
+	if p.Status >= http.StatusInternalServerError {
+		log.WithError(err).WithField("code", p.Code).Error("request failed")
+	}
+
+	return p
+}
+
+// WithPayload sends payload encoded in the format negotiated with the Accept
+// header of the request.
+func (r *responser) WithPayload(code int, payload interface{}) *responser {
 	if r == nil {
 		return r
 	}
 
-	r.responseType = jsonResponse
+	r.responseType = payloadResponse
 	r.code = code
 	r.payload = payload
 
 	return r
 }
 
-func (r *responser) WithNotFound() *responser {
+func (r *responser) WithNoContent() *responser {
 	if r == nil {
 		return r
 	}
 
-	r.code = http.StatusNotFound
-	r.responseType = notFoundResponse
+	r.responseType = noContentResponse
+	r.code = http.StatusNoContent
+	r.payload = nil
 
 	return r
 }
 
+func (r *responser) WithNotFound() *responser {
+	if r == nil {
+		return r
+	}
+
+	return r.withProblem(problem.FromStatus(http.StatusNotFound, "", instance(r.echoContext)))
+}
+
 func (r *responser) Response() error {
 	if r == nil {
 		log.Error(defaultResponserError)
@@ -115,13 +128,69 @@ func (r *responser) Response() error {
 	}
 
 	switch r.responseType {
-	case httpErrorResponse:
-		return echo.NewHTTPError(r.code, r.payload)
-	case jsonErrorResponse, jsonResponse:
-		return r.echoContext.JSONPretty(r.code, r.payload, " ")
-	case notFoundResponse:
+	case problemResponse:
+		return r.send(problem.MIMEApplicationProblemJSON, jsonCodec{indent: r.indent()})
+	case payloadResponse:
+		r.echoContext.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+
+		cd, found := negotiate(r.accept(), r.indent())
+		if !found {
+			return r.WithError(ErrNotAcceptable).Response()
+		}
+
+		return r.send(cd.contentType(), cd)
+	case noContentResponse:
 		return r.echoContext.NoContent(r.code)
 	}
 
-	return echo.NewHTTPError(defaultResponserCode, defaultResponserError)
+	return r.withProblem(problem.FromStatus(defaultResponserCode, defaultResponserError, instance(r.echoContext))).Response()
+}
+
+func (r *responser) send(contentType string, cd codec) error {
+	body, err := encodeBody(cd, r.payload)
+	if err != nil {
+		return r.WithError(err).Response()
+	}
+
+	return r.echoContext.Blob(r.code, contentType, body)
+}
+
+func (r *responser) accept() string {
+	if req := r.echoContext.Request(); req != nil {
+		return req.Header.Get(echo.HeaderAccept)
+	}
+
+	return ""
+}
+
+// indent keeps the responses compact unless the request asks for them
+// pretty printed.
+func (r *responser) indent() string {
+	if req := r.echoContext.Request(); req != nil && req.URL != nil && req.URL.Query().Has(prettyParam) {
+		return prettyIndent
+	}
+
+	return ""
+}
+
+// handleError replaces the echo error handler, so the errors raised by echo
+// itself, such as unknown routes or malformed bodies, follow the same
+// contract as the handler errors.
+func handleError(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	response := NewResponser(c)
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		response.withProblem(problem.FromStatus(he.Code, fmt.Sprint(he.Message), instance(c)))
+	} else {
+		response.WithError(err)
+	}
+
+	if err := response.Response(); err != nil {
+		log.WithError(err).Error("unable to send error response")
+	}
 }
@@ -1,7 +1,10 @@
 package http
 
 import (
+	app "clean-arquitecture-template/internal/app/example"
 	"clean-arquitecture-template/internal/app/example/commands"
+	"clean-arquitecture-template/internal/inputports/problem"
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -25,10 +28,8 @@ func Test_Responser(t *testing.T) {
 			buildResponser: func() *responser {
 				var r *responser
 
-				r.withError(nil)
-				r.WithHTTPError(nil)
-				r.WithJSONError(nil)
-				r.WithJSON(200, nil)
+				r.WithError(nil)
+				r.WithPayload(200, nil)
 				r.WithNotFound()
 				r.Response()
 
@@ -36,72 +37,71 @@ func Test_Responser(t *testing.T) {
 			},
 		},
 		{
-			testName: "http-error-case",
+			testName: "unknown-error-case",
 			expectedResponser: &responser{
 				echoContext:  econtext,
-				responseType: httpErrorResponse,
-				payload:      "some-error",
-			},
-			buildResponser: func() *responser {
-				resp := &responser{
-					echoContext: econtext,
-				}
-
-				resp = resp.WithHTTPError(errors.New("some-error"))
-
-				return resp
-			},
-		},
-		{
-			testName: "http-system-error-case",
-			expectedResponser: &responser{
-				echoContext:  econtext,
-				responseType: httpErrorResponse,
+				responseType: problemResponse,
 				code:         http.StatusInternalServerError,
-				payload:      commands.ErrSystem.Error(),
+				payload: problem.Problem{
+					Type:   "/problems/unknown_error",
+					Title:  "Internal Server Error",
+					Status: http.StatusInternalServerError,
+					Code:   problem.CodeUnknown,
+				},
 			},
 			buildResponser: func() *responser {
 				resp := &responser{
 					echoContext: econtext,
 				}
 
-				resp = resp.WithHTTPError(commands.ErrSystem)
+				resp = resp.WithError(errors.New("some-error"))
 
 				return resp
 			},
 		},
 		{
-			testName: "http-bad-request-error-case",
+			testName: "system-error-case",
 			expectedResponser: &responser{
 				echoContext:  econtext,
-				responseType: httpErrorResponse,
-				code:         http.StatusBadRequest,
-				payload:      ErrInputParam.Error(),
+				responseType: problemResponse,
+				code:         http.StatusInternalServerError,
+				payload: problem.Problem{
+					Type:   "/problems/system_error",
+					Title:  "System error",
+					Status: http.StatusInternalServerError,
+					Code:   "system_error",
+				},
 			},
 			buildResponser: func() *responser {
 				resp := &responser{
 					echoContext: econtext,
 				}
 
-				resp = resp.WithHTTPError(ErrInputParam)
+				resp = resp.WithError(commands.ErrSystem)
 
 				return resp
 			},
 		},
 		{
-			testName: "json-error-case",
+			testName: "bad-request-error-case",
 			expectedResponser: &responser{
 				echoContext:  econtext,
-				responseType: jsonErrorResponse,
+				responseType: problemResponse,
 				code:         http.StatusBadRequest,
-				payload:      ErrInputParam.Error(),
+				payload: problem.Problem{
+					Type:   "/problems/invalid_input",
+					Title:  "Invalid input",
+					Status: http.StatusBadRequest,
+					Detail: ErrInputParam.Error(),
+					Code:   "invalid_input",
+				},
 			},
 			buildResponser: func() *responser {
 				resp := &responser{
 					echoContext: econtext,
 				}
 
-				resp = resp.WithJSONError(ErrInputParam)
+				resp = resp.WithError(ErrInputParam)
 
 				return resp
 			},
@@ -110,7 +110,7 @@ func Test_Responser(t *testing.T) {
 			testName: "json-case",
 			expectedResponser: &responser{
 				echoContext:  econtext,
-				responseType: jsonResponse,
+				responseType: payloadResponse,
 				code:         http.StatusOK,
 				payload:      `{}`,
 			},
@@ -119,7 +119,7 @@ func Test_Responser(t *testing.T) {
 					echoContext: econtext,
 				}
 
-				resp = resp.WithJSON(http.StatusOK, `{}`)
+				resp = resp.WithPayload(http.StatusOK, `{}`)
 
 				return resp
 			},
@@ -128,9 +128,14 @@ func Test_Responser(t *testing.T) {
 			testName: "notfound-case",
 			expectedResponser: &responser{
 				echoContext:  econtext,
-				responseType: notFoundResponse,
+				responseType: problemResponse,
 				code:         http.StatusNotFound,
-				payload:      nil,
+				payload: problem.Problem{
+					Type:   "/problems/not_found",
+					Title:  "Not Found",
+					Status: http.StatusNotFound,
+					Code:   "not_found",
+				},
 			},
 			buildResponser: func() *responser {
 				resp := &responser{
@@ -160,21 +165,23 @@ func Test_Responser(t *testing.T) {
 
 func Test_ResponserResponse(t *testing.T) {
 	testCases := []struct {
-		testName         string
-		buildResponser   func() (*responser, *httptest.ResponseRecorder)
-		expectedHTTPCode int
-		expectedResponse string
-		expectedError    error
+		testName            string
+		buildResponser      func() (*responser, *httptest.ResponseRecorder)
+		expectedHTTPCode    int
+		expectedContentType string
+		expectedResponse    string
 	}{
 		{
-			testName: "http-error-case",
+			testName: "problem-case",
 			buildResponser: func() (*responser, *httptest.ResponseRecorder) {
 				rec := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodPost, "/example/write", nil)
 
-				return NewResponser(echo.New().NewContext(nil, rec)).WithHTTPError(ErrInputParam), rec
+				return NewResponser(echo.New().NewContext(req, rec)).WithError(ErrInputParam), rec
 			},
-			expectedHTTPCode: 200,
-			expectedError:    &echo.HTTPError{Code: 400, Message: "input param error", Internal: error(nil)},
+			expectedHTTPCode:    400,
+			expectedContentType: problem.MIMEApplicationProblemJSON,
+			expectedResponse:    "{\"type\":\"/problems/invalid_input\",\"title\":\"Invalid input\",\"status\":400,\"detail\":\"input param error\",\"instance\":\"/example/write\",\"code\":\"invalid_input\"}\n",
 		},
 		{
 			testName: "json-case",
@@ -187,11 +194,11 @@ func Test_ResponserResponse(t *testing.T) {
 					ID: "123",
 				}
 
-				return NewResponser(echo.New().NewContext(nil, rec)).WithJSON(http.StatusOK, r), rec
+				return NewResponser(echo.New().NewContext(nil, rec)).WithPayload(http.StatusOK, r), rec
 			},
-			expectedHTTPCode: 200,
-			expectedError:    nil,
-			expectedResponse: "{\n \"id\": \"123\"\n}\n",
+			expectedHTTPCode:    200,
+			expectedContentType: echo.MIMEApplicationJSONCharsetUTF8,
+			expectedResponse:    "{\"id\":\"123\"}\n",
 		},
 		{
 			testName: "not-found-case",
@@ -200,8 +207,9 @@ func Test_ResponserResponse(t *testing.T) {
 
 				return NewResponser(echo.New().NewContext(nil, rec)).WithNotFound(), rec
 			},
-			expectedHTTPCode: 404,
-			expectedError:    nil,
+			expectedHTTPCode:    404,
+			expectedContentType: problem.MIMEApplicationProblemJSON,
+			expectedResponse:    "{\"type\":\"/problems/not_found\",\"title\":\"Not Found\",\"status\":404,\"code\":\"not_found\"}\n",
 		},
 		{
 			testName: "default-error-case",
@@ -211,8 +219,9 @@ func Test_ResponserResponse(t *testing.T) {
 
 				return resp, rec
 			},
-			expectedHTTPCode: 200,
-			expectedError:    &echo.HTTPError{Code: 501, Message: "response not set", Internal: error(nil)},
+			expectedHTTPCode:    501,
+			expectedContentType: problem.MIMEApplicationProblemJSON,
+			expectedResponse:    "{\"type\":\"/problems/not_implemented\",\"title\":\"Not Implemented\",\"status\":501,\"code\":\"not_implemented\"}\n",
 		},
 	}
 
@@ -220,22 +229,60 @@ func Test_ResponserResponse(t *testing.T) {
 		name := c.testName
 		responser, recorder := c.buildResponser()
 		expectedHTTPCode := c.expectedHTTPCode
+		expectedContentType := c.expectedContentType
 		expectedResponse := c.expectedResponse
-		expectedError := c.expectedError
 
 		t.Run(name, func(t *testing.T) {
 			err := responser.Response()
 
-			assert.Equal(t, expectedError, err)
+			assert.NoError(t, err)
 			assert.Equal(t, expectedHTTPCode, recorder.Code)
+			assert.Equal(t, expectedContentType, recorder.Header().Get(echo.HeaderContentType))
 			assert.Equal(t, expectedResponse, recorder.Body.String())
 		})
 	}
 }
 
-/*
-	rec := httptest.NewRecorder()
-	t.Run(testName, func(t *testing.T) {
-		c := server.server.NewContext(req, rec)
+func Test_HandleError(t *testing.T) {
+	testCases := []struct {
+		testName         string
+		method           string
+		path             string
+		expectedHTTPCode int
+		expectedResponse string
+	}{
+		{
+			testName:         "unknown-route-case",
+			method:           http.MethodGet,
+			path:             "/unknown",
+			expectedHTTPCode: 404,
+			expectedResponse: "{\"type\":\"/problems/not_found\",\"title\":\"Not Found\",\"status\":404,\"instance\":\"/unknown\",\"code\":\"not_found\"}\n",
+		},
+		{
+			testName:         "method-not-allowed-case",
+			method:           http.MethodPut,
+			path:             "/example/write",
+			expectedHTTPCode: 405,
+			expectedResponse: "{\"type\":\"/problems/method_not_allowed\",\"title\":\"Method Not Allowed\",\"status\":405,\"instance\":\"/example/write\",\"code\":\"method_not_allowed\"}\n",
+		},
+	}
+
+	for _, c := range testCases {
+		name := c.testName
+		method := c.method
+		path := c.path
+		expectedHTTPCode := c.expectedHTTPCode
+		expectedResponse := c.expectedResponse
+
+		t.Run(name, func(t *testing.T) {
+			server := NewServer(context.Background(), app.Services{}, config{})
+
+			rec := httptest.NewRecorder()
+			server.server.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
 
-*/
+			assert.Equal(t, expectedHTTPCode, rec.Code)
+			assert.Equal(t, problem.MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+			assert.Equal(t, expectedResponse, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"clean-arquitecture-template/internal/app/example/queries"
+)
+
+const (
+	searchPath string = "/search"
+
+	searchRoute string = "search"
+
+	queryParam     string = "q"
+	offsetParam    string = "offset"
+	limitParam     string = "limit"
+	highlightParam string = "highlight"
+)
+
+type searchHit struct {
+	Line       interface{} `json:"line"`
+	Score      float64     `json:"score"`
+	Highlights []string    `json:"highlights,omitempty"`
+}
+
+type searchResponse struct {
+	Total  int64       `json:"total"`
+	Offset int         `json:"offset"`
+	Limit  int         `json:"limit"`
+	Hits   []searchHit `json:"hits"`
+}
+
+func (s Server) searchAppExamples(c echo.Context) error {
+	return s.searchExamples(c, v1)
+}
+
+func (s Server) searchAppExamplesV2(c echo.Context) error {
+	return s.searchExamples(c, v2)
+}
+
+func (s Server) searchExamples(c echo.Context, v version) error {
+	response := NewResponser(c)
+
+	req := queries.SearchExamplesRequest{Query: c.QueryParam(queryParam)}
+
+	var err error
+	if req.Offset, err = number(c, offsetParam); err != nil {
+		return response.WithError(err).Response()
+	}
+
+	if req.Limit, err = number(c, limitParam); err != nil {
+		return response.WithError(err).Response()
+	}
+
+	if req.Highlight, err = flag(c, highlightParam); err != nil {
+		return response.WithError(err).Response()
+	}
+
+	if req.IncludeDeleted, err = s.includeDeleted(c); err != nil {
+		return response.WithError(err).Response()
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), s.timeout(searchRoute))
+	defer cancel()
+
+	result, err := s.exampleServices.ExampleService.Queries.SearchExamplesHandler.Handle(ctx, req)
+	if err != nil {
+		return response.WithError(err).Response()
+	}
+
+	payload := searchResponse{
+		Total:  result.Total,
+		Offset: result.Offset,
+		Limit:  result.Limit,
+		Hits:   make([]searchHit, len(result.Hits)),
+	}
+
+	for i, hit := range result.Hits {
+		line := hit.GetExampleResult
+		payload.Hits[i] = searchHit{
+			Line:       v.readResponse(&line),
+			Score:      hit.Score,
+			Highlights: hit.Highlights,
+		}
+	}
+
+	return response.WithPayload(http.StatusOK, payload).Response()
+}
+
+// number reads an integer query parameter, zero when missing.
+func number(c echo.Context, name string) (int, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %s: %w", name, err.Error(), ErrInputParam)
+	}
+
+	return n, nil
+}
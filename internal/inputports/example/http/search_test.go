@@ -0,0 +1,108 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	app "clean-arquitecture-template/internal/app/example"
+	"clean-arquitecture-template/internal/app/example/queries"
+)
+
+type mockCommandSearchLinesHandler struct {
+	Handler func(context.Context, queries.SearchExamplesRequest) (*queries.SearchExamplesResult, error)
+}
+
+func (m mockCommandSearchLinesHandler) Handle(ctx context.Context, req queries.SearchExamplesRequest) (*queries.SearchExamplesResult, error) {
+	return m.Handler(ctx, req)
+}
+
+func Test_Search(t *testing.T) {
+	tstamp := time.Date(2018, time.September, 16, 10, 0, 0, 0, time.UTC)
+
+	var received queries.SearchExamplesRequest
+
+	services := app.Services{
+		ExampleService: app.ExampleServices{
+			Queries: app.Queries{
+				SearchExamplesHandler: mockCommandSearchLinesHandler{Handler: func(ctx context.Context, req queries.SearchExamplesRequest) (*queries.SearchExamplesResult, error) {
+					received = req
+
+					if req.Query == "bad" {
+						return nil, queries.ErrSearchQuery
+					}
+
+					hit := queries.SearchExampleHit{
+						GetExampleResult: queries.GetExampleResult{ID: "1000", CreatedAt: tstamp, Data: "brown fox"},
+						Score:            1.5,
+					}
+					if req.Highlight {
+						hit.Highlights = []string{"brown <em>fox</em>"}
+					}
+
+					return &queries.SearchExamplesResult{Hits: []queries.SearchExampleHit{hit}, Total: 4, Offset: req.Offset, Limit: 10}, nil
+				}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		testName         string
+		path             string
+		expectedHTTPCode int
+		expectedResponse string
+		expectedRequest  queries.SearchExamplesRequest
+	}{
+		{
+			testName:         "v2-case",
+			path:             "/v2/example/search?q=fox&offset=3&limit=10&highlight",
+			expectedHTTPCode: http.StatusOK,
+			expectedResponse: "{\"total\":4,\"offset\":3,\"limit\":10,\"hits\":[{\"line\":{\"id\":\"1000\",\"createdAt\":\"2018-09-16T10:00:00Z\",\"data\":\"brown fox\"},\"score\":1.5,\"highlights\":[\"brown \\u003cem\\u003efox\\u003c/em\\u003e\"]}]}\n",
+			expectedRequest:  queries.SearchExamplesRequest{Query: "fox", Offset: 3, Limit: 10, Highlight: true},
+		},
+		{
+			testName:         "v1-case",
+			path:             "/v1/example/search?q=fox",
+			expectedHTTPCode: http.StatusOK,
+			expectedResponse: "{\"total\":4,\"offset\":0,\"limit\":10,\"hits\":[{\"line\":{\"id\":\"1000\",\"created_at\":\"2018-09-16 10:00:00 +0000 UTC\",\"data\":\"brown fox\"},\"score\":1.5}]}\n",
+			expectedRequest:  queries.SearchExamplesRequest{Query: "fox"},
+		},
+		{
+			testName:         "invalid-query-case",
+			path:             "/v2/example/search?q=bad",
+			expectedHTTPCode: http.StatusBadRequest,
+			expectedRequest:  queries.SearchExamplesRequest{Query: "bad"},
+		},
+		{
+			testName:         "invalid-limit-case",
+			path:             "/v2/example/search?q=fox&limit=ten",
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+
+		t.Run(c.testName, func(t *testing.T) {
+			received = queries.SearchExamplesRequest{}
+			server := NewServer(context.Background(), services, config{})
+
+			req := httptest.NewRequest(http.MethodGet, c.path, nil)
+			rec := httptest.NewRecorder()
+
+			server.server.ServeHTTP(rec, req)
+
+			assert.Equal(t, c.expectedHTTPCode, rec.Code)
+			if c.expectedResponse != "" {
+				assert.Equal(t, echo.MIMEApplicationJSONCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+				assert.Equal(t, c.expectedResponse, rec.Body.String())
+			}
+			assert.Equal(t, c.expectedRequest, received)
+		})
+	}
+}
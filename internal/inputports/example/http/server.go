@@ -3,9 +3,13 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
@@ -18,7 +22,12 @@ const (
 	writePath string = "/write"
 	readPath  string = "/read/:id"
 
-	configPath string = "apps.example.input-ports.rest"
+	writeRoute string = "write"
+	readRoute  string = "read"
+
+	defaultRouteTimeout time.Duration = time.Second
+
+	ConfigNode string = "apps.example.input-ports.rest"
 
 	ErrReadConfig err = "unable to read config"
 )
@@ -31,35 +40,98 @@ func (e err) Error() string {
 
 type Config interface {
 	Address() string
+	Timeouts() map[string]time.Duration
+	OpenAPIValidation() bool
+	OpenAPIUI() bool
+	AdminToken() string
+	DrainDelay() time.Duration
+}
+
+type openAPIConfig struct {
+	Validate bool `json:"validate"`
+	UI       bool `json:"ui"`
 }
 
 type config struct {
-	Addr string `json:"address"`
-	Port string `json:"port"`
+	Addr          string            `json:"address"`
+	Port          string            `json:"port"`
+	RouteTimeouts map[string]string `json:"timeouts"`
+	OpenAPI       openAPIConfig     `json:"openapi"`
+	Admin         string            `json:"admin-token"`
+	Drain         string            `json:"drain-delay"`
+
+	timeouts   map[string]time.Duration
+	drainDelay time.Duration
 }
 
 func (cnf config) Address() string {
 	return fmt.Sprintf("%s:%s", cnf.Addr, cnf.Port)
 }
 
+func (cnf config) Timeouts() map[string]time.Duration {
+	return cnf.timeouts
+}
+
+func (cnf config) OpenAPIValidation() bool {
+	return cnf.OpenAPI.Validate
+}
+
+func (cnf config) OpenAPIUI() bool {
+	return cnf.OpenAPI.UI
+}
+
+// AdminToken is the token granting the admin only features, which are off
+// when it is empty.
+func (cnf config) AdminToken() string {
+	return cnf.Admin
+}
+
+// DrainDelay is how long the server keeps serving once it reports itself
+// not ready, so the load balancer has time to stop routing to it.
+func (cnf config) DrainDelay() time.Duration {
+	return cnf.drainDelay
+}
+
 type ConfigReader interface {
 	Find(node string) (io.Reader, error)
 }
 
 func ReadConfig(cnfr ConfigReader) (Config, error) {
-	reader, err := cnfr.Find(configPath)
+	reader, err := cnfr.Find(ConfigNode)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
 	}
 
+	cnf, err := parseConfig(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return cnf, nil
+}
+
+func parseConfig(reader io.Reader) (config, error) {
 	data, err := io.ReadAll(reader)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+		return config{}, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
 	}
 
 	cnf := config{}
 	if err = json.Unmarshal(data, &cnf); err != nil {
-		return nil, ErrReadConfig
+		return config{}, ErrReadConfig
+	}
+
+	cnf.timeouts = make(map[string]time.Duration, len(cnf.RouteTimeouts))
+	for route, timeout := range cnf.RouteTimeouts {
+		if cnf.timeouts[route], err = time.ParseDuration(timeout); err != nil {
+			return config{}, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+		}
+	}
+
+	if cnf.Drain != "" {
+		if cnf.drainDelay, err = time.ParseDuration(cnf.Drain); err != nil {
+			return config{}, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+		}
 	}
 
 	return cnf, nil
@@ -70,14 +142,36 @@ type Server struct {
 	exampleServices app.Services
 	server          *echo.Echo
 	address         string
+	timeouts        *routeTimeouts
+	health          *health
+	openAPIUI       bool
+	adminToken      string
+	drainDelay      time.Duration
 }
 
-func NewServer(ctx context.Context, appServices app.Services, cnf Config) Server {
+func NewServer(ctx context.Context, appServices app.Services, cnf Config, checkers ...HealthChecker) Server {
 	s := Server{
 		ctx:             ctx,
 		exampleServices: appServices,
 		server:          echo.New(),
 		address:         cnf.Address(),
+		timeouts:        newRouteTimeouts(cnf.Timeouts()),
+		health:          newHealth(checkers...),
+		openAPIUI:       cnf.OpenAPIUI(),
+		adminToken:      cnf.AdminToken(),
+		drainDelay:      cnf.DrainDelay(),
+	}
+
+	s.server.HTTPErrorHandler = handleError
+	s.server.Use(s.auditContext)
+
+	if cnf.OpenAPIValidation() {
+		validator, err := newOpenAPIValidator()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		s.server.Use(validator)
 	}
 
 	s.initApi()
@@ -86,14 +180,109 @@ func NewServer(ctx context.Context, appServices app.Services, cnf Config) Server
 }
 
 func (s Server) initApi() {
-	g := s.server.Group(exampleRoute)
+	s.server.GET(healthzPath, s.liveness)
+	s.server.GET(readyzPath, s.readiness)
+	s.server.GET(openAPIPath, s.openAPI)
+	s.server.GET(varsPath, vars, s.adminOnly)
 
-	g.POST(writePath, s.writeAppExample)
-	g.GET(readPath, s.readAppExample)
+	if s.openAPIUI {
+		s.server.GET(openAPIUIPath, s.openAPIDocs)
+	}
+
+	// The bare group predates the versioned ones and keeps serving v1.
+	for _, prefix := range []string{"", v1Route} {
+		g := s.server.Group(prefix + exampleRoute)
+		deprecation := deprecated(v2Route + exampleRoute)
+
+		g.POST(writePath, s.writeAppExample, deprecation)
+		g.GET(readPath, s.readAppExample, deprecation)
+		g.POST(batchWritePath, s.writeAppExamples, deprecation)
+		g.POST(batchReadPath, s.readAppExamples, deprecation)
+		g.GET(searchPath, s.searchAppExamples, deprecation)
+		g.GET(listPath, s.listAppExamples, deprecation)
+		g.DELETE(deletePath, s.deleteAppExample, deprecation)
+		g.POST(restorePath, s.restoreAppExample, deprecation)
+		g.GET(historyPath, s.historyAppExample, deprecation)
+	}
+
+	g := s.server.Group(v2Route + exampleRoute)
+
+	g.POST(writePath, s.writeAppExampleV2)
+	g.GET(readPath, s.readAppExampleV2)
+	g.POST(batchWritePath, s.writeAppExamplesV2)
+	g.POST(batchReadPath, s.readAppExamplesV2)
+	g.GET(searchPath, s.searchAppExamplesV2)
+	g.GET(listPath, s.listAppExamplesV2)
+	g.DELETE(deletePath, s.deleteAppExample)
+	g.POST(restorePath, s.restoreAppExample)
+	g.GET(historyPath, s.historyAppExampleV2)
+	g.GET(exportPath, s.exportAppExamples, s.adminOnly)
+	g.POST(importPath, s.importAppExamples, s.adminOnly)
+}
+
+// routeTimeouts is shared by the copies of a Server, so the timeouts of a
+// config reload reach every handler.
+type routeTimeouts struct {
+	timeouts atomic.Value
+}
+
+func newRouteTimeouts(timeouts map[string]time.Duration) *routeTimeouts {
+	rt := &routeTimeouts{}
+	rt.timeouts.Store(timeouts)
+
+	return rt
+}
+
+// timeout returns the configured timeout for route, or the default one when
+// the route has none.
+func (s Server) timeout(route string) time.Duration {
+	if s.timeouts == nil {
+		return defaultRouteTimeout
+	}
+
+	timeouts, _ := s.timeouts.timeouts.Load().(map[string]time.Duration)
+	if timeout, exists := timeouts[route]; exists && timeout > 0 {
+		return timeout
+	}
+
+	return defaultRouteTimeout
+}
+
+// ApplyConfig validates the rest config read from r and applies its route
+// timeouts, the rest of it is only read on start.
+func (s Server) ApplyConfig(r io.Reader) error {
+	cnf, err := parseConfig(r)
+	if err != nil {
+		return err
+	}
+
+	s.timeouts.timeouts.Store(cnf.Timeouts())
+
+	return nil
 }
 
 func (s Server) ListenAndServe() {
 	err := s.server.Start(s.address)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
+
+// Shutdown reports the server as not ready and keeps serving for the drain
+// delay, or until ctx is done, so the load balancer stops routing new traffic
+// to it before the listeners are closed and the in-flight requests drained.
+func (s Server) Shutdown(ctx context.Context) error {
+	s.health.shutdown()
+
+	if s.drainDelay > 0 {
+		timer := time.NewTimer(s.drainDelay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+	}
 
-	log.Fatal(err)
+	return s.server.Shutdown(ctx)
 }
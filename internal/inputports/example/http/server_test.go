@@ -1,12 +1,18 @@
 package http
 
 import (
+	"context"
 	"errors"
 	"io"
+	"net"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	app "clean-arquitecture-template/internal/app/example"
 )
 
 type configReaderMock struct {
@@ -27,10 +33,15 @@ func (rm readerMock) Read(p []byte) (n int, err error) {
 
 func Test_ReadConfig(t *testing.T) {
 	testCases := []struct {
-		testName        string
-		configReader    func(node string) (io.Reader, error)
-		expectedAddress string
-		expectedError   error
+		testName           string
+		configReader       func(node string) (io.Reader, error)
+		expectedAddress    string
+		expectedTimeouts   map[string]time.Duration
+		expectedValidation bool
+		expectedUI         bool
+		expectedAdminToken string
+		expectedDrainDelay time.Duration
+		expectedError      error
 	}{
 		{
 			testName: "error-read-config-case",
@@ -67,14 +78,96 @@ func Test_ReadConfig(t *testing.T) {
 				}`)
 				return r, nil
 			},
+			expectedAddress:  "127.0.0.1:8080",
+			expectedTimeouts: map[string]time.Duration{},
+			expectedError:    nil,
+		},
+		{
+			testName: "timeouts-case",
+			configReader: func(node string) (io.Reader, error) {
+				r := strings.NewReader(`{
+					"address": "127.0.0.1",
+					"port":    "8080",
+					"timeouts": {"write": "2s", "read": "500ms"}
+				}`)
+				return r, nil
+			},
 			expectedAddress: "127.0.0.1:8080",
-			expectedError:   nil,
+			expectedTimeouts: map[string]time.Duration{
+				writeRoute: 2 * time.Second,
+				readRoute:  500 * time.Millisecond,
+			},
+			expectedError: nil,
+		},
+		{
+			testName: "openapi-case",
+			configReader: func(node string) (io.Reader, error) {
+				r := strings.NewReader(`{
+					"address": "127.0.0.1",
+					"port":    "8080",
+					"openapi": {"validate": true, "ui": true},
+					"admin-token": "secret"
+				}`)
+				return r, nil
+			},
+			expectedAddress:    "127.0.0.1:8080",
+			expectedTimeouts:   map[string]time.Duration{},
+			expectedValidation: true,
+			expectedUI:         true,
+			expectedAdminToken: "secret",
+			expectedError:      nil,
+		},
+		{
+			testName: "drain-delay-case",
+			configReader: func(node string) (io.Reader, error) {
+				r := strings.NewReader(`{
+					"address": "127.0.0.1",
+					"port":    "8080",
+					"drain-delay": "5s"
+				}`)
+				return r, nil
+			},
+			expectedAddress:    "127.0.0.1:8080",
+			expectedTimeouts:   map[string]time.Duration{},
+			expectedDrainDelay: 5 * time.Second,
+			expectedError:      nil,
+		},
+		{
+			testName: "invalid-drain-delay-case",
+			configReader: func(node string) (io.Reader, error) {
+				r := strings.NewReader(`{
+					"address": "127.0.0.1",
+					"port":    "8080",
+					"drain-delay": "five seconds"
+				}`)
+				return r, nil
+			},
+			expectedAddress: "",
+			expectedError:   ErrReadConfig,
+		},
+		{
+			testName: "invalid-timeout-case",
+			configReader: func(node string) (io.Reader, error) {
+				r := strings.NewReader(`{
+					"address": "127.0.0.1",
+					"port":    "8080",
+					"timeouts": {"write": "two seconds"}
+				}`)
+				return r, nil
+			},
+			expectedAddress: "",
+			expectedError:   ErrReadConfig,
 		},
 	}
 
 	for _, c := range testCases {
 		name := c.testName
 		expectedAddres := c.expectedAddress
+		expectedTimeouts := c.expectedTimeouts
+		expectedValidation := c.expectedValidation
+		expectedUI := c.expectedUI
+		expectedAdminToken := c.expectedAdminToken
+		expectedDrainDelay := c.expectedDrainDelay
 		expectedError := c.expectedError
 
 		mock := configReaderMock{
@@ -88,8 +181,73 @@ func Test_ReadConfig(t *testing.T) {
 				assert.ErrorIs(t, err, expectedError)
 			} else {
 				assert.Equal(t, expectedAddres, config.Address())
+				assert.Equal(t, expectedTimeouts, config.Timeouts())
+				assert.Equal(t, expectedValidation, config.OpenAPIValidation())
+				assert.Equal(t, expectedUI, config.OpenAPIUI())
+				assert.Equal(t, expectedAdminToken, config.AdminToken())
+				assert.Equal(t, expectedDrainDelay, config.DrainDelay())
 				assert.ErrorIs(t, err, expectedError)
 			}
 		})
 	}
 }
+
+func Test_Shutdown(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	server := NewServer(context.Background(), app.Services{}, config{drainDelay: 200 * time.Millisecond})
+	server.server.Listener = ln
+	go server.ListenAndServe()
+
+	url := "http://" + ln.Addr().String()
+	get := func(path string) (int, error) {
+		res, err := http.Get(url + path)
+		if err != nil {
+			return 0, err
+		}
+		defer res.Body.Close()
+
+		return res.StatusCode, nil
+	}
+
+	assert.Eventually(t, func() bool {
+		code, err := get(readyzPath)
+		return err == nil && code == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Shutdown(context.Background())
+	}()
+
+	assert.Eventually(t, server.health.shuttingDown.Load, time.Second, time.Millisecond)
+
+	code, err := get(readyzPath)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	code, err = get(healthzPath)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+
+	assert.NoError(t, <-done)
+
+	_, err = get(healthzPath)
+	assert.Error(t, err)
+}
+
+func Test_ShutdownContextDone(t *testing.T) {
+	server := NewServer(context.Background(), app.Services{}, config{drainDelay: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	server.Shutdown(ctx)
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.True(t, server.health.shuttingDown.Load())
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+
+	"clean-arquitecture-template/internal/app/example/commands"
+	"clean-arquitecture-template/internal/app/example/queries"
+	"clean-arquitecture-template/internal/inputports/example/ndjson"
+	"clean-arquitecture-template/internal/inputports/problem"
+)
+
+/**************************************************
+* This file constains the streaming endpoints     *
+* moving every line in and out as NDJSON          *
+***************************************************/
+
+const (
+	exportPath string = "/export"
+	importPath string = "/import"
+
+	keepIDsParam        string = "keep_ids"
+	keepTimestampsParam string = "keep_timestamps"
+)
+
+type importFailure struct {
+	Line  int             `json:"line"`
+	ID    string          `json:"id,omitempty"`
+	Error problem.Problem `json:"error"`
+}
+
+type importReport struct {
+	Read     int64           `json:"read"`
+	Written  int64           `json:"written"`
+	Failed   int64           `json:"failed"`
+	Failures []importFailure `json:"failures"`
+}
+
+// importProblem is the problem of an aborted import along with the report of
+// the lines handled before, so the client knows which were written.
+type importProblem struct {
+	problem.Problem
+	Report importReport `json:"report"`
+}
+
+func newImportReport(c echo.Context, report commands.ImportReport) importReport {
+	payload := importReport{
+		Read:     report.Read,
+		Written:  report.Written,
+		Failed:   report.Failed,
+		Failures: make([]importFailure, len(report.Failures)),
+	}
+
+	for i, failure := range report.Failures {
+		payload.Failures[i] = importFailure{
+			Line:  failure.Position,
+			ID:    failure.ID,
+			Error: newProblem(failure.Err, instance(c)),
+		}
+	}
+
+	return payload
+}
+
+// exportAppExamples streams every line as it is read from the store. Once
+// the first record is sent the status can no longer change, so a failure
+// midway is only logged and the client sees a truncated stream.
+func (s Server) exportAppExamples(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	cursor, err := s.exampleServices.ExampleService.Queries.ExportExamplesHandler.Handle(ctx, queries.ExportExamplesRequest{})
+	if err != nil {
+		return NewResponser(c).WithError(err).Response()
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, ndjson.MIMEApplicationNDJSON)
+	res.WriteHeader(http.StatusOK)
+
+	written, err := ndjson.Export(ctx, res, cursor)
+	if err != nil {
+		log.WithError(err).WithField("written", written).Error("export interrupted")
+	}
+
+	return nil
+}
+
+// importAppExamples writes the lines of an NDJSON body as they arrive. The
+// body is read no faster than the store writes. An aborted import still
+// reports the lines handled before it stopped.
+func (s Server) importAppExamples(c echo.Context) error {
+	response := NewResponser(c)
+
+	mediaType, _, err := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if err != nil || mediaType != ndjson.MIMEApplicationNDJSON {
+		return response.WithError(fmt.Errorf("%s: %w", mediaType, ErrUnsupportedMediaType)).Response()
+	}
+
+	command := commands.ImportExamplesRequest{
+		Source: ndjson.NewSource(c.Request().Body),
+		Progress: func(p commands.ImportProgress) {
+			log.WithField("read", p.Read).WithField("written", p.Written).WithField("failed", p.Failed).Debug("import progress")
+		},
+	}
+
+	if command.KeepIDs, err = flag(c, keepIDsParam); err != nil {
+		return response.WithError(err).Response()
+	}
+
+	if command.KeepTimestamps, err = flag(c, keepTimestampsParam); err != nil {
+		return response.WithError(err).Response()
+	}
+
+	report, err := s.exampleServices.ExampleService.Commands.ImportExamplesHandler.Handle(c.Request().Context(), command)
+	if err != nil {
+		log.WithField("read", report.Read).WithField("written", report.Written).WithField("failed", report.Failed).Warn("import aborted")
+
+		return response.WithExtendedError(err, func(p problem.Problem) interface{} {
+			return importProblem{Problem: p, Report: newImportReport(c, report)}
+		}).Response()
+	}
+
+	return response.WithPayload(http.StatusOK, newImportReport(c, report)).Response()
+}
+
+// flag reads a boolean query parameter, false when missing and true when
+// given without a value.
+func flag(c echo.Context, name string) (bool, error) {
+	values, exists := c.QueryParams()[name]
+	if !exists {
+		return false, nil
+	}
+
+	if len(values) == 0 || values[0] == "" {
+		return true, nil
+	}
+
+	value, err := strconv.ParseBool(values[0])
+	if err != nil {
+		return false, fmt.Errorf("%s: %s: %w", name, err.Error(), ErrInputParam)
+	}
+
+	return value, nil
+}
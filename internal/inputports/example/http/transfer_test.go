@@ -0,0 +1,220 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	app "clean-arquitecture-template/internal/app/example"
+	"clean-arquitecture-template/internal/app/example/commands"
+	"clean-arquitecture-template/internal/app/example/queries"
+	"clean-arquitecture-template/internal/domain/example"
+	"clean-arquitecture-template/internal/inputports/example/ndjson"
+)
+
+type mockCommandImportLinesHandler struct {
+	Handler func(context.Context, commands.ImportExamplesRequest) (commands.ImportReport, error)
+}
+
+func (m mockCommandImportLinesHandler) Handle(ctx context.Context, command commands.ImportExamplesRequest) (commands.ImportReport, error) {
+	return m.Handler(ctx, command)
+}
+
+func Test_Export(t *testing.T) {
+	tstamp := time.Date(2018, time.September, 16, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		testName            string
+		repo                func() *example.MockRepository
+		expectedHTTPCode    int
+		expectedContentType string
+		expectedResponse    string
+	}{
+		{
+			testName: "lines-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Scan", mock.Anything).Return(&example.MockCursor{Lines: []example.Line{
+					{ID: example.MockIdentifier("one"), Created: tstamp, Data: "first-line"},
+					{ID: example.MockIdentifier("two"), Created: tstamp, Data: "second-line"},
+				}}, nil)
+
+				return mr
+			},
+			expectedHTTPCode:    http.StatusOK,
+			expectedContentType: ndjson.MIMEApplicationNDJSON,
+			expectedResponse: "{\"id\":\"one\",\"createdAt\":\"2018-09-16T10:00:00Z\",\"data\":\"first-line\"}\n" +
+				"{\"id\":\"two\",\"createdAt\":\"2018-09-16T10:00:00Z\",\"data\":\"second-line\"}\n",
+		},
+		{
+			testName: "truncated-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Scan", mock.Anything).Return(&example.MockCursor{
+					Lines: []example.Line{{ID: example.MockIdentifier("one"), Created: tstamp, Data: "first-line"}},
+					Error: example.ErrTimeout,
+				}, nil)
+
+				return mr
+			},
+			expectedHTTPCode:    http.StatusOK,
+			expectedContentType: ndjson.MIMEApplicationNDJSON,
+			expectedResponse:    "{\"id\":\"one\",\"createdAt\":\"2018-09-16T10:00:00Z\",\"data\":\"first-line\"}\n",
+		},
+		{
+			testName: "scan-error-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Scan", mock.Anything).Return((*example.MockCursor)(nil), errors.New("some-error"))
+
+				return mr
+			},
+			expectedHTTPCode:    http.StatusInternalServerError,
+			expectedContentType: "application/problem+json",
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+
+		t.Run(c.testName, func(t *testing.T) {
+			services := app.Services{
+				ExampleService: app.ExampleServices{
+					Queries: app.Queries{
+						ExportExamplesHandler: queries.NewExportExamplesRequestHandler(c.repo()),
+					},
+				},
+			}
+			server := NewServer(context.Background(), services, config{Admin: "secret", OpenAPI: openAPIConfig{Validate: true}})
+
+			req := httptest.NewRequest(http.MethodGet, v2Route+exampleRoute+exportPath, nil)
+			req.Header.Set(headerAdminToken, "secret")
+			rec := httptest.NewRecorder()
+
+			server.server.ServeHTTP(rec, req)
+
+			assert.Equal(t, c.expectedHTTPCode, rec.Code)
+			assert.Equal(t, c.expectedContentType, rec.Header().Get(echo.HeaderContentType))
+			if c.expectedResponse != "" {
+				assert.Equal(t, c.expectedResponse, rec.Body.String())
+			}
+		})
+	}
+}
+
+func Test_Import(t *testing.T) {
+	var received commands.ImportExamplesRequest
+
+	services := app.Services{
+		ExampleService: app.ExampleServices{
+			Commands: app.Commands{
+				ImportExamplesHandler: mockCommandImportLinesHandler{Handler: func(ctx context.Context, command commands.ImportExamplesRequest) (commands.ImportReport, error) {
+					received = command
+
+					report := commands.ImportReport{}
+					for {
+						item, err := command.Source.Next()
+						if errors.Is(err, io.EOF) {
+							return report, nil
+						}
+
+						if err != nil {
+							return report, commands.ErrImportSource
+						}
+
+						if item.Data == "abort" {
+							return report, commands.ErrUnavailable
+						}
+
+						report.Read++
+						if item.Err != nil {
+							report.Failed++
+							report.Failures = append(report.Failures, commands.ImportFailure{Position: item.Position, Err: commands.ErrInvalidLine})
+						} else {
+							report.Written++
+						}
+					}
+				}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		testName               string
+		query                  string
+		contentType            string
+		body                   string
+		expectedHTTPCode       int
+		expectedResponse       string
+		expectedKeepIDs        bool
+		expectedKeepTimestamps bool
+	}{
+		{
+			testName:         "report-case",
+			contentType:      ndjson.MIMEApplicationNDJSON,
+			body:             "{\"id\":\"one\",\"createdAt\":\"2018-09-16T10:00:00Z\",\"data\":\"first-line\"}\n\n{bad}\n{\"data\":\"third-line\"}\n",
+			expectedHTTPCode: http.StatusOK,
+			expectedResponse: "{\"read\":3,\"written\":2,\"failed\":1,\"failures\":[{\"line\":3,\"error\":{\"type\":\"/problems/invalid_line\",\"title\":\"Invalid line\",\"status\":400,\"detail\":\"invalid line\",\"instance\":\"/v2/example/import\",\"code\":\"invalid_line\"}}]}\n",
+		},
+		{
+			testName:         "aborted-case",
+			contentType:      ndjson.MIMEApplicationNDJSON,
+			body:             "{\"data\":\"first-line\"}\n{bad}\n{\"data\":\"abort\"}\n",
+			expectedHTTPCode: http.StatusServiceUnavailable,
+			expectedResponse: "{\"type\":\"/problems/unavailable\",\"title\":\"Service unavailable\",\"status\":503,\"instance\":\"/v2/example/import\",\"code\":\"unavailable\",\"report\":{\"read\":2,\"written\":1,\"failed\":1,\"failures\":[{\"line\":2,\"error\":{\"type\":\"/problems/invalid_line\",\"title\":\"Invalid line\",\"status\":400,\"detail\":\"invalid line\",\"instance\":\"/v2/example/import\",\"code\":\"invalid_line\"}}]}}\n",
+		},
+		{
+			testName:               "keep-case",
+			query:                  "?keep_ids&keep_timestamps=true",
+			contentType:            ndjson.MIMEApplicationNDJSON,
+			expectedHTTPCode:       http.StatusOK,
+			expectedResponse:       "{\"read\":0,\"written\":0,\"failed\":0,\"failures\":[]}\n",
+			expectedKeepIDs:        true,
+			expectedKeepTimestamps: true,
+		},
+		{
+			testName:         "invalid-flag-case",
+			query:            "?keep_ids=maybe",
+			contentType:      ndjson.MIMEApplicationNDJSON,
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+		{
+			testName:         "unsupported-body-case",
+			contentType:      echo.MIMEApplicationJSON,
+			body:             "[]",
+			expectedHTTPCode: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+
+		t.Run(c.testName, func(t *testing.T) {
+			received = commands.ImportExamplesRequest{}
+			server := NewServer(context.Background(), services, config{Admin: "secret", OpenAPI: openAPIConfig{Validate: true}})
+
+			req := httptest.NewRequest(http.MethodPost, v2Route+exampleRoute+importPath+c.query, strings.NewReader(c.body))
+			req.Header.Set(headerAdminToken, "secret")
+			req.Header.Set(echo.HeaderContentType, c.contentType)
+			rec := httptest.NewRecorder()
+
+			server.server.ServeHTTP(rec, req)
+
+			assert.Equal(t, c.expectedHTTPCode, rec.Code)
+			if c.expectedResponse != "" {
+				assert.Equal(t, c.expectedResponse, rec.Body.String())
+			}
+			assert.Equal(t, c.expectedKeepIDs, received.KeepIDs)
+			assert.Equal(t, c.expectedKeepTimestamps, received.KeepTimestamps)
+		})
+	}
+}
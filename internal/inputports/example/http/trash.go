@@ -0,0 +1,85 @@
+package http
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+
+	"clean-arquitecture-template/internal/app/example/commands"
+	"clean-arquitecture-template/internal/inputports/problem"
+)
+
+const (
+	deletePath  string = "/:id"
+	restorePath string = "/:id/restore"
+
+	deleteRoute  string = "delete"
+	restoreRoute string = "restore"
+
+	includeDeletedParam string = "include_deleted"
+
+	headerAdminToken string = "X-Admin-Token"
+
+	ErrForbidden = problem.ErrForbidden
+)
+
+func (s Server) deleteAppExample(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), s.timeout(deleteRoute))
+	defer cancel()
+
+	response := NewResponser(c)
+
+	err := s.exampleServices.ExampleService.Commands.DeleteExampleHandler.Handle(ctx, commands.DeleteExampleRequest{ID: c.Param("id")})
+	if err != nil {
+		return response.WithError(err).Response()
+	}
+
+	return response.WithNoContent().Response()
+}
+
+func (s Server) restoreAppExample(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), s.timeout(restoreRoute))
+	defer cancel()
+
+	response := NewResponser(c)
+
+	err := s.exampleServices.ExampleService.Commands.RestoreExampleHandler.Handle(ctx, commands.RestoreExampleRequest{ID: c.Param("id")})
+	if err != nil {
+		return response.WithError(err).Response()
+	}
+
+	return response.WithNoContent().Response()
+}
+
+// includeDeleted reads the include_deleted flag, which only admins may set.
+func (s Server) includeDeleted(c echo.Context) (bool, error) {
+	include, err := flag(c, includeDeletedParam)
+	if err != nil || !include {
+		return false, err
+	}
+
+	if !s.admin(c) {
+		return false, fmt.Errorf("%s: admin token required: %w", includeDeletedParam, ErrForbidden)
+	}
+
+	return true, nil
+}
+
+func (s Server) admin(c echo.Context) bool {
+	token := c.Request().Header.Get(headerAdminToken)
+
+	return s.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1
+}
+
+// adminOnly lets through the requests carrying the admin token only.
+func (s Server) adminOnly(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !s.admin(c) {
+			return NewResponser(c).WithError(fmt.Errorf("%s %s: admin token required: %w", c.Request().Method, c.Path(), ErrForbidden)).Response()
+		}
+
+		return next(c)
+	}
+}
@@ -0,0 +1,279 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	app "clean-arquitecture-template/internal/app/example"
+	"clean-arquitecture-template/internal/app/example/commands"
+	"clean-arquitecture-template/internal/app/example/queries"
+)
+
+type mockCommandDeleteLineHandler struct {
+	Handler func(context.Context, commands.DeleteExampleRequest) error
+}
+
+func (m mockCommandDeleteLineHandler) Handle(ctx context.Context, command commands.DeleteExampleRequest) error {
+	return m.Handler(ctx, command)
+}
+
+type mockCommandRestoreLineHandler struct {
+	Handler func(context.Context, commands.RestoreExampleRequest) error
+}
+
+func (m mockCommandRestoreLineHandler) Handle(ctx context.Context, command commands.RestoreExampleRequest) error {
+	return m.Handler(ctx, command)
+}
+
+func Test_DeleteRestore(t *testing.T) {
+	result := func(id string) error {
+		switch id {
+		case "missing":
+			return commands.ErrNotFound
+		case "bad":
+			return commands.ErrInvalidID
+		}
+
+		return nil
+	}
+
+	services := app.Services{
+		ExampleService: app.ExampleServices{
+			Commands: app.Commands{
+				DeleteExampleHandler: mockCommandDeleteLineHandler{Handler: func(ctx context.Context, command commands.DeleteExampleRequest) error {
+					return result(command.ID)
+				}},
+				RestoreExampleHandler: mockCommandRestoreLineHandler{Handler: func(ctx context.Context, command commands.RestoreExampleRequest) error {
+					return result(command.ID)
+				}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		testName           string
+		method             string
+		path               string
+		expectedHTTPCode   int
+		expectedDeprecated bool
+	}{
+		{
+			testName:         "v2-delete-case",
+			method:           http.MethodDelete,
+			path:             "/v2/example/1000",
+			expectedHTTPCode: http.StatusNoContent,
+		},
+		{
+			testName:           "v1-delete-case",
+			method:             http.MethodDelete,
+			path:               "/v1/example/1000",
+			expectedHTTPCode:   http.StatusNoContent,
+			expectedDeprecated: true,
+		},
+		{
+			testName:           "legacy-delete-not-found-case",
+			method:             http.MethodDelete,
+			path:               "/example/missing",
+			expectedHTTPCode:   http.StatusNotFound,
+			expectedDeprecated: true,
+		},
+		{
+			testName:         "v2-restore-case",
+			method:           http.MethodPost,
+			path:             "/v2/example/1000/restore",
+			expectedHTTPCode: http.StatusNoContent,
+		},
+		{
+			testName:         "v2-restore-invalid-id-case",
+			method:           http.MethodPost,
+			path:             "/v2/example/bad/restore",
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+
+		t.Run(c.testName, func(t *testing.T) {
+			server := NewServer(context.Background(), services, config{OpenAPI: openAPIConfig{Validate: true}})
+
+			req := httptest.NewRequest(c.method, c.path, nil)
+			rec := httptest.NewRecorder()
+
+			server.server.ServeHTTP(rec, req)
+
+			assert.Equal(t, c.expectedHTTPCode, rec.Code)
+			if c.expectedHTTPCode == http.StatusNoContent {
+				assert.Empty(t, rec.Body.String())
+			}
+			assert.Equal(t, c.expectedDeprecated, rec.Header().Get(headerDeprecation) == "true")
+		})
+	}
+}
+
+func Test_IncludeDeleted(t *testing.T) {
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+
+	var received queries.GetExampleRequest
+
+	services := app.Services{
+		ExampleService: app.ExampleServices{
+			Queries: app.Queries{
+				ReadExampleHandler: mockCommandReadLineHandler{Handler: func(ctx context.Context, req queries.GetExampleRequest) (*queries.GetExampleResult, error) {
+					received = req
+
+					return &queries.GetExampleResult{ID: "1000", CreatedAt: tstamp, Data: "first-line", DeletedAt: tstamp}, nil
+				}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		testName         string
+		adminToken       string
+		path             string
+		token            string
+		expectedHTTPCode int
+		expectedResponse string
+		expectedRequest  queries.GetExampleRequest
+	}{
+		{
+			testName:         "admin-v2-case",
+			adminToken:       "secret",
+			path:             "/v2/example/read/1000?include_deleted",
+			token:            "secret",
+			expectedHTTPCode: http.StatusOK,
+			expectedResponse: "{\"id\":\"1000\",\"createdAt\":\"2018-09-16T12:00:00Z\",\"data\":\"first-line\",\"deletedAt\":\"2018-09-16T12:00:00Z\"}\n",
+			expectedRequest:  queries.GetExampleRequest{ID: "1000", IncludeDeleted: true},
+		},
+		{
+			testName:         "admin-v1-case",
+			adminToken:       "secret",
+			path:             "/v1/example/read/1000?include_deleted=true",
+			token:            "secret",
+			expectedHTTPCode: http.StatusOK,
+			expectedResponse: "{\"id\":\"1000\",\"created_at\":\"2018-09-16 12:00:00 +0000 UTC\",\"data\":\"first-line\",\"deleted_at\":\"2018-09-16 12:00:00 +0000 UTC\"}\n",
+			expectedRequest:  queries.GetExampleRequest{ID: "1000", IncludeDeleted: true},
+		},
+		{
+			testName:         "flag-off-case",
+			path:             "/v2/example/read/1000?include_deleted=false",
+			expectedHTTPCode: http.StatusOK,
+			expectedRequest:  queries.GetExampleRequest{ID: "1000"},
+		},
+		{
+			testName:         "wrong-token-case",
+			adminToken:       "secret",
+			path:             "/v2/example/read/1000?include_deleted",
+			token:            "guess",
+			expectedHTTPCode: http.StatusForbidden,
+		},
+		{
+			testName:         "no-admin-token-configured-case",
+			path:             "/v2/example/read/1000?include_deleted",
+			expectedHTTPCode: http.StatusForbidden,
+		},
+		{
+			testName:         "invalid-flag-case",
+			adminToken:       "secret",
+			path:             "/v2/example/read/1000?include_deleted=maybe",
+			token:            "secret",
+			expectedHTTPCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+
+		t.Run(c.testName, func(t *testing.T) {
+			received = queries.GetExampleRequest{}
+			server := NewServer(context.Background(), services, config{Admin: c.adminToken, OpenAPI: openAPIConfig{Validate: true}})
+
+			req := httptest.NewRequest(http.MethodGet, c.path, nil)
+			if c.token != "" {
+				req.Header.Set(headerAdminToken, c.token)
+			}
+			rec := httptest.NewRecorder()
+
+			server.server.ServeHTTP(rec, req)
+
+			assert.Equal(t, c.expectedHTTPCode, rec.Code)
+			if c.expectedResponse != "" {
+				assert.Equal(t, c.expectedResponse, rec.Body.String())
+			}
+			assert.Equal(t, c.expectedRequest, received)
+		})
+	}
+}
+
+func Test_AdminOnly(t *testing.T) {
+	testCases := []struct {
+		testName         string
+		adminToken       string
+		method           string
+		path             string
+		token            string
+		expectedHTTPCode int
+	}{
+		{
+			testName:         "vars-admin-case",
+			adminToken:       "secret",
+			method:           http.MethodGet,
+			path:             varsPath,
+			token:            "secret",
+			expectedHTTPCode: http.StatusOK,
+		},
+		{
+			testName:         "vars-wrong-token-case",
+			adminToken:       "secret",
+			method:           http.MethodGet,
+			path:             varsPath,
+			token:            "guess",
+			expectedHTTPCode: http.StatusForbidden,
+		},
+		{
+			testName:         "vars-no-admin-token-configured-case",
+			method:           http.MethodGet,
+			path:             varsPath,
+			expectedHTTPCode: http.StatusForbidden,
+		},
+		{
+			testName:         "export-without-token-case",
+			adminToken:       "secret",
+			method:           http.MethodGet,
+			path:             v2Route + exampleRoute + exportPath,
+			expectedHTTPCode: http.StatusForbidden,
+		},
+		{
+			testName:         "import-wrong-token-case",
+			adminToken:       "secret",
+			method:           http.MethodPost,
+			path:             v2Route + exampleRoute + importPath + "?keep_ids&keep_timestamps",
+			token:            "guess",
+			expectedHTTPCode: http.StatusForbidden,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+
+		t.Run(c.testName, func(t *testing.T) {
+			server := NewServer(context.Background(), app.Services{}, config{Admin: c.adminToken, OpenAPI: openAPIConfig{Validate: true}})
+
+			req := httptest.NewRequest(c.method, c.path, nil)
+			if c.token != "" {
+				req.Header.Set(headerAdminToken, c.token)
+			}
+			rec := httptest.NewRecorder()
+
+			server.server.ServeHTTP(rec, req)
+
+			assert.Equal(t, c.expectedHTTPCode, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package http
+
+import (
+	"expvar"
+
+	"github.com/labstack/echo/v4"
+)
+
+const varsPath string = "/debug/vars"
+
+// vars serves the variables published with expvar, the cache stats among
+// them, to admins only since they include the command line.
+var vars = echo.WrapHandler(expvar.Handler())
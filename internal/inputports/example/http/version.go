@@ -0,0 +1,140 @@
+package http
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"clean-arquitecture-template/internal/app/example/queries"
+)
+
+const (
+	v1Route string = "/v1"
+	v2Route string = "/v2"
+
+	headerDeprecation string = "Deprecation"
+	headerLink        string = "Link"
+)
+
+// version renders the results of the application services in the shape of
+// one API version, so every version is served by the same handlers.
+type version struct {
+	writeResponse   func(id string) interface{}
+	readResponse    func(result *queries.GetExampleResult) interface{}
+	historyResponse func(record *queries.HistoryRecord) interface{}
+}
+
+var (
+	v1 = version{
+		writeResponse: func(id string) interface{} {
+			return WriteExampleResponse{NewID: id}
+		},
+		readResponse: readResponseV1,
+		historyResponse: func(record *queries.HistoryRecord) interface{} {
+			return historyRecordResponse{
+				Action:    record.Action,
+				Actor:     record.Actor,
+				RequestID: record.RequestID,
+				At:        record.At.String(),
+				Before:    optionalResponse(readResponseV1, record.Before),
+				After:     optionalResponse(readResponseV1, record.After),
+			}
+		},
+	}
+
+	v2 = version{
+		writeResponse: func(id string) interface{} {
+			return WriteExampleResponseV2{NewID: id}
+		},
+		readResponse: readResponseV2,
+		historyResponse: func(record *queries.HistoryRecord) interface{} {
+			return historyRecordResponseV2{
+				Action:    record.Action,
+				Actor:     record.Actor,
+				RequestID: record.RequestID,
+				At:        record.At.UTC().Format(time.RFC3339Nano),
+				Before:    optionalResponse(readResponseV2, record.Before),
+				After:     optionalResponse(readResponseV2, record.After),
+			}
+		},
+	}
+)
+
+func readResponseV1(result *queries.GetExampleResult) interface{} {
+	response := readAppExampleResponse{
+		ID:        result.ID,
+		CreatedAT: result.CreatedAt.String(),
+		Data:      result.Data,
+	}
+
+	if !result.DeletedAt.IsZero() {
+		response.DeletedAT = result.DeletedAt.String()
+	}
+
+	return response
+}
+
+func readResponseV2(result *queries.GetExampleResult) interface{} {
+	response := readAppExampleResponseV2{
+		ID:        result.ID,
+		CreatedAt: result.CreatedAt.UTC().Format(time.RFC3339Nano),
+		Data:      result.Data,
+	}
+
+	if !result.DeletedAt.IsZero() {
+		response.DeletedAt = result.DeletedAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	return response
+}
+
+type WriteExampleResponseV2 struct {
+	NewID string `json:"newId"`
+}
+
+type readAppExampleResponseV2 struct {
+	ID        string `json:"id"`
+	CreatedAt string `json:"createdAt"`
+	Data      string `json:"data"`
+	DeletedAt string `json:"deletedAt,omitempty"`
+}
+
+type historyRecordResponse struct {
+	Action    string      `json:"action"`
+	Actor     string      `json:"actor"`
+	RequestID string      `json:"request_id"`
+	At        string      `json:"at"`
+	Before    interface{} `json:"before,omitempty"`
+	After     interface{} `json:"after,omitempty"`
+}
+
+type historyRecordResponseV2 struct {
+	Action    string      `json:"action"`
+	Actor     string      `json:"actor"`
+	RequestID string      `json:"requestId"`
+	At        string      `json:"at"`
+	Before    interface{} `json:"before,omitempty"`
+	After     interface{} `json:"after,omitempty"`
+}
+
+func optionalResponse(render func(*queries.GetExampleResult) interface{}, result *queries.GetExampleResult) interface{} {
+	if result == nil {
+		return nil
+	}
+
+	return render(result)
+}
+
+// deprecated flags every response of a deprecated version and points the
+// clients to the version replacing it.
+func deprecated(successor string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Response().Header()
+			header.Set(headerDeprecation, "true")
+			header.Set(headerLink, "<"+successor+">; rel=\"successor-version\"")
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"clean-arquitecture-template/internal/app/example/commands"
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const (
+	ConfigNode string = "apps.example.input-ports.jobs.purge"
+
+	defaultRetention time.Duration = 30 * 24 * time.Hour
+	defaultTimeout   time.Duration = time.Minute
+
+	purgeActor string = "jobs:purge"
+
+	ErrReadConfig err = "unable to read config"
+)
+
+type err string
+
+func (e err) Error() string {
+	return string(e)
+}
+
+// Config tells how long the deleted lines stay in the trash and how often the
+// purge runs. A zero Interval turns the purge off.
+type Config interface {
+	Retention() time.Duration
+	Interval() time.Duration
+	Timeout() time.Duration
+}
+
+type config struct {
+	PurgeRetention string `json:"retention"`
+	PurgeInterval  string `json:"interval"`
+	PurgeTimeout   string `json:"timeout"`
+
+	retention time.Duration
+	interval  time.Duration
+	timeout   time.Duration
+}
+
+func (c config) Retention() time.Duration {
+	return c.retention
+}
+
+func (c config) Interval() time.Duration {
+	return c.interval
+}
+
+func (c config) Timeout() time.Duration {
+	return c.timeout
+}
+
+type ConfigReader interface {
+	Find(node string) (io.Reader, error)
+}
+
+func ReadConfig(cnfr ConfigReader) (Config, error) {
+	reader, err := cnfr.Find(ConfigNode)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	cnf := config{
+		retention: defaultRetention,
+		timeout:   defaultTimeout,
+	}
+	if err = json.Unmarshal(data, &cnf); err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	for _, d := range []struct {
+		value  string
+		target *time.Duration
+	}{
+		{cnf.PurgeRetention, &cnf.retention},
+		{cnf.PurgeInterval, &cnf.interval},
+		{cnf.PurgeTimeout, &cnf.timeout},
+	} {
+		if d.value == "" {
+			continue
+		}
+
+		if *d.target, err = time.ParseDuration(d.value); err != nil {
+			return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+		}
+	}
+
+	return cnf, nil
+}
+
+// Purger removes for good, every Interval, the lines deleted more than
+// Retention ago.
+type Purger struct {
+	handler   commands.PurgeLinesRequestHandler
+	retention time.Duration
+	interval  time.Duration
+	timeout   time.Duration
+}
+
+func NewPurger(handler commands.PurgeLinesRequestHandler, cnf Config) Purger {
+	return Purger{
+		handler:   handler,
+		retention: cnf.Retention(),
+		interval:  cnf.Interval(),
+		timeout:   cnf.Timeout(),
+	}
+}
+
+// Run purges until ctx is done. It returns at once when the purge is off.
+func (p Purger) Run(ctx context.Context) {
+	if p.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.Purge(ctx)
+		}
+	}
+}
+
+// Purge runs the purge once and reports the number of lines removed. The
+// lines are purged in the name of the job, unless ctx tells someone else.
+func (p Purger) Purge(ctx context.Context) int64 {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	if example.ActorFrom(ctx) == "" {
+		ctx = example.WithActor(ctx, purgeActor)
+	}
+
+	purged, err := p.handler.Handle(ctx, commands.PurgeExamplesRequest{Retention: p.retention})
+
+	logger := log.WithField("purged", purged).WithField("retention", p.retention.String())
+	if err != nil {
+		logger.WithError(err).Error("purge failed")
+	} else if purged > 0 {
+		logger.Info("deleted lines purged")
+	}
+
+	return purged
+}
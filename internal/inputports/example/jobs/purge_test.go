@@ -0,0 +1,166 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"clean-arquitecture-template/internal/app/example/commands"
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+type configReaderMock struct {
+	f func(node string) (io.Reader, error)
+}
+
+func (cr configReaderMock) Find(node string) (io.Reader, error) {
+	return cr.f(node)
+}
+
+type mockCommandPurgeLinesHandler struct {
+	Handler func(context.Context, commands.PurgeExamplesRequest) (int64, error)
+}
+
+func (m mockCommandPurgeLinesHandler) Handle(ctx context.Context, command commands.PurgeExamplesRequest) (int64, error) {
+	return m.Handler(ctx, command)
+}
+
+func Test_ReadConfig(t *testing.T) {
+	testCases := []struct {
+		testName          string
+		configReader      func(node string) (io.Reader, error)
+		expectedRetention time.Duration
+		expectedInterval  time.Duration
+		expectedTimeout   time.Duration
+		expectedError     error
+	}{
+		{
+			testName: "error-read-config-case",
+			configReader: func(node string) (io.Reader, error) {
+				return nil, errors.New("some-error")
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "defaults-case",
+			configReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{}`), nil
+			},
+			expectedRetention: defaultRetention,
+			expectedTimeout:   defaultTimeout,
+		},
+		{
+			testName: "success-case",
+			configReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"retention": "48h", "interval": "1h", "timeout": "30s"}`), nil
+			},
+			expectedRetention: 48 * time.Hour,
+			expectedInterval:  time.Hour,
+			expectedTimeout:   30 * time.Second,
+		},
+		{
+			testName: "invalid-duration-case",
+			configReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"retention": "a month"}`), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "unmarshal-error-case",
+			configReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{`), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+
+		t.Run(c.testName, func(t *testing.T) {
+			cnf, err := ReadConfig(configReaderMock{f: c.configReader})
+
+			assert.ErrorIs(t, err, c.expectedError)
+			if cnf != nil {
+				assert.Equal(t, c.expectedRetention, cnf.Retention())
+				assert.Equal(t, c.expectedInterval, cnf.Interval())
+				assert.Equal(t, c.expectedTimeout, cnf.Timeout())
+			}
+		})
+	}
+}
+
+func Test_Purge(t *testing.T) {
+	testCases := []struct {
+		testName       string
+		handler        func(context.Context, commands.PurgeExamplesRequest) (int64, error)
+		expectedPurged int64
+	}{
+		{
+			testName: "purged-case",
+			handler: func(ctx context.Context, command commands.PurgeExamplesRequest) (int64, error) {
+				if command.Retention != time.Hour {
+					return 0, errors.New("unexpected retention")
+				}
+
+				if _, has := ctx.Deadline(); !has {
+					return 0, errors.New("missing timeout")
+				}
+
+				if example.ActorFrom(ctx) != purgeActor {
+					return 0, errors.New("unexpected actor")
+				}
+
+				return 3, nil
+			},
+			expectedPurged: 3,
+		},
+		{
+			testName: "error-case",
+			handler: func(ctx context.Context, command commands.PurgeExamplesRequest) (int64, error) {
+				return 0, commands.ErrSystem
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		c := c
+
+		t.Run(c.testName, func(t *testing.T) {
+			p := NewPurger(mockCommandPurgeLinesHandler{Handler: c.handler}, config{retention: time.Hour, timeout: time.Second})
+
+			assert.Equal(t, c.expectedPurged, p.Purge(context.Background()))
+		})
+	}
+}
+
+func Test_Run(t *testing.T) {
+	var runs int32
+
+	handler := mockCommandPurgeLinesHandler{Handler: func(ctx context.Context, command commands.PurgeExamplesRequest) (int64, error) {
+		atomic.AddInt32(&runs, 1)
+
+		return 0, nil
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		NewPurger(handler, config{interval: 5 * time.Millisecond, timeout: time.Second}).Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&runs) >= 2 }, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+
+	NewPurger(handler, config{}).Run(context.Background())
+}
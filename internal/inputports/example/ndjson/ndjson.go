@@ -0,0 +1,131 @@
+package ndjson
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"clean-arquitecture-template/internal/app/example/commands"
+	"clean-arquitecture-template/internal/app/example/queries"
+)
+
+const (
+	MIMEApplicationNDJSON string = "application/x-ndjson"
+
+	// FlushEvery is the number of records written between two flushes.
+	FlushEvery int64 = 500
+
+	maxRecordSize int = 1024 * 1024
+
+	ErrRecord ndjsonError = "malformed record"
+	ErrWrite  ndjsonError = "unable to write record"
+)
+
+type ndjsonError string
+
+func (ne ndjsonError) Error() string {
+	return string(ne)
+}
+
+// Record is one line of an export, and of an import.
+type Record struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      string    `json:"data"`
+}
+
+type Flusher interface {
+	Flush()
+}
+
+// Export writes every line of cursor to w, one record per line, and closes
+// the cursor. When w is a Flusher it is flushed every FlushEvery records, so
+// the records reach the reader while the export goes on.
+func Export(ctx context.Context, w io.Writer, cursor queries.ExampleCursor) (int64, error) {
+	defer cursor.Close(ctx)
+
+	flusher, _ := w.(Flusher)
+	enc := json.NewEncoder(w)
+
+	var written int64
+	for cursor.Next(ctx) {
+		result := cursor.Result()
+
+		record := Record{
+			ID:        result.ID,
+			CreatedAt: result.CreatedAt,
+			Data:      result.Data,
+		}
+
+		if err := enc.Encode(record); err != nil {
+			return written, fmt.Errorf("%s: %w", err.Error(), ErrWrite)
+		}
+
+		written++
+		if flusher != nil && written%FlushEvery == 0 {
+			flusher.Flush()
+		}
+	}
+
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	return written, cursor.Err()
+}
+
+// Source reads the records of an import one line at a time. Blank lines are
+// skipped, malformed ones are handed to the import as failed items.
+type Source struct {
+	scanner  *bufio.Scanner
+	position int
+}
+
+func NewSource(r io.Reader) *Source {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordSize)
+
+	return &Source{
+		scanner: scanner,
+	}
+}
+
+func (s *Source) Next() (commands.ImportItem, error) {
+	for s.scanner.Scan() {
+		s.position++
+
+		data := s.scanner.Bytes()
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+
+		item := commands.ImportItem{Position: s.position}
+
+		var record Record
+		if err := json.Unmarshal(data, &record); err != nil {
+			item.Err = fmt.Errorf("%s: %w", err.Error(), ErrRecord)
+			return item, nil
+		}
+
+		item.ID = record.ID
+		item.CreatedAt = record.CreatedAt
+		item.Data = record.Data
+
+		return item, nil
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			err = fmt.Errorf("line %d: %s: %w", s.position+1, err.Error(), ErrRecord)
+		}
+
+		return commands.ImportItem{}, err
+	}
+
+	return commands.ImportItem{}, io.EOF
+}
@@ -0,0 +1,126 @@
+package ndjson
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"clean-arquitecture-template/internal/app/example/commands"
+	"clean-arquitecture-template/internal/app/example/queries"
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+type flushRecorder struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (fr *flushRecorder) Flush() {
+	fr.flushes++
+}
+
+func Test_Export(t *testing.T) {
+	ctx := context.Background()
+	tstamp := time.Date(2018, time.September, 16, 10, 0, 0, 0, time.UTC)
+
+	lines := make([]example.Line, FlushEvery+1)
+	for i := range lines {
+		lines[i] = example.Line{ID: example.MockIdentifier("id"), Created: tstamp, Data: "line"}
+	}
+
+	testCases := []struct {
+		name            string
+		cursor          *example.MockCursor
+		expectedWritten int64
+		expectedFlushes int
+		expectedError   error
+	}{
+		{
+			name:            "empty-case",
+			cursor:          &example.MockCursor{},
+			expectedWritten: 0,
+			expectedFlushes: 1,
+		},
+		{
+			name:            "periodic-flush-case",
+			cursor:          &example.MockCursor{Lines: lines},
+			expectedWritten: FlushEvery + 1,
+			expectedFlushes: 2,
+		},
+		{
+			name:            "cursor-error-case",
+			cursor:          &example.MockCursor{Lines: lines[:1], Error: example.ErrTimeout},
+			expectedWritten: 1,
+			expectedFlushes: 1,
+			expectedError:   queries.ErrTimeout,
+		},
+	}
+
+	for _, c := range testCases {
+		cursor := c.cursor
+		expectedWritten := c.expectedWritten
+		expectedFlushes := c.expectedFlushes
+		expectedError := c.expectedError
+
+		t.Run(c.name, func(t *testing.T) {
+			repo := &example.MockRepository{}
+			repo.On("Scan", ctx).Return(cursor, nil)
+
+			results, err := queries.NewExportExamplesRequestHandler(repo).Handle(ctx, queries.ExportExamplesRequest{})
+			require.NoError(t, err)
+
+			var w flushRecorder
+			written, err := Export(ctx, &w, results)
+
+			assert.ErrorIs(t, err, expectedError)
+			assert.Equal(t, expectedWritten, written)
+			assert.Equal(t, expectedFlushes, w.flushes)
+			assert.Equal(t, int(expectedWritten), strings.Count(w.String(), "\n"))
+			assert.True(t, cursor.Closed())
+		})
+	}
+}
+
+func Test_Source(t *testing.T) {
+	tstamp := time.Date(2018, time.September, 16, 10, 0, 0, 0, time.UTC)
+
+	input := "{\"id\":\"one\",\"createdAt\":\"2018-09-16T10:00:00Z\",\"data\":\"first-line\"}\n" +
+		"\n" +
+		"  \n" +
+		"{not-json}\n" +
+		"{\"data\":\"second-line\"}"
+
+	source := NewSource(strings.NewReader(input))
+
+	item, err := source.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, commands.ImportItem{Position: 1, ID: "one", CreatedAt: tstamp, Data: "first-line"}, item)
+
+	item, err = source.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, item.Position)
+	assert.ErrorIs(t, item.Err, ErrRecord)
+
+	item, err = source.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, commands.ImportItem{Position: 5, Data: "second-line"}, item)
+
+	_, err = source.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func Test_SourceTooLong(t *testing.T) {
+	source := NewSource(strings.NewReader(strings.Repeat("x", maxRecordSize+1)))
+
+	_, err := source.Next()
+
+	assert.ErrorIs(t, err, ErrRecord)
+	assert.False(t, errors.Is(err, io.EOF))
+}
@@ -10,8 +10,8 @@ type Services struct {
 	Server http.Server
 }
 
-func NewServices(ctx context.Context, app app.Services, cnf http.Config) Services {
+func NewServices(ctx context.Context, app app.Services, cnf http.Config, checkers ...http.HealthChecker) Services {
 	return Services{
-		Server: http.NewServer(ctx, app, cnf),
+		Server: http.NewServer(ctx, app, cnf, checkers...),
 	}
 }
@@ -0,0 +1,131 @@
+package problem
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+/**************************************************
+* This file constains the error contract shared   *
+* by every input port, based on RFC 7807.         *
+***************************************************/
+
+const (
+	MIMEApplicationProblemJSON string = "application/problem+json"
+
+	typeBase string = "/problems/"
+
+	CodeUnknown string = "unknown_error"
+)
+
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+}
+
+// Definition is what the registry knows about an error. The detail of the
+// problem is only filled with the error message for client errors, server
+// errors keep their internals out of the response.
+type Definition struct {
+	Status int
+	Title  string
+	Code   string
+}
+
+func (d Definition) problem(err error, instance string) Problem {
+	p := Problem{
+		Type:     typeBase + d.Code,
+		Title:    d.Title,
+		Status:   d.Status,
+		Instance: instance,
+		Code:     d.Code,
+	}
+
+	if err != nil && d.Status < http.StatusInternalServerError {
+		p.Detail = err.Error()
+	}
+
+	return p
+}
+
+type entry struct {
+	err        error
+	definition Definition
+}
+
+type Registry struct {
+	mu      sync.RWMutex
+	entries []entry
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register maps err, and every error wrapping it, to d. Errors registered
+// first take precedence when an error wraps more than one of them.
+func (r *Registry) Register(err error, d Definition) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, entry{err: err, definition: d})
+
+	return r
+}
+
+func (r *Registry) Lookup(err error) (Definition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, e := range r.entries {
+		if errors.Is(err, e.err) {
+			return e.definition, true
+		}
+	}
+
+	return Definition{}, false
+}
+
+// New builds the problem for err. Errors missing from the registry are
+// reported as unknown server errors.
+func (r *Registry) New(err error, instance string) Problem {
+	d, found := r.Lookup(err)
+	if !found {
+		d = Definition{
+			Status: http.StatusInternalServerError,
+			Title:  http.StatusText(http.StatusInternalServerError),
+			Code:   CodeUnknown,
+		}
+	}
+
+	return d.problem(err, instance)
+}
+
+// FromStatus builds the problem for errors raised by the transport itself,
+// such as unknown routes, that only carry a status.
+func FromStatus(status int, detail string, instance string) Problem {
+	title := http.StatusText(status)
+	if title == "" {
+		title = http.StatusText(http.StatusInternalServerError)
+		status = http.StatusInternalServerError
+	}
+
+	d := Definition{
+		Status: status,
+		Title:  title,
+		Code:   strings.ReplaceAll(strings.ToLower(title), " ", "_"),
+	}
+
+	p := d.problem(nil, instance)
+	if status < http.StatusInternalServerError && detail != title {
+		p.Detail = detail
+	}
+
+	return p
+}
@@ -0,0 +1,188 @@
+package problem
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"clean-arquitecture-template/internal/app/example/commands"
+	"clean-arquitecture-template/internal/app/example/queries"
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+func Test_RegistryNew(t *testing.T) {
+	testCases := []struct {
+		testName        string
+		err             error
+		expectedProblem Problem
+	}{
+		{
+			testName: "unknown-error-case",
+			err:      errors.New("some-error"),
+			expectedProblem: Problem{
+				Type:     "/problems/unknown_error",
+				Title:    "Internal Server Error",
+				Status:   http.StatusInternalServerError,
+				Instance: "/example/write",
+				Code:     CodeUnknown,
+			},
+		},
+		{
+			testName: "wrapped-client-error-case",
+			err:      fmt.Errorf("%s: %w", "unexpected EOF", ErrInputParam),
+			expectedProblem: Problem{
+				Type:     "/problems/invalid_input",
+				Title:    "Invalid input",
+				Status:   http.StatusBadRequest,
+				Detail:   "unexpected EOF: input param error",
+				Instance: "/example/write",
+				Code:     "invalid_input",
+			},
+		},
+		{
+			testName: "server-error-hides-detail-case",
+			err:      fmt.Errorf("%s: %w", "connection refused", commands.ErrSystem),
+			expectedProblem: Problem{
+				Type:     "/problems/system_error",
+				Title:    "System error",
+				Status:   http.StatusInternalServerError,
+				Instance: "/example/write",
+				Code:     "system_error",
+			},
+		},
+		{
+			testName: "service-timeout-case",
+			err:      fmt.Errorf("%s: %w", "context deadline exceeded", queries.ErrTimeout),
+			expectedProblem: Problem{
+				Type:     "/problems/timeout",
+				Title:    "Operation timed out",
+				Status:   http.StatusGatewayTimeout,
+				Instance: "/example/write",
+				Code:     "timeout",
+			},
+		},
+		{
+			testName: "service-unavailable-case",
+			err:      fmt.Errorf("%s: %w", "circuit breaker open", commands.ErrUnavailable),
+			expectedProblem: Problem{
+				Type:     "/problems/unavailable",
+				Title:    "Service unavailable",
+				Status:   http.StatusServiceUnavailable,
+				Instance: "/example/write",
+				Code:     "unavailable",
+			},
+		},
+		{
+			testName: "duplicate-case",
+			err:      fmt.Errorf("%s: %w", "line \"one\"", commands.ErrDuplicate),
+			expectedProblem: Problem{
+				Type:     "/problems/duplicate",
+				Title:    "Line already exists",
+				Status:   http.StatusConflict,
+				Detail:   "line \"one\": line already exists",
+				Instance: "/example/write",
+				Code:     "duplicate",
+			},
+		},
+		{
+			testName: "domain-timeout-case",
+			err:      example.ErrTimeout,
+			expectedProblem: Problem{
+				Type:     "/problems/timeout",
+				Title:    "Operation timed out",
+				Status:   http.StatusGatewayTimeout,
+				Instance: "/example/write",
+				Code:     "timeout",
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		name := c.testName
+		err := c.err
+		expectedProblem := c.expectedProblem
+
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, expectedProblem, Default.New(err, "/example/write"))
+		})
+	}
+}
+
+func Test_RegistryPrecedence(t *testing.T) {
+	const (
+		errOuter Error = "outer"
+		errInner Error = "inner"
+	)
+
+	r := NewRegistry().
+		Register(errOuter, Definition{Status: http.StatusConflict, Title: "Outer", Code: "outer"}).
+		Register(errInner, Definition{Status: http.StatusBadRequest, Title: "Inner", Code: "inner"})
+
+	d, found := r.Lookup(fmt.Errorf("%w", errInner))
+	assert.True(t, found)
+	assert.Equal(t, "inner", d.Code)
+
+	_, found = r.Lookup(errors.New("some-error"))
+	assert.False(t, found)
+}
+
+func Test_FromStatus(t *testing.T) {
+	testCases := []struct {
+		testName        string
+		status          int
+		detail          string
+		expectedProblem Problem
+	}{
+		{
+			testName: "client-error-case",
+			status:   http.StatusUnsupportedMediaType,
+			detail:   "unsupported content type",
+			expectedProblem: Problem{
+				Type:     "/problems/unsupported_media_type",
+				Title:    "Unsupported Media Type",
+				Status:   http.StatusUnsupportedMediaType,
+				Detail:   "unsupported content type",
+				Instance: "/example/write",
+				Code:     "unsupported_media_type",
+			},
+		},
+		{
+			testName: "server-error-case",
+			status:   http.StatusServiceUnavailable,
+			detail:   "connection refused",
+			expectedProblem: Problem{
+				Type:     "/problems/service_unavailable",
+				Title:    "Service Unavailable",
+				Status:   http.StatusServiceUnavailable,
+				Instance: "/example/write",
+				Code:     "service_unavailable",
+			},
+		},
+		{
+			testName: "unknown-status-case",
+			status:   999,
+			detail:   "some-error",
+			expectedProblem: Problem{
+				Type:     "/problems/internal_server_error",
+				Title:    "Internal Server Error",
+				Status:   http.StatusInternalServerError,
+				Instance: "/example/write",
+				Code:     "internal_server_error",
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		name := c.testName
+		status := c.status
+		detail := c.detail
+		expectedProblem := c.expectedProblem
+
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, expectedProblem, FromStatus(status, detail, "/example/write"))
+		})
+	}
+}
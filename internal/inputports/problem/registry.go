@@ -0,0 +1,146 @@
+package problem
+
+import (
+	"net/http"
+
+	"clean-arquitecture-template/internal/app/example/commands"
+	"clean-arquitecture-template/internal/app/example/queries"
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const (
+	ErrInputParam           Error = "input param error"
+	ErrNotAcceptable        Error = "not acceptable"
+	ErrUnsupportedMediaType Error = "unsupported media type"
+	ErrForbidden            Error = "forbidden"
+)
+
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+// Default holds the mapping of the domain and service errors every input
+// port reports.
+var Default = NewRegistry().
+	Register(ErrInputParam, Definition{
+		Status: http.StatusBadRequest,
+		Title:  "Invalid input",
+		Code:   "invalid_input",
+	}).
+	Register(ErrNotAcceptable, Definition{
+		Status: http.StatusNotAcceptable,
+		Title:  "Not acceptable",
+		Code:   "not_acceptable",
+	}).
+	Register(ErrUnsupportedMediaType, Definition{
+		Status: http.StatusUnsupportedMediaType,
+		Title:  "Unsupported media type",
+		Code:   "unsupported_media_type",
+	}).
+	Register(ErrForbidden, Definition{
+		Status: http.StatusForbidden,
+		Title:  "Forbidden",
+		Code:   "forbidden",
+	}).
+	Register(queries.ErrInvalidID, Definition{
+		Status: http.StatusBadRequest,
+		Title:  "Invalid identifier",
+		Code:   "invalid_id",
+	}).
+	Register(commands.ErrInvalidID, Definition{
+		Status: http.StatusBadRequest,
+		Title:  "Invalid identifier",
+		Code:   "invalid_id",
+	}).
+	Register(commands.ErrBatchSize, Definition{
+		Status: http.StatusBadRequest,
+		Title:  "Batch too large",
+		Code:   "batch_too_large",
+	}).
+	Register(queries.ErrBatchSize, Definition{
+		Status: http.StatusBadRequest,
+		Title:  "Batch too large",
+		Code:   "batch_too_large",
+	}).
+	Register(commands.ErrInvalidLine, Definition{
+		Status: http.StatusBadRequest,
+		Title:  "Invalid line",
+		Code:   "invalid_line",
+	}).
+	Register(commands.ErrImportSource, Definition{
+		Status: http.StatusBadRequest,
+		Title:  "Unreadable import",
+		Code:   "unreadable_import",
+	}).
+	Register(commands.ErrRetention, Definition{
+		Status: http.StatusBadRequest,
+		Title:  "Invalid retention",
+		Code:   "invalid_retention",
+	}).
+	Register(queries.ErrSearchQuery, Definition{
+		Status: http.StatusBadRequest,
+		Title:  "Invalid search query",
+		Code:   "invalid_search_query",
+	}).
+	Register(queries.ErrListQuery, Definition{
+		Status: http.StatusBadRequest,
+		Title:  "Invalid list query",
+		Code:   "invalid_list_query",
+	}).
+	Register(commands.ErrNotFound, Definition{
+		Status: http.StatusNotFound,
+		Title:  "Line not found",
+		Code:   "not_found",
+	}).
+	Register(queries.ErrNotFound, Definition{
+		Status: http.StatusNotFound,
+		Title:  "Line not found",
+		Code:   "not_found",
+	}).
+	Register(commands.ErrTimeout, Definition{
+		Status: http.StatusGatewayTimeout,
+		Title:  "Operation timed out",
+		Code:   "timeout",
+	}).
+	Register(queries.ErrTimeout, Definition{
+		Status: http.StatusGatewayTimeout,
+		Title:  "Operation timed out",
+		Code:   "timeout",
+	}).
+	Register(example.ErrTimeout, Definition{
+		Status: http.StatusGatewayTimeout,
+		Title:  "Operation timed out",
+		Code:   "timeout",
+	}).
+	Register(commands.ErrUnavailable, Definition{
+		Status: http.StatusServiceUnavailable,
+		Title:  "Service unavailable",
+		Code:   "unavailable",
+	}).
+	Register(queries.ErrUnavailable, Definition{
+		Status: http.StatusServiceUnavailable,
+		Title:  "Service unavailable",
+		Code:   "unavailable",
+	}).
+	Register(example.ErrUnavailable, Definition{
+		Status: http.StatusServiceUnavailable,
+		Title:  "Service unavailable",
+		Code:   "unavailable",
+	}).
+	Register(commands.ErrDuplicate, Definition{
+		Status: http.StatusConflict,
+		Title:  "Line already exists",
+		Code:   "duplicate",
+	}).
+	Register(commands.ErrSystem, Definition{
+		Status: http.StatusInternalServerError,
+		Title:  "System error",
+		Code:   "system_error",
+	}).
+	Register(queries.ErrSystem, Definition{
+		Status: http.StatusInternalServerError,
+		Title:  "System error",
+		Code:   "system_error",
+	})
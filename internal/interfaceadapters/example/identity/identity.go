@@ -0,0 +1,172 @@
+// Package identity provides time-sortable identifier strategies any storage
+// adapter can store: UUIDv7, ULID, KSUID and Snowflake. Every strategy parses
+// only the canonical text form of its identifiers, case aside, and gives the
+// canonical form back from String.
+package identity
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const (
+	ErrReadConfig identityError = "unable to read identity config"
+	ErrIdentifier identityError = "invalid identifier"
+
+	ConfigNode string = "apps.example.interface-adapters.identity"
+
+	// NativeStrategy keeps the identifiers of the storage adapter.
+	NativeStrategy    string = "native"
+	UUIDv7Strategy    string = "uuidv7"
+	ULIDStrategy      string = "ulid"
+	KSUIDStrategy     string = "ksuid"
+	SnowflakeStrategy string = "snowflake"
+)
+
+// Strategies lists the strategies of the package, the native one aside.
+var Strategies = []string{UUIDv7Strategy, ULIDStrategy, KSUIDStrategy, SnowflakeStrategy}
+
+type identityError string
+
+func (ie identityError) Error() string {
+	return string(ie)
+}
+
+type Config interface {
+	Strategy() string
+	Node() int64
+}
+
+type config struct {
+	Kind   string `json:"strategy"`
+	NodeID int64  `json:"node"`
+}
+
+// Strategy is the native one when none is set.
+func (c config) Strategy() string {
+	if c.Kind == "" {
+		return NativeStrategy
+	}
+
+	return c.Kind
+}
+
+// Node identifies the process among those making Snowflake identifiers, no
+// two of them running at once may share it.
+func (c config) Node() int64 {
+	return c.NodeID
+}
+
+type ConfigReader interface {
+	Find(node string) (io.Reader, error)
+}
+
+func ReadConfig(cfnReader ConfigReader) (Config, error) {
+	reader, err := cfnReader.Find(ConfigNode)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	d, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	cnf := config{}
+	if err = json.Unmarshal(d, &cnf); err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	if !known(cnf.Strategy()) {
+		return nil, fmt.Errorf("strategy %q: %w", cnf.Strategy(), ErrReadConfig)
+	}
+
+	if cnf.NodeID < 0 || cnf.NodeID > maxSnowflakeNode {
+		return nil, fmt.Errorf("node %d out of [0, %d]: %w", cnf.NodeID, maxSnowflakeNode, ErrReadConfig)
+	}
+
+	return cnf, nil
+}
+
+func known(strategy string) bool {
+	if strategy == NativeStrategy {
+		return true
+	}
+
+	for _, s := range Strategies {
+		if s == strategy {
+			return true
+		}
+	}
+
+	return false
+}
+
+// New builds the provider of the strategy chosen in cnf, native being the
+// provider of the storage adapter.
+func New(cnf Config, native example.IdentityProvider) (example.IdentityProvider, error) {
+	if cnf.Strategy() == NativeStrategy {
+		return native, nil
+	}
+
+	return NewProvider(cnf.Strategy(), cnf.Node())
+}
+
+// NewProvider builds the provider of one of Strategies, node being used by
+// the Snowflake one only.
+func NewProvider(strategy string, node int64) (example.IdentityProvider, error) {
+	switch strategy {
+	case UUIDv7Strategy:
+		return NewUUIDv7Provider(), nil
+	case ULIDStrategy:
+		return NewULIDProvider(), nil
+	case KSUIDStrategy:
+		return NewKSUIDProvider(), nil
+	case SnowflakeStrategy:
+		return NewSnowflakeProvider(node)
+	}
+
+	return nil, fmt.Errorf("strategy %q: %w", strategy, ErrReadConfig)
+}
+
+// source is the clock and the entropy of a provider, which the tests replace.
+type source struct {
+	now     func() time.Time
+	entropy io.Reader
+}
+
+func newSource() source {
+	return source{now: time.Now, entropy: rand.Reader}
+}
+
+// random fills b, panicking when the entropy source fails as uuid.New does,
+// since no identifier can be made then.
+func (s source) random(b []byte) {
+	if _, err := io.ReadFull(s.entropy, b); err != nil {
+		panic(fmt.Sprintf("identity: reading entropy: %s", err.Error()))
+	}
+}
+
+// increment adds one to the big-endian number in b, reporting whether it
+// wrapped around to zero.
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// invalid wraps ErrIdentifier with the reason text is not an identifier of
+// the strategy.
+func invalid(strategy string, text []byte, reason string) error {
+	return fmt.Errorf("%s %q %s: %w", strategy, text, reason, ErrIdentifier)
+}
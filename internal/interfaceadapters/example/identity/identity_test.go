@@ -0,0 +1,381 @@
+package identity
+
+import (
+	"crypto/rand"
+	"encoding"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+type configReaderMock struct {
+	f func(node string) (io.Reader, error)
+}
+
+func (crm configReaderMock) Find(node string) (io.Reader, error) {
+	return crm.f(node)
+}
+
+// repeatReader is an entropy source giving the same byte again and again.
+type repeatReader byte
+
+func (rr repeatReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(rr)
+	}
+
+	return len(p), nil
+}
+
+// fixedSource is a clock stopped at now with the entropy of a repeatReader.
+func fixedSource(now time.Time, b byte) source {
+	return source{
+		now:     func() time.Time { return now },
+		entropy: repeatReader(b),
+	}
+}
+
+// textIdentifier is what the identifiers of every strategy implement.
+type textIdentifier interface {
+	example.Identifier
+	encoding.TextMarshaler
+	Time() time.Time
+}
+
+func Test_ReadConfig(t *testing.T) {
+	testCases := []struct {
+		testName          string
+		buildConfigReader func(node string) (io.Reader, error)
+		expectedStrategy  string
+		expectedNode      int64
+		expectedError     error
+	}{
+		{
+			testName: "config-find-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return nil, errors.New("not found")
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "config-unmarshal-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader("{"), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "defaults-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{}`), nil
+			},
+			expectedStrategy: NativeStrategy,
+		},
+		{
+			testName: "snowflake-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"strategy": "snowflake", "node": 1023}`), nil
+			},
+			expectedStrategy: SnowflakeStrategy,
+			expectedNode:     1023,
+		},
+		{
+			testName: "unknown-strategy-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"strategy": "uuidv4"}`), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "node-too-big-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"strategy": "snowflake", "node": 1024}`), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "negative-node-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"strategy": "snowflake", "node": -1}`), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+	}
+
+	for _, c := range testCases {
+		name := c.testName
+		reader := configReaderMock{c.buildConfigReader}
+		expectedStrategy := c.expectedStrategy
+		expectedNode := c.expectedNode
+		expectedError := c.expectedError
+
+		t.Run(name, func(t *testing.T) {
+			cnf, err := ReadConfig(reader)
+			assert.ErrorIs(t, err, expectedError)
+
+			if expectedError != nil {
+				return
+			}
+
+			assert.Equal(t, expectedStrategy, cnf.Strategy())
+			assert.Equal(t, expectedNode, cnf.Node())
+		})
+	}
+}
+
+func Test_New(t *testing.T) {
+	native := example.MockIdentityProvider{}
+
+	testCases := []struct {
+		testName      string
+		cnf           config
+		expectedType  interface{}
+		expectedError error
+	}{
+		{
+			testName:     "native-case",
+			cnf:          config{},
+			expectedType: &example.MockIdentityProvider{},
+		},
+		{
+			testName:     "uuidv7-case",
+			cnf:          config{Kind: UUIDv7Strategy},
+			expectedType: &UUIDv7Provider{},
+		},
+		{
+			testName:     "ulid-case",
+			cnf:          config{Kind: ULIDStrategy},
+			expectedType: &ULIDProvider{},
+		},
+		{
+			testName:     "ksuid-case",
+			cnf:          config{Kind: KSUIDStrategy},
+			expectedType: &KSUIDProvider{},
+		},
+		{
+			testName:     "snowflake-case",
+			cnf:          config{Kind: SnowflakeStrategy, NodeID: 7},
+			expectedType: &SnowflakeProvider{},
+		},
+		{
+			testName:      "snowflake-node-error-case",
+			cnf:           config{Kind: SnowflakeStrategy, NodeID: 4096},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName:      "unknown-strategy-error-case",
+			cnf:           config{Kind: "objectid"},
+			expectedError: ErrReadConfig,
+		},
+	}
+
+	for _, c := range testCases {
+		name := c.testName
+		cnf := c.cnf
+		expectedType := c.expectedType
+		expectedError := c.expectedError
+
+		t.Run(name, func(t *testing.T) {
+			ids, err := New(cnf, &native)
+			assert.ErrorIs(t, err, expectedError)
+
+			if expectedError != nil {
+				return
+			}
+
+			assert.IsType(t, expectedType, ids)
+		})
+	}
+}
+
+// Test_Providers checks what every strategy promises: identifiers sorted in
+// the order they were made, within a millisecond too, text that parses back
+// to the same identifier and the creation time.
+func Test_Providers(t *testing.T) {
+	now := time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		strategy   string
+		provider   func(src source) example.IdentityProvider
+		resolution time.Duration
+		less       func(a, b example.Identifier) bool
+	}{
+		{
+			strategy: UUIDv7Strategy,
+			provider: func(src source) example.IdentityProvider {
+				p := NewUUIDv7Provider()
+				p.source = src
+				return p
+			},
+			resolution: time.Millisecond,
+			less:       lessText,
+		},
+		{
+			strategy: ULIDStrategy,
+			provider: func(src source) example.IdentityProvider {
+				p := NewULIDProvider()
+				p.source = src
+				return p
+			},
+			resolution: time.Millisecond,
+			less:       lessText,
+		},
+		{
+			strategy: KSUIDStrategy,
+			provider: func(src source) example.IdentityProvider {
+				p := NewKSUIDProvider()
+				p.source = src
+				return p
+			},
+			resolution: time.Second,
+			less:       lessText,
+		},
+		{
+			strategy: SnowflakeStrategy,
+			provider: func(src source) example.IdentityProvider {
+				p, _ := NewSnowflakeProvider(5)
+				p.source = src
+				return p
+			},
+			resolution: time.Millisecond,
+			less: func(a, b example.Identifier) bool {
+				return a.(Snowflake) < b.(Snowflake)
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		strategy := c.strategy
+		provider := c.provider
+		resolution := c.resolution
+		less := c.less
+
+		t.Run(strategy, func(t *testing.T) {
+			clock := now
+			ids := provider(source{
+				now:     func() time.Time { return clock },
+				entropy: rand.Reader,
+			})
+
+			first := ids.NewID()
+			assert.WithinDuration(t, now, first.(textIdentifier).Time(), 0)
+
+			previous := first
+			for i := 0; i < 10000; i++ {
+				id := ids.NewID()
+				require.True(t, less(previous, id), "%s not after %s", id, previous)
+
+				previous = id
+			}
+
+			clock = now.Add(-time.Hour)
+			backwards := ids.NewID()
+			assert.True(t, less(previous, backwards), "a clock going back keeps the order")
+
+			clock = now.Add(time.Hour)
+			later := ids.NewID()
+			assert.True(t, less(backwards, later))
+			assert.WithinDuration(t, now.Add(time.Hour).Truncate(resolution), later.(textIdentifier).Time(), 0)
+
+			for _, id := range []example.Identifier{first, backwards, later} {
+				parsed, err := ids.ParseID(id.String())
+				require.NoError(t, err)
+				assert.Equal(t, id, parsed, "identifiers are comparable")
+
+				text, err := id.(textIdentifier).MarshalText()
+				require.NoError(t, err)
+				assert.Equal(t, id.String(), string(text))
+			}
+
+			parsed, err := ids.ParseID("")
+			assert.ErrorIs(t, err, ErrIdentifier)
+			assert.Nil(t, parsed)
+		})
+	}
+}
+
+func lessText(a, b example.Identifier) bool {
+	return a.String() < b.String()
+}
+
+func Test_ProvidersConcurrent(t *testing.T) {
+	for _, strategy := range Strategies {
+		strategy := strategy
+
+		t.Run(strategy, func(t *testing.T) {
+			ids, err := NewProvider(strategy, 1)
+			require.NoError(t, err)
+
+			const workers, perWorker = 8, 1000
+
+			made := make(chan example.Identifier, workers*perWorker)
+			done := make(chan struct{})
+			for w := 0; w < workers; w++ {
+				go func() {
+					for i := 0; i < perWorker; i++ {
+						made <- ids.NewID()
+					}
+					done <- struct{}{}
+				}()
+			}
+
+			for w := 0; w < workers; w++ {
+				<-done
+			}
+			close(made)
+
+			seen := make(map[example.Identifier]bool, workers*perWorker)
+			for id := range made {
+				assert.False(t, seen[id], "%s made twice", id)
+				seen[id] = true
+			}
+
+			assert.Len(t, seen, workers*perWorker)
+		})
+	}
+}
+
+func Benchmark_NewID(b *testing.B) {
+	for _, strategy := range Strategies {
+		ids, err := NewProvider(strategy, 1)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(strategy, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				_ = ids.NewID()
+			}
+		})
+	}
+}
+
+func Benchmark_ParseID(b *testing.B) {
+	for _, strategy := range Strategies {
+		ids, err := NewProvider(strategy, 1)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		key := ids.NewID().String()
+
+		b.Run(strategy, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := ids.ParseID(key); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
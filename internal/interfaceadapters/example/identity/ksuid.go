@@ -0,0 +1,130 @@
+package identity
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const (
+	ksuidTextLen int = 27
+
+	// ksuidEpoch is the Unix second KSUID timestamps count from.
+	ksuidEpoch int64 = 1400000000
+
+	base62Alphabet string = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+var base62Values = digitValues(base62Alphabet, false)
+
+// KSUID is 32 bits of seconds since ksuidEpoch and 128 random bits, written
+// in base62.
+type KSUID [20]byte
+
+func (id KSUID) String() string {
+	var text [ksuidTextLen]byte
+	id.encode(text[:])
+
+	return string(text[:])
+}
+
+// Time is the second the identifier was made in.
+func (id KSUID) Time() time.Time {
+	return time.Unix(ksuidEpoch+int64(binary.BigEndian.Uint32(id[:4])), 0)
+}
+
+func (id KSUID) MarshalText() ([]byte, error) {
+	text := make([]byte, ksuidTextLen)
+	id.encode(text)
+
+	return text, nil
+}
+
+// UnmarshalText takes 27 base62 digits, which are case sensitive, of a value
+// that fits in 160 bits.
+func (id *KSUID) UnmarshalText(text []byte) error {
+	if len(text) != ksuidTextLen {
+		return invalid(KSUIDStrategy, text, "is not 27 characters long")
+	}
+
+	var parsed KSUID
+	for _, c := range text {
+		v := base62Values[c]
+		if v == noDigit {
+			return invalid(KSUIDStrategy, text, "is not base62 digits")
+		}
+
+		carry := uint(v)
+		for i := len(parsed) - 1; i >= 0; i-- {
+			carry += uint(parsed[i]) * 62
+			parsed[i] = byte(carry)
+			carry >>= 8
+		}
+
+		if carry != 0 {
+			return invalid(KSUIDStrategy, text, "overflows 160 bits")
+		}
+	}
+
+	*id = parsed
+
+	return nil
+}
+
+// encode divides the 160 bits by 62 once per digit, from the last one.
+func (id KSUID) encode(text []byte) {
+	number := id
+	for i := ksuidTextLen - 1; i >= 0; i-- {
+		var remainder uint
+		for j := range number {
+			remainder = remainder<<8 | uint(number[j])
+			number[j] = byte(remainder / 62)
+			remainder %= 62
+		}
+
+		text[i] = base62Alphabet[remainder]
+	}
+}
+
+// KSUIDProvider makes identifiers that sort in the order they were made: in
+// the same second the random part goes up by one, and when it runs out, or
+// the clock goes back, the provider carries on from its last second.
+type KSUIDProvider struct {
+	mu     sync.Mutex
+	source source
+	last   KSUID
+}
+
+func NewKSUIDProvider() *KSUIDProvider {
+	return &KSUIDProvider{source: newSource()}
+}
+
+func (p *KSUIDProvider) NewID() example.Identifier {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	last := binary.BigEndian.Uint32(p.last[:4])
+	seconds := p.source.now().Unix() - ksuidEpoch
+
+	switch {
+	case seconds > int64(last):
+		binary.BigEndian.PutUint32(p.last[:4], uint32(seconds))
+		p.source.random(p.last[4:])
+	case increment(p.last[4:]):
+		binary.BigEndian.PutUint32(p.last[:4], last+1)
+		p.source.random(p.last[4:])
+	}
+
+	return p.last
+}
+
+func (p *KSUIDProvider) ParseID(key string) (example.Identifier, error) {
+	var id KSUID
+	if err := id.UnmarshalText([]byte(key)); err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
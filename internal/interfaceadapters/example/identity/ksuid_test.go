@@ -0,0 +1,98 @@
+package identity
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_KSUIDText(t *testing.T) {
+	testCases := []struct {
+		name          string
+		text          string
+		expectedRaw   string
+		expectedError error
+	}{
+		{
+			name:        "example",
+			text:        "0ujtsYcgvSTl8PAuAdqWYSMnLOv",
+			expectedRaw: "0669f7efb5a1cd34b5f99d1154fb6853345c9735",
+		},
+		{
+			name:        "smallest",
+			text:        "000000000000000000000000000",
+			expectedRaw: "0000000000000000000000000000000000000000",
+		},
+		{
+			name:        "largest",
+			text:        "aWgEPTl1tmebfsQzFP4bxwgy80V",
+			expectedRaw: "ffffffffffffffffffffffffffffffffffffffff",
+		},
+		{
+			name:          "overflow",
+			text:          "aWgEPTl1tmebfsQzFP4bxwgy80W",
+			expectedError: ErrIdentifier,
+		},
+		{
+			name:          "too-long",
+			text:          "0ujtsYcgvSTl8PAuAdqWYSMnLOv0",
+			expectedError: ErrIdentifier,
+		},
+		{
+			name:          "not-base62",
+			text:          "0ujtsYcgvSTl8PAuAdqWYSMnLO-",
+			expectedError: ErrIdentifier,
+		},
+	}
+
+	for _, c := range testCases {
+		testname := c.name
+		text := c.text
+		expectedRaw := c.expectedRaw
+		expectedError := c.expectedError
+
+		t.Run(testname, func(t *testing.T) {
+			var id KSUID
+			err := id.UnmarshalText([]byte(text))
+
+			if expectedError != nil {
+				assert.ErrorIs(t, err, expectedError)
+				assert.Equal(t, KSUID{}, id)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, expectedRaw, hex.EncodeToString(id[:]))
+			assert.Equal(t, text, id.String())
+		})
+	}
+}
+
+func Test_KSUIDTime(t *testing.T) {
+	var id KSUID
+	require.NoError(t, id.UnmarshalText([]byte("0ujtsYcgvSTl8PAuAdqWYSMnLOv")))
+
+	assert.Equal(t, time.Date(2017, time.October, 10, 4, 0, 47, 0, time.UTC), id.Time().UTC())
+
+	var swapped KSUID
+	require.NoError(t, swapped.UnmarshalText([]byte("0UJTSyCGVstL8pAUaDQwysmNloV")))
+	assert.NotEqual(t, id, swapped, "base62 digits are case sensitive")
+}
+
+func Test_KSUIDRandomOverflow(t *testing.T) {
+	now := time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	p := NewKSUIDProvider()
+	p.source = fixedSource(now.Add(999*time.Millisecond), 0xff)
+
+	first := p.NewID().(KSUID)
+	assert.Equal(t, now, first.Time().UTC(), "the time is truncated to the second")
+
+	carried := p.NewID().(KSUID)
+	assert.Equal(t, now.Add(time.Second), carried.Time().UTC(), "the random part ran out")
+	assert.Less(t, first.String(), carried.String())
+}
@@ -0,0 +1,121 @@
+package identity
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+
+	maxSnowflakeNode     int64 = 1<<snowflakeNodeBits - 1
+	maxSnowflakeSequence int64 = 1<<snowflakeSequenceBits - 1
+
+	// snowflakeEpoch is the Unix millisecond Snowflake timestamps count
+	// from, 2020-01-01T00:00:00Z.
+	snowflakeEpoch int64 = 1577836800000
+
+	// snowflakeTextLen is the length of the largest Snowflake, 2^63 - 1.
+	snowflakeTextLen int = 19
+)
+
+// Snowflake is 41 bits of milliseconds since snowflakeEpoch, the 10 bits of
+// the node that made it and a 12 bits sequence, written in decimal.
+type Snowflake int64
+
+func (id Snowflake) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+// Time is the millisecond the identifier was made in.
+func (id Snowflake) Time() time.Time {
+	return time.UnixMilli(snowflakeEpoch + int64(id)>>(snowflakeNodeBits+snowflakeSequenceBits))
+}
+
+// Node is the node the identifier was made by.
+func (id Snowflake) Node() int64 {
+	return int64(id) >> snowflakeSequenceBits & maxSnowflakeNode
+}
+
+func (id Snowflake) MarshalText() ([]byte, error) {
+	return strconv.AppendInt(make([]byte, 0, snowflakeTextLen), int64(id), 10), nil
+}
+
+// UnmarshalText takes decimal digits only, without sign or leading zeros, of
+// a value that fits in 63 bits.
+func (id *Snowflake) UnmarshalText(text []byte) error {
+	if len(text) == 0 || len(text) > snowflakeTextLen {
+		return invalid(SnowflakeStrategy, text, "is not 1 to 19 digits long")
+	}
+
+	if text[0] == '0' && len(text) > 1 {
+		return invalid(SnowflakeStrategy, text, "has leading zeros")
+	}
+
+	for _, c := range text {
+		if c < '0' || c > '9' {
+			return invalid(SnowflakeStrategy, text, "is not decimal digits")
+		}
+	}
+
+	parsed, err := strconv.ParseInt(string(text), 10, 64)
+	if err != nil {
+		return invalid(SnowflakeStrategy, text, "overflows 63 bits")
+	}
+
+	*id = Snowflake(parsed)
+
+	return nil
+}
+
+// SnowflakeProvider makes the identifiers of one node, which sort in the
+// order they were made: in the same millisecond the sequence goes up, and
+// when it runs out, or the clock goes back, the provider carries on from its
+// last millisecond.
+type SnowflakeProvider struct {
+	mu       sync.Mutex
+	source   source
+	node     int64
+	last     int64
+	sequence int64
+}
+
+func NewSnowflakeProvider(node int64) (*SnowflakeProvider, error) {
+	if node < 0 || node > maxSnowflakeNode {
+		return nil, fmt.Errorf("snowflake node %d out of [0, %d]: %w", node, maxSnowflakeNode, ErrReadConfig)
+	}
+
+	return &SnowflakeProvider{source: newSource(), node: node, last: -1}, nil
+}
+
+func (p *SnowflakeProvider) NewID() example.Identifier {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ms := p.source.now().UnixMilli() - snowflakeEpoch
+	if ms < 0 {
+		ms = 0
+	}
+
+	if ms > p.last {
+		p.last, p.sequence = ms, 0
+	} else if p.sequence++; p.sequence > maxSnowflakeSequence {
+		p.last, p.sequence = p.last+1, 0
+	}
+
+	return Snowflake(p.last<<(snowflakeNodeBits+snowflakeSequenceBits) | p.node<<snowflakeSequenceBits | p.sequence)
+}
+
+func (p *SnowflakeProvider) ParseID(key string) (example.Identifier, error) {
+	var id Snowflake
+	if err := id.UnmarshalText([]byte(key)); err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
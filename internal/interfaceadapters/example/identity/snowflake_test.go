@@ -0,0 +1,126 @@
+package identity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SnowflakeText(t *testing.T) {
+	testCases := []struct {
+		name          string
+		text          string
+		identifier    Snowflake
+		expectedError error
+	}{
+		{
+			name:       "zero",
+			text:       "0",
+			identifier: 0,
+		},
+		{
+			name:       "made",
+			text:       "1234567890123456789",
+			identifier: 1234567890123456789,
+		},
+		{
+			name:       "largest",
+			text:       "9223372036854775807",
+			identifier: 1<<63 - 1,
+		},
+		{
+			name:          "overflow",
+			text:          "9223372036854775808",
+			expectedError: ErrIdentifier,
+		},
+		{
+			name:          "leading-zero",
+			text:          "0123",
+			expectedError: ErrIdentifier,
+		},
+		{
+			name:          "sign",
+			text:          "+123",
+			expectedError: ErrIdentifier,
+		},
+		{
+			name:          "negative",
+			text:          "-123",
+			expectedError: ErrIdentifier,
+		},
+		{
+			name:          "empty",
+			text:          "",
+			expectedError: ErrIdentifier,
+		},
+		{
+			name:          "too-long",
+			text:          "12345678901234567890",
+			expectedError: ErrIdentifier,
+		},
+		{
+			name:          "underscores",
+			text:          "1_000",
+			expectedError: ErrIdentifier,
+		},
+	}
+
+	for _, c := range testCases {
+		testname := c.name
+		text := c.text
+		identifier := c.identifier
+		expectedError := c.expectedError
+
+		t.Run(testname, func(t *testing.T) {
+			var id Snowflake
+			err := id.UnmarshalText([]byte(text))
+
+			if expectedError != nil {
+				assert.ErrorIs(t, err, expectedError)
+				assert.Equal(t, Snowflake(0), id)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, identifier, id)
+			assert.Equal(t, text, id.String())
+		})
+	}
+}
+
+func Test_NewSnowflakeProvider(t *testing.T) {
+	for _, node := range []int64{-1, maxSnowflakeNode + 1} {
+		p, err := NewSnowflakeProvider(node)
+		assert.ErrorIs(t, err, ErrReadConfig, "node %d", node)
+		assert.Nil(t, p)
+	}
+}
+
+func Test_SnowflakeLayout(t *testing.T) {
+	now := time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	p, err := NewSnowflakeProvider(maxSnowflakeNode)
+	require.NoError(t, err)
+	p.source = fixedSource(now, 0)
+
+	var last Snowflake
+	for i := int64(0); i <= maxSnowflakeSequence; i++ {
+		last = p.NewID().(Snowflake)
+
+		assert.Equal(t, now, last.Time().UTC())
+		assert.Equal(t, maxSnowflakeNode, last.Node())
+		assert.Equal(t, i, int64(last)&maxSnowflakeSequence)
+	}
+
+	carried := p.NewID().(Snowflake)
+	assert.Equal(t, now.Add(time.Millisecond), carried.Time().UTC(), "the sequence ran out")
+	assert.Equal(t, int64(0), int64(carried)&maxSnowflakeSequence)
+	assert.Less(t, last, carried)
+
+	p.source = fixedSource(time.Unix(0, 0), 0)
+	early := p.NewID().(Snowflake)
+	assert.Less(t, carried, early, "a clock before the epoch keeps the order")
+}
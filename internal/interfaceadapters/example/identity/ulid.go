@@ -0,0 +1,149 @@
+package identity
+
+import (
+	"sync"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const (
+	ulidTextLen int = 26
+
+	crockfordAlphabet string = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// crockfordValues maps the characters of crockfordAlphabet, in either case,
+// to their value and every other byte to noDigit.
+var crockfordValues = digitValues(crockfordAlphabet, true)
+
+// noDigit marks the bytes that are no digit in a digitValues table.
+const noDigit byte = 0xff
+
+func digitValues(alphabet string, foldCase bool) [256]byte {
+	var values [256]byte
+	for i := range values {
+		values[i] = noDigit
+	}
+
+	for i := 0; i < len(alphabet); i++ {
+		values[alphabet[i]] = byte(i)
+		if c := alphabet[i]; foldCase && c >= 'A' && c <= 'Z' {
+			values[c+'a'-'A'] = byte(i)
+		}
+	}
+
+	return values
+}
+
+// ULID is 48 bits of Unix milliseconds and 80 random bits, written in
+// Crockford's base32.
+type ULID [16]byte
+
+func (id ULID) String() string {
+	var text [ulidTextLen]byte
+	id.encode(text[:])
+
+	return string(text[:])
+}
+
+// Time is the millisecond the identifier was made in.
+func (id ULID) Time() time.Time {
+	return time.UnixMilli(int64(getUint48(id[:6])))
+}
+
+func (id ULID) MarshalText() ([]byte, error) {
+	text := make([]byte, ulidTextLen)
+	id.encode(text)
+
+	return text, nil
+}
+
+// UnmarshalText takes 26 base32 digits in either case. The letters I, L, O
+// and U, which Crockford's decoding would alias or exclude, are rejected.
+func (id *ULID) UnmarshalText(text []byte) error {
+	if len(text) != ulidTextLen {
+		return invalid(ULIDStrategy, text, "is not 26 characters long")
+	}
+
+	var parsed ULID
+	for i, c := range text {
+		v := crockfordValues[c]
+		if v == noDigit {
+			return invalid(ULIDStrategy, text, "is not base32 digits")
+		}
+
+		// The first digit holds the two bits above the 128 of a ULID.
+		if i == 0 && v > 7 {
+			return invalid(ULIDStrategy, text, "overflows 128 bits")
+		}
+
+		for b := 0; b < 5; b++ {
+			bit := i*5 + b - 2
+			if bit >= 0 && v>>(4-b)&1 == 1 {
+				parsed[bit/8] |= 0x80 >> (bit % 8)
+			}
+		}
+	}
+
+	*id = parsed
+
+	return nil
+}
+
+// encode writes the 128 bits five at a time, as a 130 bits number with two
+// leading zeros.
+func (id ULID) encode(text []byte) {
+	for i := 0; i < ulidTextLen; i++ {
+		var v byte
+		for b := 0; b < 5; b++ {
+			v <<= 1
+			if bit := i*5 + b - 2; bit >= 0 {
+				v |= id[bit/8] >> (7 - bit%8) & 1
+			}
+		}
+
+		text[i] = crockfordAlphabet[v]
+	}
+}
+
+// ULIDProvider makes identifiers that sort in the order they were made: in
+// the same millisecond the random part goes up by one, and when it runs out,
+// or the clock goes back, the provider carries on from its last millisecond.
+type ULIDProvider struct {
+	mu     sync.Mutex
+	source source
+	last   ULID
+}
+
+func NewULIDProvider() *ULIDProvider {
+	return &ULIDProvider{source: newSource()}
+}
+
+func (p *ULIDProvider) NewID() example.Identifier {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	last := int64(getUint48(p.last[:6]))
+	ms := p.source.now().UnixMilli()
+
+	switch {
+	case ms > last:
+		putUint48(p.last[:6], uint64(ms))
+		p.source.random(p.last[6:])
+	case increment(p.last[6:]):
+		putUint48(p.last[:6], uint64(last+1))
+		p.source.random(p.last[6:])
+	}
+
+	return p.last
+}
+
+func (p *ULIDProvider) ParseID(key string) (example.Identifier, error) {
+	var id ULID
+	if err := id.UnmarshalText([]byte(key)); err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
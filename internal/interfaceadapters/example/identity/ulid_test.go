@@ -0,0 +1,107 @@
+package identity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ULIDText(t *testing.T) {
+	testCases := []struct {
+		name          string
+		text          string
+		expectedText  string
+		expectedTime  int64
+		expectedError error
+	}{
+		{
+			name:         "spec-example",
+			text:         "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+			expectedText: "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+			expectedTime: 1469922850259,
+		},
+		{
+			name:         "lower-case",
+			text:         "01arz3ndektsv4rrffq69g5fav",
+			expectedText: "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+			expectedTime: 1469922850259,
+		},
+		{
+			name:         "largest",
+			text:         "7ZZZZZZZZZZZZZZZZZZZZZZZZZ",
+			expectedText: "7ZZZZZZZZZZZZZZZZZZZZZZZZZ",
+			expectedTime: 1<<48 - 1,
+		},
+		{
+			name:          "overflow",
+			text:          "8ZZZZZZZZZZZZZZZZZZZZZZZZZ",
+			expectedError: ErrIdentifier,
+		},
+		{
+			name:          "too-short",
+			text:          "01ARZ3NDEKTSV4RRFFQ69G5FA",
+			expectedError: ErrIdentifier,
+		},
+		{
+			name:          "excluded-letter",
+			text:          "01ARZ3NDEKTSV4RRFFQ69G5FAU",
+			expectedError: ErrIdentifier,
+		},
+		{
+			name:          "aliased-letter",
+			text:          "0LARZ3NDEKTSV4RRFFQ69G5FAV",
+			expectedError: ErrIdentifier,
+		},
+		{
+			name:          "hyphenated",
+			text:          "01ARZ3NDEK-SV4RRFFQ69G5FAV",
+			expectedError: ErrIdentifier,
+		},
+	}
+
+	for _, c := range testCases {
+		testname := c.name
+		text := c.text
+		expectedText := c.expectedText
+		expectedTime := c.expectedTime
+		expectedError := c.expectedError
+
+		t.Run(testname, func(t *testing.T) {
+			var id ULID
+			err := id.UnmarshalText([]byte(text))
+
+			if expectedError != nil {
+				assert.ErrorIs(t, err, expectedError)
+				assert.Equal(t, ULID{}, id)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, expectedText, id.String())
+			assert.Equal(t, expectedTime, id.Time().UnixMilli())
+		})
+	}
+}
+
+func Test_ULIDRandomOverflow(t *testing.T) {
+	now := time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	p := NewULIDProvider()
+	p.source = fixedSource(now, 0xff)
+
+	first := p.NewID().(ULID)
+	assert.Equal(t, now, first.Time().UTC())
+
+	p.source = fixedSource(now, 0x00)
+
+	carried := p.NewID().(ULID)
+	assert.Equal(t, now.Add(time.Millisecond), carried.Time().UTC(), "the random part ran out")
+	assert.Less(t, first.String(), carried.String())
+
+	next := p.NewID().(ULID)
+	assert.Equal(t, carried.Time(), next.Time())
+	assert.Less(t, carried.String(), next.String())
+}
@@ -0,0 +1,150 @@
+package identity
+
+import (
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const (
+	uuidTextLen int = 36
+
+	// uuidCounterMax bounds the counter kept in the rand_a bits, which is
+	// seeded below half of it so a millisecond has room for many more.
+	uuidCounterMax  uint16 = 0x0fff
+	uuidCounterSeed uint16 = 0x07ff
+)
+
+// UUIDv7 is an RFC 9562 version 7 UUID: 48 bits of Unix milliseconds, then a
+// counter in the 12 rand_a bits and 62 random bits.
+type UUIDv7 [16]byte
+
+func (id UUIDv7) String() string {
+	var text [uuidTextLen]byte
+	id.encode(text[:])
+
+	return string(text[:])
+}
+
+// Time is the millisecond the identifier was made in.
+func (id UUIDv7) Time() time.Time {
+	return time.UnixMilli(int64(getUint48(id[:6])))
+}
+
+func (id UUIDv7) MarshalText() ([]byte, error) {
+	text := make([]byte, uuidTextLen)
+	id.encode(text)
+
+	return text, nil
+}
+
+// UnmarshalText takes the hyphenated form only, in either case, of a version
+// 7 UUID of the RFC 9562 variant.
+func (id *UUIDv7) UnmarshalText(text []byte) error {
+	if len(text) != uuidTextLen {
+		return invalid(UUIDv7Strategy, text, "is not 36 characters long")
+	}
+
+	var parsed UUIDv7
+	from := 0
+	for _, group := range [][2]int{{0, 4}, {4, 6}, {6, 8}, {8, 10}, {10, 16}} {
+		to := from + 2*(group[1]-group[0])
+		if _, err := hex.Decode(parsed[group[0]:group[1]], text[from:to]); err != nil {
+			return invalid(UUIDv7Strategy, text, "is not hex digits")
+		}
+
+		if to < uuidTextLen && text[to] != '-' {
+			return invalid(UUIDv7Strategy, text, "is not hyphenated 8-4-4-4-12")
+		}
+
+		from = to + 1
+	}
+
+	if parsed[6]>>4 != 7 {
+		return invalid(UUIDv7Strategy, text, "is not version 7")
+	}
+
+	if parsed[8]>>6 != 0b10 {
+		return invalid(UUIDv7Strategy, text, "is not of the RFC 9562 variant")
+	}
+
+	*id = parsed
+
+	return nil
+}
+
+func (id UUIDv7) encode(text []byte) {
+	hex.Encode(text[0:8], id[0:4])
+	text[8] = '-'
+	hex.Encode(text[9:13], id[4:6])
+	text[13] = '-'
+	hex.Encode(text[14:18], id[6:8])
+	text[18] = '-'
+	hex.Encode(text[19:23], id[8:10])
+	text[23] = '-'
+	hex.Encode(text[24:36], id[10:16])
+}
+
+// UUIDv7Provider makes identifiers that sort in the order they were made: in
+// the same millisecond the counter goes up, and when it runs out, or the
+// clock goes back, the provider carries on from its last millisecond.
+type UUIDv7Provider struct {
+	mu      sync.Mutex
+	source  source
+	last    int64
+	counter uint16
+}
+
+func NewUUIDv7Provider() *UUIDv7Provider {
+	return &UUIDv7Provider{source: newSource()}
+}
+
+func (p *UUIDv7Provider) NewID() example.Identifier {
+	var id UUIDv7
+
+	p.mu.Lock()
+	p.source.random(id[6:])
+	seed := (uint16(id[6])<<8 | uint16(id[7])) & uuidCounterSeed
+
+	if ms := p.source.now().UnixMilli(); ms > p.last {
+		p.last, p.counter = ms, seed
+	} else if p.counter++; p.counter > uuidCounterMax {
+		p.last, p.counter = p.last+1, seed
+	}
+
+	putUint48(id[:6], uint64(p.last))
+	id[6] = 0x70 | byte(p.counter>>8)
+	id[7] = byte(p.counter)
+	p.mu.Unlock()
+
+	id[8] = 0x80 | id[8]&0x3f
+
+	return id
+}
+
+func (p *UUIDv7Provider) ParseID(key string) (example.Identifier, error) {
+	var id UUIDv7
+	if err := id.UnmarshalText([]byte(key)); err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+func putUint48(b []byte, v uint64) {
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+}
+
+func getUint48(b []byte) uint64 {
+	var v uint64
+	for _, c := range b[:6] {
+		v = v<<8 | uint64(c)
+	}
+
+	return v
+}
@@ -0,0 +1,116 @@
+package identity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UUIDv7Text(t *testing.T) {
+	// The example UUIDv7 of RFC 9562, appendix A.6.
+	rfc := UUIDv7{0x01, 0x7f, 0x22, 0xe2, 0x79, 0xb0, 0x7c, 0xc3, 0x98, 0xc4, 0xdc, 0x0c, 0x0c, 0x07, 0x39, 0x8f}
+
+	testCases := []struct {
+		name          string
+		text          string
+		identifier    UUIDv7
+		expectedError error
+	}{
+		{
+			name:       "lower-case",
+			text:       "017f22e2-79b0-7cc3-98c4-dc0c0c07398f",
+			identifier: rfc,
+		},
+		{
+			name:       "upper-case",
+			text:       "017F22E2-79B0-7CC3-98C4-DC0C0C07398F",
+			identifier: rfc,
+		},
+		{
+			name:          "no-hyphens",
+			text:          "017f22e279b07cc398c4dc0c0c07398f",
+			expectedError: ErrIdentifier,
+		},
+		{
+			name:          "braces",
+			text:          "{017f22e2-79b0-7cc3-98c4-dc0c0c07398f}",
+			expectedError: ErrIdentifier,
+		},
+		{
+			name:          "urn",
+			text:          "urn:uuid:017f22e2-79b0-7cc3-98c4-dc0c0c07398f",
+			expectedError: ErrIdentifier,
+		},
+		{
+			name:          "misplaced-hyphen",
+			text:          "017f22e279-b0-7cc3-98c4-dc0c0c07398f",
+			expectedError: ErrIdentifier,
+		},
+		{
+			name:          "not-hex",
+			text:          "017f22e2-79b0-7cc3-98c4-dc0c0c07398g",
+			expectedError: ErrIdentifier,
+		},
+		{
+			name:          "version-4",
+			text:          "017f22e2-79b0-4cc3-98c4-dc0c0c07398f",
+			expectedError: ErrIdentifier,
+		},
+		{
+			name:          "microsoft-variant",
+			text:          "017f22e2-79b0-7cc3-c8c4-dc0c0c07398f",
+			expectedError: ErrIdentifier,
+		},
+	}
+
+	for _, c := range testCases {
+		testname := c.name
+		text := c.text
+		identifier := c.identifier
+		expectedError := c.expectedError
+
+		t.Run(testname, func(t *testing.T) {
+			var id UUIDv7
+			err := id.UnmarshalText([]byte(text))
+
+			if expectedError != nil {
+				assert.ErrorIs(t, err, expectedError)
+				assert.Equal(t, UUIDv7{}, id)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, identifier, id)
+			assert.Equal(t, "017f22e2-79b0-7cc3-98c4-dc0c0c07398f", id.String())
+			assert.Equal(t, int64(1645557742000), id.Time().UnixMilli())
+		})
+	}
+}
+
+func Test_UUIDv7CounterOverflow(t *testing.T) {
+	now := time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	p := NewUUIDv7Provider()
+	p.source = fixedSource(now, 0xff)
+
+	first := p.NewID().(UUIDv7)
+	assert.Equal(t, byte(0x77), first[6], "the counter is seeded below its half")
+	assert.Equal(t, byte(0xff), first[7])
+	assert.Equal(t, byte(0xbf), first[8], "the variant bits are set")
+
+	var last UUIDv7
+	for i := uuidCounterSeed; i < uuidCounterMax; i++ {
+		last = p.NewID().(UUIDv7)
+	}
+
+	assert.Equal(t, now, last.Time().UTC())
+	assert.Equal(t, byte(0x7f), last[6])
+	assert.Equal(t, byte(0xff), last[7])
+
+	carried := p.NewID().(UUIDv7)
+	assert.Equal(t, now.Add(time.Millisecond), carried.Time().UTC())
+	assert.Less(t, last.String(), carried.String())
+}
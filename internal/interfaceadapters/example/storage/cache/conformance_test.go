@@ -0,0 +1,28 @@
+package cache
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"clean-arquitecture-template/internal/domain/example"
+	"clean-arquitecture-template/internal/interfaceadapters/example/storage/memory"
+	"clean-arquitecture-template/internal/interfaceadapters/example/storage/repotest"
+)
+
+func Test_Conformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) (example.LineRepository, example.IdentityProvider) {
+		cnf, err := memory.ReadConfig(configReaderMock{
+			f: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"implementation": "sharded", "shards": 4}`), nil
+			},
+		})
+		require.NoError(t, err)
+
+		cached := NewRepository(memory.NewShardedRepo(cnf), testConfig, WithRemote(newMapRemote()))
+
+		return cached, memory.NewIdentityProvider()
+	})
+}
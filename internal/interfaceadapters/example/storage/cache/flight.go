@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+type call struct {
+	done chan struct{}
+	line *example.Line
+	err  error
+}
+
+// flight runs one backend read per key at a time, the callers asking for a
+// key already being read wait for that read and share its result.
+type flight struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+func newFlight() *flight {
+	return &flight{calls: make(map[string]*call)}
+}
+
+// do reports whether the result was shared with an earlier caller. The read
+// runs apart from the callers and each of them only waits for it until its
+// own ctx is done, so a caller giving up cancels neither the read nor the
+// wait of the others.
+func (f *flight) do(ctx context.Context, key string, read func() (*example.Line, error)) (*example.Line, error, bool) {
+	f.mu.Lock()
+	c, shared := f.calls[key]
+	if !shared {
+		c = &call{done: make(chan struct{})}
+		f.calls[key] = c
+
+		go f.run(key, c, read)
+	}
+	f.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.line, c.err, shared
+	case <-ctx.Done():
+		err := ctx.Err()
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("%s: %w", err.Error(), example.ErrTimeout)
+		}
+
+		return nil, err, shared
+	}
+}
+
+func (f *flight) run(key string, c *call, read func() (*example.Line, error)) {
+	c.line, c.err = read()
+
+	f.mu.Lock()
+	if f.calls[key] == c {
+		delete(f.calls, key)
+	}
+	f.mu.Unlock()
+
+	close(c.done)
+}
+
+// forget makes the next callers start a new read, so they do not wait for
+// one started before a change of the line.
+func (f *flight) forget(keys ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, key := range keys {
+		delete(f.calls, key)
+	}
+}
+
+// detached keeps the values of the context it wraps, but neither its
+// deadline nor its cancellation, so a read shared by several callers does
+// not end with the one that started it.
+type detached struct {
+	ctx context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detached) Done() <-chan struct{} {
+	return nil
+}
+
+func (detached) Err() error {
+	return nil
+}
+
+func (d detached) Value(key interface{}) interface{} {
+	return d.ctx.Value(key)
+}
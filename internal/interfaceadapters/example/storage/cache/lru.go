@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+// entry caches a line, or the absence of one when line is nil.
+type entry struct {
+	key     string
+	line    *example.Line
+	expires time.Time
+}
+
+// lru keeps the size most recently used entries. Expired entries are dropped
+// when they are looked up, or evicted like any other.
+type lru struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func newLRU(size int) *lru {
+	return &lru{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *lru) get(key string, now time.Time) (entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, exists := c.entries[key]
+	if !exists {
+		return entry{}, false
+	}
+
+	e := element.Value.(entry)
+	if !now.Before(e.expires) {
+		c.remove(element)
+		return entry{}, false
+	}
+
+	c.order.MoveToFront(element)
+
+	return e, true
+}
+
+// add stores e and reports how many entries were evicted to make room.
+func (c *lru) add(e entry) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, exists := c.entries[e.key]; exists {
+		element.Value = e
+		c.order.MoveToFront(element)
+
+		return 0
+	}
+
+	c.entries[e.key] = c.order.PushFront(e)
+
+	evicted := 0
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+		evicted++
+	}
+
+	return evicted
+}
+
+func (c *lru) delete(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if element, exists := c.entries[key]; exists {
+			c.remove(element)
+		}
+	}
+}
+
+func (c *lru) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+}
+
+func (c *lru) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *lru) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(entry).key)
+}
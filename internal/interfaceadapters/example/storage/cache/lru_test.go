@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+func Test_LRUEvictsLeastRecentlyUsed(t *testing.T) {
+	now := time.Date(2023, time.May, 1, 12, 0, 0, 0, time.UTC)
+	line := &example.Line{Data: "first-line"}
+	c := newLRU(2)
+
+	assert.Equal(t, 0, c.add(entry{key: "one", line: line, expires: now.Add(time.Minute)}))
+	assert.Equal(t, 0, c.add(entry{key: "two", expires: now.Add(time.Minute)}))
+
+	_, found := c.get("one", now)
+	assert.True(t, found)
+
+	assert.Equal(t, 1, c.add(entry{key: "three", expires: now.Add(time.Minute)}))
+
+	_, found = c.get("two", now)
+	assert.False(t, found)
+
+	e, found := c.get("one", now)
+	assert.True(t, found)
+	assert.Equal(t, line, e.line)
+
+	_, found = c.get("three", now.Add(time.Minute))
+	assert.False(t, found)
+	assert.Equal(t, 1, c.len())
+
+	c.clear()
+	assert.Equal(t, 0, c.len())
+}
@@ -0,0 +1,537 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const (
+	ErrReadConfig cacheError = "unable to read cache config"
+
+	ConfigNode string = "apps.example.interface-adapters.storage.cache"
+
+	defaultSize        int           = 10000
+	defaultTTL         time.Duration = time.Minute
+	defaultNegativeTTL time.Duration = 5 * time.Second
+	defaultTimeout     time.Duration = 5 * time.Second
+
+	keyPrefix string = "line:"
+)
+
+type cacheError string
+
+func (ce cacheError) Error() string {
+	return string(ce)
+}
+
+type Config interface {
+	Enabled() bool
+	Size() int
+	TTL() time.Duration
+	NegativeTTL() time.Duration
+	Timeout() time.Duration
+}
+
+type config struct {
+	On        bool   `json:"enabled"`
+	Entries   int    `json:"size"`
+	Ttl       string `json:"ttl"`
+	NotFound  string `json:"negative-ttl"`
+	ReadLimit string `json:"timeout"`
+	ttl       time.Duration
+	notFound  time.Duration
+	timeout   time.Duration
+}
+
+func (c config) Enabled() bool {
+	return c.On
+}
+
+func (c config) Size() int {
+	return c.Entries
+}
+
+func (c config) TTL() time.Duration {
+	return c.ttl
+}
+
+// NegativeTTL is how long a line not found is remembered as missing.
+func (c config) NegativeTTL() time.Duration {
+	return c.notFound
+}
+
+// Timeout bounds a read shared by several callers, which none of them can
+// cancel.
+func (c config) Timeout() time.Duration {
+	return c.timeout
+}
+
+type ConfigReader interface {
+	Find(node string) (io.Reader, error)
+}
+
+func ReadConfig(cfnReader ConfigReader) (Config, error) {
+	reader, err := cfnReader.Find(ConfigNode)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	d, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	cnf := config{
+		Entries:  defaultSize,
+		ttl:      defaultTTL,
+		notFound: defaultNegativeTTL,
+		timeout:  defaultTimeout,
+	}
+	if err = json.Unmarshal(d, &cnf); err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	if cnf.Entries <= 0 {
+		return nil, fmt.Errorf("size %d: %w", cnf.Entries, ErrReadConfig)
+	}
+
+	if cnf.Ttl != "" {
+		if cnf.ttl, err = time.ParseDuration(cnf.Ttl); err != nil {
+			return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+		}
+	}
+
+	if cnf.NotFound != "" {
+		if cnf.notFound, err = time.ParseDuration(cnf.NotFound); err != nil {
+			return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+		}
+	}
+
+	if cnf.ReadLimit != "" {
+		if cnf.timeout, err = time.ParseDuration(cnf.ReadLimit); err != nil {
+			return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+		}
+	}
+
+	return cnf, nil
+}
+
+// RemoteEntry is a cached line, encoded, to be kept for TTL.
+type RemoteEntry struct {
+	Key   string
+	Value []byte
+	TTL   time.Duration
+}
+
+// RemoteCache is a cache shared by several processes, looked up after the
+// local one. Get returns one value per key, nil for the keys not cached.
+type RemoteCache interface {
+	Get(ctx context.Context, keys []string) ([][]byte, error)
+	Set(ctx context.Context, entries []RemoteEntry) error
+	Delete(ctx context.Context, keys []string) error
+}
+
+// Stats counts the lookups of a Repository. A negative hit is a line known
+// not to exist, a coalesced read one that waited for a read of the same line
+// already running.
+type Stats struct {
+	Hits         int64 `json:"hits"`
+	NegativeHits int64 `json:"negative_hits"`
+	RemoteHits   int64 `json:"remote_hits"`
+	Misses       int64 `json:"misses"`
+	Coalesced    int64 `json:"coalesced"`
+	Evictions    int64 `json:"evictions"`
+	RemoteErrors int64 `json:"remote_errors"`
+	Entries      int64 `json:"entries"`
+}
+
+type counters struct {
+	hits, negativeHits, remoteHits, misses, coalesced, evictions, remoteErrors atomic.Int64
+}
+
+type Option func(*Repository)
+
+// WithRemote looks the lines up in remote when they are not cached locally.
+// Errors of the remote cache count as misses.
+func WithRemote(remote RemoteCache) Option {
+	return func(r *Repository) {
+		r.remote = remote
+	}
+}
+
+// Repository caches the lines read from the repository it wraps. Changing a
+// line through it drops the line from the caches, Purge drops every line
+// cached locally; lines purged stay in the remote cache until they expire.
+type Repository struct {
+	example.LineRepository
+
+	local  *lru
+	remote RemoteCache
+	flight *flight
+
+	ttl         time.Duration
+	negativeTTL time.Duration
+	timeout     time.Duration
+	now         func() time.Time
+
+	// epoch changes with every change of a line, a read started before one
+	// does not fill the caches since it may have read the line unchanged.
+	epoch atomic.Uint64
+	stats counters
+}
+
+func NewRepository(repo example.LineRepository, cnf Config, opts ...Option) *Repository {
+	r := &Repository{
+		LineRepository: repo,
+		local:          newLRU(cnf.Size()),
+		flight:         newFlight(),
+		ttl:            cnf.TTL(),
+		negativeTTL:    cnf.NegativeTTL(),
+		timeout:        cnf.Timeout(),
+		now:            time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Publish exposes the stats as the expvar variable name.
+func (r *Repository) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return r.Stats()
+	}))
+}
+
+func (r *Repository) Stats() Stats {
+	return Stats{
+		Hits:         r.stats.hits.Load(),
+		NegativeHits: r.stats.negativeHits.Load(),
+		RemoteHits:   r.stats.remoteHits.Load(),
+		Misses:       r.stats.misses.Load(),
+		Coalesced:    r.stats.coalesced.Load(),
+		Evictions:    r.stats.evictions.Load(),
+		RemoteErrors: r.stats.remoteErrors.Load(),
+		Entries:      int64(r.local.len()),
+	}
+}
+
+func (r *Repository) Read(ctx context.Context, id example.Identifier) (*example.Line, error) {
+	key := cacheKey(id)
+
+	if line, found := r.lookup(key); found {
+		return line, nil
+	}
+
+	line, err, shared := r.flight.do(ctx, key, func() (*example.Line, error) {
+		ctx, cancel := r.sharedContext(ctx)
+		defer cancel()
+
+		epoch := r.epoch.Load()
+
+		values := r.remoteGet(ctx, []string{key})
+		if values[0] != nil {
+			if line, ok := decode(id, values[0]); ok {
+				r.stats.remoteHits.Add(1)
+				r.fillLocal(epoch, key, line)
+
+				return line, nil
+			}
+		}
+
+		r.stats.misses.Add(1)
+
+		line, err := r.LineRepository.Read(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		r.fill(ctx, epoch, []string{key}, []*example.Line{line})
+
+		return line, nil
+	})
+	if shared {
+		r.stats.coalesced.Add(1)
+	}
+
+	return copyLine(line), err
+}
+
+// sharedContext detaches the read shared by the callers of Read from the one
+// starting it and bounds it by the cache timeout, when there is one.
+func (r *Repository) sharedContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.timeout <= 0 {
+		return context.WithCancel(detached{ctx})
+	}
+
+	return context.WithTimeout(detached{ctx}, r.timeout)
+}
+
+// ReadMany reads from the repository, in one call, the lines not cached.
+func (r *Repository) ReadMany(ctx context.Context, ids []example.Identifier) ([]*example.Line, error) {
+	epoch := r.epoch.Load()
+	lines := make([]*example.Line, len(ids))
+
+	var missed []int
+	for i, id := range ids {
+		line, found := r.lookup(cacheKey(id))
+		if !found {
+			missed = append(missed, i)
+			continue
+		}
+
+		lines[i] = line
+	}
+
+	if len(missed) == 0 {
+		return lines, nil
+	}
+
+	keys := make([]string, len(missed))
+	for j, i := range missed {
+		keys[j] = cacheKey(ids[i])
+	}
+
+	var (
+		stillMissed []int
+		fromRemote  []string
+		remoteLines []*example.Line
+	)
+	for j, value := range r.remoteGet(ctx, keys) {
+		i := missed[j]
+
+		line, ok := decode(ids[i], value)
+		if value == nil || !ok {
+			stillMissed = append(stillMissed, i)
+			continue
+		}
+
+		r.stats.remoteHits.Add(1)
+		lines[i] = copyLine(line)
+		fromRemote = append(fromRemote, keys[j])
+		remoteLines = append(remoteLines, line)
+	}
+
+	for j, key := range fromRemote {
+		r.fillLocal(epoch, key, remoteLines[j])
+	}
+
+	if len(stillMissed) == 0 {
+		return lines, nil
+	}
+
+	r.stats.misses.Add(int64(len(stillMissed)))
+
+	missedIDs := make([]example.Identifier, len(stillMissed))
+	missedKeys := make([]string, len(stillMissed))
+	for j, i := range stillMissed {
+		missedIDs[j] = ids[i]
+		missedKeys[j] = cacheKey(ids[i])
+	}
+
+	read, err := r.LineRepository.ReadMany(ctx, missedIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for j, i := range stillMissed {
+		lines[i] = copyLine(read[j])
+	}
+
+	r.fill(ctx, epoch, missedKeys, read)
+
+	return lines, nil
+}
+
+func (r *Repository) Write(ctx context.Context, line example.Line) error {
+	defer r.invalidate(ctx, line.ID)
+
+	return r.LineRepository.Write(ctx, line)
+}
+
+func (r *Repository) WriteMany(ctx context.Context, lines []example.Line) []error {
+	ids := make([]example.Identifier, len(lines))
+	for i, line := range lines {
+		ids[i] = line.ID
+	}
+
+	defer r.invalidate(ctx, ids...)
+
+	return r.LineRepository.WriteMany(ctx, lines)
+}
+
+func (r *Repository) Delete(ctx context.Context, id example.Identifier, at time.Time) (bool, error) {
+	defer r.invalidate(ctx, id)
+
+	return r.LineRepository.Delete(ctx, id, at)
+}
+
+func (r *Repository) Restore(ctx context.Context, id example.Identifier) (bool, error) {
+	defer r.invalidate(ctx, id)
+
+	return r.LineRepository.Restore(ctx, id)
+}
+
+func (r *Repository) Purge(ctx context.Context, before time.Time) ([]example.Line, error) {
+	defer func() {
+		r.epoch.Add(1)
+		r.local.clear()
+	}()
+
+	return r.LineRepository.Purge(ctx, before)
+}
+
+// lookup reports whether key is cached locally, with a nil line when the
+// line is known not to exist.
+func (r *Repository) lookup(key string) (*example.Line, bool) {
+	e, found := r.local.get(key, r.now())
+	if !found {
+		return nil, false
+	}
+
+	if e.line == nil {
+		r.stats.negativeHits.Add(1)
+		return nil, true
+	}
+
+	r.stats.hits.Add(1)
+
+	return copyLine(e.line), true
+}
+
+func (r *Repository) remoteGet(ctx context.Context, keys []string) [][]byte {
+	if r.remote == nil {
+		return make([][]byte, len(keys))
+	}
+
+	values, err := r.remote.Get(ctx, keys)
+	if err != nil || len(values) != len(keys) {
+		r.stats.remoteErrors.Add(1)
+		return make([][]byte, len(keys))
+	}
+
+	return values
+}
+
+func (r *Repository) fill(ctx context.Context, epoch uint64, keys []string, lines []*example.Line) {
+	entries := make([]RemoteEntry, 0, len(keys))
+	for i, key := range keys {
+		if !r.fillLocal(epoch, key, lines[i]) {
+			return
+		}
+
+		entries = append(entries, RemoteEntry{Key: key, Value: encode(lines[i]), TTL: r.expiry(lines[i])})
+	}
+
+	if r.remote == nil || len(entries) == 0 {
+		return
+	}
+
+	if err := r.remote.Set(ctx, entries); err != nil {
+		r.stats.remoteErrors.Add(1)
+	}
+}
+
+// fillLocal reports false, caching nothing, when a line changed since epoch.
+func (r *Repository) fillLocal(epoch uint64, key string, line *example.Line) bool {
+	if r.epoch.Load() != epoch {
+		return false
+	}
+
+	evicted := r.local.add(entry{key: key, line: copyLine(line), expires: r.now().Add(r.expiry(line))})
+	r.stats.evictions.Add(int64(evicted))
+
+	return true
+}
+
+func (r *Repository) expiry(line *example.Line) time.Duration {
+	if line == nil {
+		return r.negativeTTL
+	}
+
+	return r.ttl
+}
+
+func (r *Repository) invalidate(ctx context.Context, ids ...example.Identifier) {
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == nil {
+			continue
+		}
+
+		keys = append(keys, cacheKey(id))
+	}
+
+	r.epoch.Add(1)
+	r.flight.forget(keys...)
+	r.local.delete(keys...)
+
+	if r.remote == nil || len(keys) == 0 {
+		return
+	}
+
+	if err := r.remote.Delete(ctx, keys); err != nil {
+		r.stats.remoteErrors.Add(1)
+	}
+}
+
+func cacheKey(id example.Identifier) string {
+	return keyPrefix + id.String()
+}
+
+func copyLine(line *example.Line) *example.Line {
+	if line == nil {
+		return nil
+	}
+
+	l := *line
+
+	return &l
+}
+
+// remoteLine is a cached line in the remote cache, Found false when the line
+// is known not to exist.
+type remoteLine struct {
+	Found     bool      `json:"found"`
+	Created   time.Time `json:"created"`
+	Data      string    `json:"data"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func encode(line *example.Line) []byte {
+	rl := remoteLine{}
+	if line != nil {
+		rl = remoteLine{Found: true, Created: line.Created, Data: line.Data, DeletedAt: line.DeletedAt}
+	}
+
+	d, _ := json.Marshal(rl)
+
+	return d
+}
+
+// decode reports false when value is not a cached line.
+func decode(id example.Identifier, value []byte) (*example.Line, bool) {
+	if value == nil {
+		return nil, false
+	}
+
+	var rl remoteLine
+	if err := json.Unmarshal(value, &rl); err != nil {
+		return nil, false
+	}
+
+	if !rl.Found {
+		return nil, true
+	}
+
+	return &example.Line{ID: id, Created: rl.Created, Data: rl.Data, DeletedAt: rl.DeletedAt}, true
+}
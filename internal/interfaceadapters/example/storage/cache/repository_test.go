@@ -0,0 +1,613 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+type configReaderMock struct {
+	f func(node string) (io.Reader, error)
+}
+
+func (crm configReaderMock) Find(node string) (io.Reader, error) {
+	return crm.f(node)
+}
+
+func Test_ReadConfig(t *testing.T) {
+	testCases := []struct {
+		testName            string
+		buildConfigReader   func(node string) (io.Reader, error)
+		expectedEnabled     bool
+		expectedSize        int
+		expectedTTL         time.Duration
+		expectedNegativeTTL time.Duration
+		expectedTimeout     time.Duration
+		expectedError       error
+	}{
+		{
+			testName: "config-find-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return nil, errors.New("not found")
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "config-unmarshal-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader("{"), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "defaults-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{}`), nil
+			},
+			expectedSize:        defaultSize,
+			expectedTTL:         defaultTTL,
+			expectedNegativeTTL: defaultNegativeTTL,
+			expectedTimeout:     defaultTimeout,
+		},
+		{
+			testName: "success-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"enabled": true, "size": 10, "ttl": "30s", "negative-ttl": "1s", "timeout": "2s"}`), nil
+			},
+			expectedEnabled:     true,
+			expectedSize:        10,
+			expectedTTL:         30 * time.Second,
+			expectedNegativeTTL: time.Second,
+			expectedTimeout:     2 * time.Second,
+		},
+		{
+			testName: "size-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"size": 0}`), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "ttl-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"ttl": "soon"}`), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "negative-ttl-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"negative-ttl": "soon"}`), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "timeout-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"timeout": "soon"}`), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+	}
+
+	for _, c := range testCases {
+		name := c.testName
+		reader := configReaderMock{c.buildConfigReader}
+		expectedEnabled := c.expectedEnabled
+		expectedSize := c.expectedSize
+		expectedTTL := c.expectedTTL
+		expectedNegativeTTL := c.expectedNegativeTTL
+		expectedTimeout := c.expectedTimeout
+		expectedError := c.expectedError
+
+		t.Run(name, func(t *testing.T) {
+			cnf, err := ReadConfig(reader)
+			assert.ErrorIs(t, err, expectedError)
+
+			if expectedError != nil {
+				return
+			}
+
+			assert.Equal(t, expectedEnabled, cnf.Enabled())
+			assert.Equal(t, expectedSize, cnf.Size())
+			assert.Equal(t, expectedTTL, cnf.TTL())
+			assert.Equal(t, expectedNegativeTTL, cnf.NegativeTTL())
+			assert.Equal(t, expectedTimeout, cnf.Timeout())
+		})
+	}
+}
+
+// mapRemote is a remote cache ignoring the TTLs.
+type mapRemote struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	err    error
+}
+
+func newMapRemote() *mapRemote {
+	return &mapRemote{values: make(map[string][]byte)}
+}
+
+func (mr *mapRemote) Get(ctx context.Context, keys []string) ([][]byte, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if mr.err != nil {
+		return nil, mr.err
+	}
+
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		values[i] = mr.values[key]
+	}
+
+	return values, nil
+}
+
+func (mr *mapRemote) Set(ctx context.Context, entries []RemoteEntry) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, e := range entries {
+		mr.values[e.Key] = e.Value
+	}
+
+	return mr.err
+}
+
+func (mr *mapRemote) Delete(ctx context.Context, keys []string) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for _, key := range keys {
+		delete(mr.values, key)
+	}
+
+	return mr.err
+}
+
+var testConfig = config{Entries: 2, ttl: time.Minute, notFound: time.Second}
+
+func Test_RepositoryRead(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, time.May, 1, 12, 0, 0, 0, time.UTC)
+	line := example.Line{ID: example.MockIdentifier("one"), Created: now, Data: "first-line"}
+
+	testCases := []struct {
+		name          string
+		repo          func() *example.MockRepository
+		remote        func() *mapRemote
+		reads         []string
+		elapsed       time.Duration
+		expectedLine  *example.Line
+		expectedStats Stats
+		expectedError error
+	}{
+		{
+			name: "hit-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", mock.Anything, example.MockIdentifier("one")).Return(&line, nil).Once()
+
+				return mr
+			},
+			reads:         []string{"one", "one", "one"},
+			expectedLine:  &line,
+			expectedStats: Stats{Hits: 2, Misses: 1, Entries: 1},
+		},
+		{
+			name: "expired-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", mock.Anything, example.MockIdentifier("one")).Return(&line, nil).Twice()
+
+				return mr
+			},
+			reads:         []string{"one", "one"},
+			elapsed:       time.Minute,
+			expectedLine:  &line,
+			expectedStats: Stats{Misses: 2, Entries: 1},
+		},
+		{
+			name: "negative-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", mock.Anything, example.MockIdentifier("none")).Return((*example.Line)(nil), nil).Once()
+
+				return mr
+			},
+			reads:         []string{"none", "none"},
+			expectedStats: Stats{NegativeHits: 1, Misses: 1, Entries: 1},
+		},
+		{
+			name: "negative-expired-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", mock.Anything, example.MockIdentifier("none")).Return((*example.Line)(nil), nil).Twice()
+
+				return mr
+			},
+			reads:         []string{"none", "none"},
+			elapsed:       time.Second,
+			expectedStats: Stats{Misses: 2, Entries: 1},
+		},
+		{
+			name: "eviction-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", mock.Anything, example.MockIdentifier("one")).Return(&line, nil).Twice()
+				mr.On("Read", mock.Anything, example.MockIdentifier("two")).Return((*example.Line)(nil), nil).Once()
+				mr.On("Read", mock.Anything, example.MockIdentifier("three")).Return((*example.Line)(nil), nil).Once()
+
+				return mr
+			},
+			reads:         []string{"one", "two", "three", "one"},
+			expectedLine:  &line,
+			expectedStats: Stats{Misses: 4, Evictions: 2, Entries: 2},
+		},
+		{
+			name: "remote-hit-case",
+			repo: func() *example.MockRepository {
+				return &example.MockRepository{}
+			},
+			remote: func() *mapRemote {
+				mr := newMapRemote()
+				mr.values[keyPrefix+"one"] = encode(&line)
+
+				return mr
+			},
+			reads:         []string{"one", "one"},
+			expectedLine:  &line,
+			expectedStats: Stats{Hits: 1, RemoteHits: 1, Entries: 1},
+		},
+		{
+			name: "remote-error-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", mock.Anything, example.MockIdentifier("one")).Return(&line, nil).Once()
+
+				return mr
+			},
+			remote: func() *mapRemote {
+				mr := newMapRemote()
+				mr.err = errors.New("unreachable")
+
+				return mr
+			},
+			reads:         []string{"one"},
+			expectedLine:  &line,
+			expectedStats: Stats{Misses: 1, RemoteErrors: 2, Entries: 1},
+		},
+		{
+			name: "repository-error-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", mock.Anything, example.MockIdentifier("one")).Return((*example.Line)(nil), example.ErrTimeout).Twice()
+
+				return mr
+			},
+			reads:         []string{"one", "one"},
+			expectedStats: Stats{Misses: 2},
+			expectedError: example.ErrTimeout,
+		},
+	}
+
+	for _, c := range testCases {
+		repo := c.repo()
+		remote := c.remote
+		reads := c.reads
+		elapsed := c.elapsed
+		expectedLine := c.expectedLine
+		expectedStats := c.expectedStats
+		expectedError := c.expectedError
+
+		t.Run(c.name, func(t *testing.T) {
+			var opts []Option
+			if remote != nil {
+				opts = append(opts, WithRemote(remote()))
+			}
+
+			clock := now
+			cached := NewRepository(repo, testConfig, opts...)
+			cached.now = func() time.Time { return clock }
+
+			var (
+				got *example.Line
+				err error
+			)
+			for _, id := range reads {
+				got, err = cached.Read(ctx, example.MockIdentifier(id))
+				clock = clock.Add(elapsed)
+			}
+
+			assert.Equal(t, expectedLine, got)
+			assert.ErrorIs(t, err, expectedError)
+			assert.Equal(t, expectedStats, cached.Stats())
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_RepositoryReadMany(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, time.May, 1, 12, 0, 0, 0, time.UTC)
+	one := example.Line{ID: example.MockIdentifier("one"), Created: now, Data: "first-line"}
+	two := example.Line{ID: example.MockIdentifier("two"), Created: now, Data: "second-line"}
+
+	repo := &example.MockRepository{}
+	repo.On("Read", mock.Anything, example.MockIdentifier("one")).Return(&one, nil).Once()
+	repo.On("ReadMany", ctx, []example.Identifier{example.MockIdentifier("two"), example.MockIdentifier("none")}).
+		Return([]*example.Line{&two, nil}, nil).Once()
+
+	remote := newMapRemote()
+	cached := NewRepository(repo, config{Entries: 10, ttl: time.Minute, notFound: time.Second}, WithRemote(remote))
+
+	_, err := cached.Read(ctx, example.MockIdentifier("one"))
+	assert.NoError(t, err)
+
+	ids := []example.Identifier{example.MockIdentifier("one"), example.MockIdentifier("two"), example.MockIdentifier("none")}
+	for i := 0; i < 2; i++ {
+		lines, err := cached.ReadMany(ctx, ids)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*example.Line{&one, &two, nil}, lines)
+	}
+
+	assert.Equal(t, Stats{Hits: 3, NegativeHits: 1, Misses: 3, Entries: 3}, cached.Stats())
+	assert.Len(t, remote.values, 3)
+	repo.AssertExpectations(t)
+}
+
+func Test_RepositoryInvalidation(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, time.May, 1, 12, 0, 0, 0, time.UTC)
+	id := example.MockIdentifier("one")
+	line := example.Line{ID: id, Created: now, Data: "first-line"}
+	deleted := example.Line{ID: id, Created: now, Data: "first-line", DeletedAt: now}
+
+	testCases := []struct {
+		name   string
+		repo   func() *example.MockRepository
+		change func(r *Repository)
+		before *example.Line
+		after  *example.Line
+	}{
+		{
+			name: "write-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Write", ctx, line).Return(nil)
+
+				return mr
+			},
+			change: func(r *Repository) {
+				assert.NoError(t, r.Write(ctx, line))
+			},
+			after: &line,
+		},
+		{
+			name: "write-many-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("WriteMany", ctx, []example.Line{line}).Return([]error{nil})
+
+				return mr
+			},
+			change: func(r *Repository) {
+				assert.Equal(t, []error{nil}, r.WriteMany(ctx, []example.Line{line}))
+			},
+			after: &line,
+		},
+		{
+			name: "delete-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Delete", ctx, id, now).Return(true, nil)
+
+				return mr
+			},
+			change: func(r *Repository) {
+				found, err := r.Delete(ctx, id, now)
+				assert.True(t, found)
+				assert.NoError(t, err)
+			},
+			before: &line,
+			after:  &deleted,
+		},
+		{
+			name: "restore-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Restore", ctx, id).Return(true, nil)
+
+				return mr
+			},
+			change: func(r *Repository) {
+				found, err := r.Restore(ctx, id)
+				assert.True(t, found)
+				assert.NoError(t, err)
+			},
+			before: &deleted,
+			after:  &line,
+		},
+		{
+			name: "purge-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Purge", ctx, now).Return([]example.Line{deleted}, nil)
+
+				return mr
+			},
+			change: func(r *Repository) {
+				purged, err := r.Purge(ctx, now)
+				assert.Equal(t, []example.Line{deleted}, purged)
+				assert.NoError(t, err)
+			},
+			before: &deleted,
+		},
+	}
+
+	for _, c := range testCases {
+		repo := c.repo()
+		change := c.change
+		before := c.before
+		after := c.after
+
+		t.Run(c.name, func(t *testing.T) {
+			repo.On("Read", mock.Anything, id).Return(before, nil).Once()
+			repo.On("Read", mock.Anything, id).Return(after, nil).Once()
+
+			remote := newMapRemote()
+			cached := NewRepository(repo, testConfig, WithRemote(remote))
+
+			got, err := cached.Read(ctx, id)
+			assert.NoError(t, err)
+			assert.Equal(t, before, got)
+
+			change(cached)
+
+			if c.name != "purge-case" {
+				assert.Empty(t, remote.values)
+			}
+			delete(remote.values, cacheKey(id))
+
+			got, err = cached.Read(ctx, id)
+			assert.NoError(t, err)
+			assert.Equal(t, after, got)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+// gatedRepository counts the reads and holds them until the gate closes.
+type gatedRepository struct {
+	example.MockRepository
+
+	gate  chan struct{}
+	reads atomic.Int64
+	line  *example.Line
+}
+
+func (gr *gatedRepository) Read(ctx context.Context, id example.Identifier) (*example.Line, error) {
+	gr.reads.Add(1)
+	<-gr.gate
+
+	return gr.line, nil
+}
+
+func Test_RepositoryCoalescesMisses(t *testing.T) {
+	ctx := context.Background()
+	line := example.Line{ID: example.MockIdentifier("one"), Data: "first-line"}
+	repo := &gatedRepository{gate: make(chan struct{}), line: &line}
+	cached := NewRepository(repo, testConfig)
+
+	const readers = 20
+
+	var wg sync.WaitGroup
+	results := make([]*example.Line, readers)
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			results[i], _ = cached.Read(ctx, example.MockIdentifier("one"))
+		}(i)
+	}
+
+	assert.Eventually(t, func() bool {
+		return repo.reads.Load() == 1
+	}, time.Second, time.Millisecond)
+
+	close(repo.gate)
+	wg.Wait()
+
+	// The readers coming after the read either joined it or found its line.
+	stats := cached.Stats()
+	assert.Equal(t, int64(1), repo.reads.Load())
+	assert.Equal(t, int64(1), stats.Misses)
+	assert.Equal(t, int64(readers-1), stats.Coalesced+stats.Hits)
+	for _, result := range results {
+		assert.Equal(t, &line, result)
+	}
+}
+
+func Test_RepositoryLeaderCancelsSharedRead(t *testing.T) {
+	line := example.Line{ID: example.MockIdentifier("one"), Data: "first-line"}
+	repo := &gatedRepository{gate: make(chan struct{}), line: &line}
+	cached := NewRepository(repo, testConfig)
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := cached.Read(leaderCtx, example.MockIdentifier("one"))
+		leaderErr <- err
+	}()
+
+	assert.Eventually(t, func() bool {
+		return repo.reads.Load() == 1
+	}, time.Second, time.Millisecond)
+
+	type result struct {
+		line *example.Line
+		err  error
+	}
+
+	waiter := make(chan result, 1)
+	go func() {
+		line, err := cached.Read(context.Background(), example.MockIdentifier("one"))
+		waiter <- result{line, err}
+	}()
+
+	cancel()
+	assert.ErrorIs(t, <-leaderErr, context.Canceled)
+
+	expired, expire := context.WithTimeout(context.Background(), time.Millisecond)
+	defer expire()
+
+	_, err := cached.Read(expired, example.MockIdentifier("one"))
+	assert.ErrorIs(t, err, example.ErrTimeout)
+
+	close(repo.gate)
+
+	got := <-waiter
+	assert.NoError(t, got.err)
+	assert.Equal(t, &line, got.line)
+	assert.Equal(t, int64(1), repo.reads.Load())
+}
+
+func Test_RepositoryDropsReadsRacingWrites(t *testing.T) {
+	ctx := context.Background()
+	line := example.Line{ID: example.MockIdentifier("one"), Data: "first-line"}
+	repo := &gatedRepository{gate: make(chan struct{}), line: &line}
+	repo.On("Write", ctx, line).Return(nil)
+	cached := NewRepository(repo, testConfig)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		_, _ = cached.Read(ctx, example.MockIdentifier("one"))
+	}()
+
+	assert.Eventually(t, func() bool {
+		return repo.reads.Load() == 1
+	}, time.Second, time.Millisecond)
+
+	assert.NoError(t, cached.Write(ctx, line))
+
+	close(repo.gate)
+	<-done
+
+	assert.Equal(t, int64(0), cached.Stats().Entries)
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+// AuditLog keeps the audit records of every line, oldest first. The records
+// are copied in and out, so nobody can change one once appended.
+type AuditLog struct {
+	mu      *sync.RWMutex
+	records map[identifier][]example.AuditRecord
+}
+
+func NewAuditLog() AuditLog {
+	return AuditLog{
+		mu:      &sync.RWMutex{},
+		records: make(map[identifier][]example.AuditRecord),
+	}
+}
+
+func (al AuditLog) Append(ctx context.Context, records []example.AuditRecord) error {
+	if err := alive(ctx); err != nil {
+		return err
+	}
+
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	for _, record := range records {
+		id := identifier(record.LineID.String())
+		al.records[id] = append(al.records[id], copyRecord(record))
+	}
+
+	return nil
+}
+
+func (al AuditLog) History(ctx context.Context, id example.Identifier) ([]example.AuditRecord, error) {
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+
+	al.mu.RLock()
+	defer al.mu.RUnlock()
+
+	stored := al.records[identifier(id.String())]
+
+	history := make([]example.AuditRecord, len(stored))
+	for i, record := range stored {
+		history[i] = copyRecord(record)
+	}
+
+	return history, nil
+}
+
+func copyRecord(record example.AuditRecord) example.AuditRecord {
+	if record.Before != nil {
+		before := *record.Before
+		record.Before = &before
+	}
+
+	if record.After != nil {
+		after := *record.After
+		record.After = &after
+	}
+
+	return record
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"clean-arquitecture-template/internal/domain/example"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AuditLog(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+
+	created := example.Line{ID: identifier("a"), Created: at, Data: "first line"}
+	deleted := created
+	deleted.DeletedAt = at.Add(time.Hour)
+
+	records := []example.AuditRecord{
+		{LineID: identifier("a"), Action: example.AuditCreate, Actor: "alice", RequestID: "r1", At: at, After: &created},
+		{LineID: identifier("b"), Action: example.AuditCreate, Actor: "alice", RequestID: "r1", At: at, After: &example.Line{ID: identifier("b")}},
+	}
+
+	al := NewAuditLog()
+
+	require.NoError(t, al.Append(ctx, records))
+	require.NoError(t, al.Append(ctx, []example.AuditRecord{
+		{LineID: identifier("a"), Action: example.AuditDelete, Actor: "bob", RequestID: "r2", At: at.Add(time.Hour), Before: &created, After: &deleted},
+	}))
+
+	created.Data = "changed after the append"
+
+	history, err := al.History(ctx, identifier("a"))
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, example.AuditCreate, history[0].Action)
+	assert.Equal(t, "first line", history[0].After.Data)
+	assert.Equal(t, example.AuditDelete, history[1].Action)
+	assert.Equal(t, "bob", history[1].Actor)
+	assert.Equal(t, deleted.DeletedAt, history[1].After.DeletedAt)
+
+	history[0].After.Data = "changed after the read"
+
+	history, err = al.History(ctx, identifier("a"))
+	require.NoError(t, err)
+	assert.Equal(t, "first line", history[0].After.Data)
+
+	history, err = al.History(ctx, identifier("missing"))
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	assert.ErrorIs(t, al.Append(cancelled, records), ErrTimeOut)
+
+	_, err = al.History(cancelled, identifier("a"))
+	assert.ErrorIs(t, err, ErrTimeOut)
+
+	// A nil context stands for no deadline, as it does for the stores.
+	var none context.Context
+
+	require.NoError(t, al.Append(none, records[1:]))
+
+	history, err = al.History(none, identifier("b"))
+	require.NoError(t, err)
+	assert.Len(t, history, 2)
+}
@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const benchmarkLines = 10000
+
+func benchmarkStores(b *testing.B, bench func(b *testing.B, repo Repository)) {
+	stores := map[string]func() (Repository, func()){
+		ChannelImplementation: func() (Repository, func()) {
+			st := newRunningStore(time.Minute)
+			return st, st.stop
+		},
+		ShardedImplementation: func() (Repository, func()) {
+			return NewShardedRepo(config{}), func() {}
+		},
+	}
+
+	for _, name := range []string{ChannelImplementation, ShardedImplementation} {
+		build := stores[name]
+
+		b.Run(name, func(b *testing.B) {
+			repo, stop := build()
+			defer stop()
+
+			ctx := context.Background()
+			for i := 0; i < benchmarkLines; i++ {
+				if err := repo.Write(ctx, benchmarkLine(i)); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ResetTimer()
+			bench(b, repo)
+		})
+	}
+}
+
+func benchmarkLine(i int) example.Line {
+	return example.Line{
+		ID:      identifier(fmt.Sprintf("line-%06d", i%benchmarkLines)),
+		Created: time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC),
+		Data:    fmt.Sprintf("benchmark line %d", i),
+	}
+}
+
+func Benchmark_Read(b *testing.B) {
+	benchmarkStores(b, func(b *testing.B, repo Repository) {
+		var n atomic.Int64
+
+		b.RunParallel(func(pb *testing.PB) {
+			ctx := context.Background()
+			for pb.Next() {
+				if _, err := repo.Read(ctx, benchmarkLine(int(n.Add(1))).ID); err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	})
+}
+
+func Benchmark_Write(b *testing.B) {
+	benchmarkStores(b, func(b *testing.B, repo Repository) {
+		var n atomic.Int64
+
+		b.RunParallel(func(pb *testing.PB) {
+			ctx := context.Background()
+			for pb.Next() {
+				if err := repo.Write(ctx, benchmarkLine(int(n.Add(1)))); err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	})
+}
+
+// Benchmark_ReadMostly writes one request out of ten, the load of the load
+// tests.
+func Benchmark_ReadMostly(b *testing.B) {
+	benchmarkStores(b, func(b *testing.B, repo Repository) {
+		var n atomic.Int64
+
+		b.RunParallel(func(pb *testing.PB) {
+			ctx := context.Background()
+			for pb.Next() {
+				i := int(n.Add(1))
+
+				var err error
+				if i%10 == 0 {
+					err = repo.Write(ctx, benchmarkLine(i))
+				} else {
+					_, err = repo.Read(ctx, benchmarkLine(i).ID)
+				}
+
+				if err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	})
+}
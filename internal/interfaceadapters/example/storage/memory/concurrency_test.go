@@ -0,0 +1,313 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+func newRunningStore(timeout time.Duration) Store {
+	storeCtx, cancel := context.WithCancel(context.Background())
+
+	st := Store{
+		ctx:     storeCtx,
+		cancel:  cancel,
+		data:    make(map[identifier]example.Line),
+		request: make(chan request),
+		timeout: newOpTimeout(timeout),
+	}
+	st.start()
+
+	return st
+}
+
+// waitTimeout reports whether wg was done before d.
+func waitTimeout(wg *sync.WaitGroup, d time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+func Test_ConcurrentReadersAndWriters(t *testing.T) {
+	const (
+		writers = 2000
+		readers = 2000
+	)
+
+	ctx := context.Background()
+	st := newRunningStore(time.Minute)
+	defer st.stop()
+
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+	lineID := func(i int) identifier {
+		return identifier(fmt.Sprintf("line-%05d", i))
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			assert.NoError(t, st.Write(ctx, example.Line{ID: lineID(i), Created: tstamp, Data: fmt.Sprintf("line number %d", i)}))
+
+			if i%10 == 0 {
+				_, err := st.Delete(ctx, lineID(i), tstamp)
+				assert.NoError(t, err)
+			}
+		}(i)
+	}
+
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			// The requests walking every line are kept few, the point is
+			// them running among the others.
+			var err error
+			switch {
+			case i%50 == 0:
+				_, err = st.Search(ctx, example.SearchQuery{Text: "line", Limit: 10})
+			case i%50 == 1:
+				_, err = st.Find(ctx, example.LineSpecification{DataPrefix: "line", Limit: 10})
+			case i%50 == 2:
+				var cur example.LineCursor
+				if cur, err = st.Scan(ctx); err == nil {
+					for cur.Next(ctx) {
+					}
+					err = cur.Err()
+				}
+			case i%2 == 0:
+				_, err = st.Read(ctx, lineID(i))
+			default:
+				_, err = st.ReadMany(ctx, []example.Identifier{lineID(i), lineID(i + 1)})
+			}
+
+			assert.NoError(t, err)
+		}(i)
+	}
+
+	require.True(t, waitTimeout(&wg, time.Minute), "requests still blocked")
+
+	count, err := st.count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(writers), count)
+
+	for i := 0; i < writers; i++ {
+		l, err := st.Read(ctx, lineID(i))
+
+		require.NoError(t, err)
+		require.NotNil(t, l)
+		assert.Equal(t, i%10 == 0, l.Deleted())
+	}
+
+	purged, err := st.Purge(ctx, tstamp.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Len(t, purged, writers/10)
+}
+
+func Test_StopFailsRequestsInFlight(t *testing.T) {
+	const callers = 1000
+
+	ctx := context.Background()
+	st := newRunningStore(time.Minute)
+
+	started := make(chan struct{}, callers)
+	errs := make(chan error, callers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for n := 0; ; n++ {
+				id := identifier(fmt.Sprintf("line-%d-%d", i, n/2))
+
+				var err error
+				if n%2 == 0 {
+					err = st.Write(ctx, example.Line{ID: id, Data: "a line"})
+				} else {
+					_, err = st.Read(ctx, id)
+				}
+
+				if n == 0 {
+					started <- struct{}{}
+				}
+
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(i)
+	}
+
+	for i := 0; i < callers; i++ {
+		<-started
+	}
+
+	st.stop()
+
+	require.True(t, waitTimeout(&wg, 10*time.Second), "requests still blocked after stop")
+	close(errs)
+
+	for err := range errs {
+		assert.ErrorIs(t, err, ErrStopped)
+		assert.ErrorIs(t, err, example.ErrUnavailable)
+	}
+
+	_, err := st.Read(ctx, identifier("line-0-0"))
+	assert.ErrorIs(t, err, ErrStopped)
+}
+
+// Test_UnansweredRequests runs the requests against a loop that takes them
+// and never answers, they must give up with the caller or with the store.
+func Test_UnansweredRequests(t *testing.T) {
+	calls := map[string]func(ctx context.Context, st Store) error{
+		"write": func(ctx context.Context, st Store) error {
+			return st.Write(ctx, example.Line{ID: identifier("one")})
+		},
+		"read": func(ctx context.Context, st Store) error {
+			_, err := st.Read(ctx, identifier("one"))
+			return err
+		},
+		"write-many": func(ctx context.Context, st Store) error {
+			return st.WriteMany(ctx, []example.Line{{ID: identifier("one")}})[0]
+		},
+		"read-many": func(ctx context.Context, st Store) error {
+			_, err := st.ReadMany(ctx, []example.Identifier{identifier("one")})
+			return err
+		},
+		"scan": func(ctx context.Context, st Store) error {
+			_, err := st.Scan(ctx)
+			return err
+		},
+		"search": func(ctx context.Context, st Store) error {
+			_, err := st.Search(ctx, example.SearchQuery{Text: "line"})
+			return err
+		},
+		"find": func(ctx context.Context, st Store) error {
+			_, err := st.Find(ctx, example.LineSpecification{})
+			return err
+		},
+		"delete": func(ctx context.Context, st Store) error {
+			_, err := st.Delete(ctx, identifier("one"), time.Now())
+			return err
+		},
+		"restore": func(ctx context.Context, st Store) error {
+			_, err := st.Restore(ctx, identifier("one"))
+			return err
+		},
+		"purge": func(ctx context.Context, st Store) error {
+			_, err := st.Purge(ctx, time.Now())
+			return err
+		},
+		"count": func(ctx context.Context, st Store) error {
+			_, err := st.count(ctx)
+			return err
+		},
+		"check": func(ctx context.Context, st Store) error {
+			return st.Check(ctx)
+		},
+	}
+
+	for name, call := range calls {
+		call := call
+
+		t.Run(name+"-caller-gives-up-case", func(t *testing.T) {
+			st := newSilentStore(time.Minute)
+			defer st.stop()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			assert.ErrorIs(t, call(ctx, st.Store), ErrTimeOut)
+		})
+
+		t.Run(name+"-store-stops-case", func(t *testing.T) {
+			st := newSilentStore(time.Minute)
+
+			errc := make(chan error, 1)
+			go func() {
+				errc <- call(context.Background(), st.Store)
+			}()
+
+			<-st.received
+			st.stop()
+
+			select {
+			case err := <-errc:
+				assert.ErrorIs(t, err, ErrStopped)
+			case <-time.After(10 * time.Second):
+				t.Fatal("request still blocked after stop")
+			}
+		})
+	}
+}
+
+type silentStore struct {
+	Store
+	received chan struct{}
+}
+
+func newSilentStore(timeout time.Duration) silentStore {
+	storeCtx, cancel := context.WithCancel(context.Background())
+
+	st := silentStore{
+		Store: Store{
+			ctx:     storeCtx,
+			cancel:  cancel,
+			data:    make(map[identifier]example.Line),
+			request: make(chan request),
+			timeout: newOpTimeout(timeout),
+		},
+		received: make(chan struct{}, 1),
+	}
+
+	go func() {
+		for {
+			select {
+			case <-storeCtx.Done():
+				return
+			case <-st.request:
+				st.received <- struct{}{}
+			}
+		}
+	}()
+
+	return st
+}
+
+func Test_CanceledCallerNeverReachesTheLoop(t *testing.T) {
+	st := newSilentStore(time.Minute)
+	defer st.stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := st.Write(ctx, example.Line{ID: identifier("one")})
+
+	assert.ErrorIs(t, err, ErrTimeOut)
+	assert.Empty(t, st.received)
+}
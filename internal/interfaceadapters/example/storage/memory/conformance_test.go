@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+	"clean-arquitecture-template/internal/interfaceadapters/example/storage/repotest"
+)
+
+func Test_Conformance(t *testing.T) {
+	factories := map[string]repotest.Factory{
+		ChannelImplementation: func(t *testing.T) (example.LineRepository, example.IdentityProvider) {
+			st := newRunningStore(time.Minute)
+			t.Cleanup(st.stop)
+
+			return st, NewIdentityProvider()
+		},
+		ShardedImplementation: func(t *testing.T) (example.LineRepository, example.IdentityProvider) {
+			return NewShardedRepo(config{ShardsNum: 4}), NewIdentityProvider()
+		},
+	}
+
+	for name, factory := range factories {
+		factory := factory
+
+		t.Run(name, func(t *testing.T) {
+			repotest.Run(t, factory)
+		})
+	}
+}
+
+func Test_ConformanceWithIdentities(t *testing.T) {
+	newRepos := map[string]func(t *testing.T) example.LineRepository{
+		ChannelImplementation: func(t *testing.T) example.LineRepository {
+			st := newRunningStore(time.Minute)
+			t.Cleanup(st.stop)
+
+			return st
+		},
+		ShardedImplementation: func(t *testing.T) example.LineRepository {
+			return NewShardedRepo(config{ShardsNum: 4})
+		},
+	}
+
+	for name, newRepo := range newRepos {
+		newRepo := newRepo
+
+		t.Run(name, func(t *testing.T) {
+			repotest.RunWithIdentities(t, newRepo)
+		})
+	}
+}
@@ -3,7 +3,13 @@ package memory
 import (
 	"clean-arquitecture-template/internal/domain/example"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,10 +19,36 @@ const (
 	writeRequest requestType = iota
 	readRequest
 	countRequest
+	pingRequest
+	writeManyRequest
+	readManyRequest
+	scanRequest
+	searchRequest
+	findRequest
+	deleteRequest
+	restoreRequest
+	purgeRequest
 
-	timeLayout string = "2006-01-02 15:04:05"
+	healthCheckName string = "memory"
 
-	ErrTimeOut Err = "data store timeout"
+	defaultTimeout time.Duration = time.Second
+
+	scanPageSize int = 100
+
+	checkInterval time.Duration = time.Millisecond
+
+	ConfigNode string = "apps.example.interface-adapters.storage.memory"
+
+	ChannelImplementation string = "channel"
+	ShardedImplementation string = "sharded"
+
+	defaultShards int = 32
+
+	ErrTimeOut    Err = "data store timeout"
+	ErrStopped    Err = "data store stopped"
+	ErrReadConfig Err = "unable to read config"
+	ErrIdentifier Err = "invalid memory identifier"
+	ErrExists     Err = "line already in data store"
 )
 
 var (
@@ -30,18 +62,156 @@ func (e Err) Error() string {
 	return string(e)
 }
 
+// Is reports the store timeouts as the domain timeout, a stopped store as
+// unavailable and a taken identifier as a duplicate, so the application
+// services can tell them apart from other failures.
+func (e Err) Is(target error) bool {
+	switch e {
+	case ErrTimeOut:
+		return target == example.ErrTimeout
+	case ErrStopped:
+		return target == example.ErrUnavailable
+	case ErrExists:
+		return target == example.ErrDuplicate
+	default:
+		return false
+	}
+}
+
+type Config interface {
+	Timeout() time.Duration
+	Implementation() string
+	Shards() int
+}
+
+type config struct {
+	OpTimeout string `json:"timeout"`
+	Impl      string `json:"implementation"`
+	ShardsNum int    `json:"shards"`
+
+	timeout time.Duration
+}
+
+func (c config) Timeout() time.Duration {
+	return c.timeout
+}
+
+// Implementation is either the channel store, the default, or the sharded
+// one.
+func (c config) Implementation() string {
+	if c.Impl == "" {
+		return ChannelImplementation
+	}
+
+	return c.Impl
+}
+
+func (c config) Shards() int {
+	if c.ShardsNum == 0 {
+		return defaultShards
+	}
+
+	return c.ShardsNum
+}
+
+type ConfigReader interface {
+	Find(node string) (io.Reader, error)
+}
+
+func ReadConfig(cfnReader ConfigReader) (Config, error) {
+	reader, err := cfnReader.Find(ConfigNode)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	cnf, err := parseConfig(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return cnf, nil
+}
+
+func parseConfig(reader io.Reader) (config, error) {
+	d, err := io.ReadAll(reader)
+	if err != nil {
+		return config{}, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	cnf := config{
+		timeout: defaultTimeout,
+	}
+	if err = json.Unmarshal(d, &cnf); err != nil {
+		return config{}, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	if cnf.OpTimeout != "" {
+		if cnf.timeout, err = time.ParseDuration(cnf.OpTimeout); err != nil {
+			return config{}, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+		}
+	}
+
+	if impl := cnf.Implementation(); impl != ChannelImplementation && impl != ShardedImplementation {
+		return config{}, fmt.Errorf("implementation %q: %w", impl, ErrReadConfig)
+	}
+
+	if cnf.ShardsNum < 0 {
+		return config{}, fmt.Errorf("shards %d: %w", cnf.ShardsNum, ErrReadConfig)
+	}
+
+	return cnf, nil
+}
+
+// Repository is the memory store, whichever its implementation.
+type Repository interface {
+	example.LineRepository
+	example.Searcher
+	Name() string
+	Check(context.Context) error
+	ApplyConfig(io.Reader) error
+}
+
+// New builds the store implementation chosen in cnf.
+func New(ctx context.Context, cnf Config) Repository {
+	if cnf.Implementation() == ShardedImplementation {
+		return NewShardedRepo(cnf)
+	}
+
+	return NewExampleRepo(ctx, cnf)
+}
+
 type requestType int
 
 func (rt requestType) String() string {
-	return []string{"write", "read", "count"}[rt]
+	return []string{"write", "read", "count", "ping", "write-many", "read-many", "scan", "search", "find", "delete", "restore", "purge"}[rt]
 }
 
+// request is served by the store loop, which answers on reply. The reply is
+// buffered, so the loop never waits for a caller that gave up.
 type request struct {
 	requestType requestType
 	id          identifier
-	input       line
-	output      chan *example.Line
-	count       chan *int64
+	input       example.Line
+	ids         []identifier
+	inputs      []example.Line
+	query       example.SearchQuery
+	spec        example.LineSpecification
+	at          time.Time
+	reply       chan response
+}
+
+// response holds the outcome of any request type, only the fields of the
+// type served are set.
+type response struct {
+	line    *example.Line
+	lines   []*example.Line
+	keys    []identifier
+	result  example.SearchResult
+	matches []example.Line
+	found   bool
+	count   int64
+	errs    []error
+	purged  []example.Line
 }
 
 type identifier string
@@ -54,27 +224,71 @@ func (id identifier) String() string {
 	return string(id)
 }
 
-type line struct {
-	createdAT string
-	data      string
+// IdentityProvider hands out the identifiers of NewID and only parses those.
+type IdentityProvider struct{}
+
+func NewIdentityProvider() IdentityProvider {
+	return IdentityProvider{}
+}
+
+func (IdentityProvider) NewID() example.Identifier {
+	return NewID()
+}
+
+func (IdentityProvider) ParseID(key string) (example.Identifier, error) {
+	id, err := uuid.Parse(key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrIdentifier)
+	}
+
+	return identifier(id.String()), nil
+}
+
+// newLine keeps the line as given, times included to the nanosecond and in
+// their own location, under the identifier of the store.
+func newLine(id identifier, input example.Line) example.Line {
+	input.ID = id
+
+	return input
 }
 
 type Store struct {
-	ctx            context.Context
-	cancel         context.CancelFunc
-	data           map[identifier]line
-	request        chan request
-	timeoutSeconds int
+	ctx     context.Context
+	cancel  context.CancelFunc
+	data    map[identifier]example.Line
+	request chan request
+	timeout *opTimeout
+}
+
+// opTimeout is shared by the copies of a Store, so the timeout of a config
+// reload reaches all of them.
+type opTimeout struct {
+	d atomic.Int64
 }
 
-func NewExampleRepo(ctx context.Context) Store {
+func newOpTimeout(d time.Duration) *opTimeout {
+	t := &opTimeout{}
+	t.d.Store(int64(d))
+
+	return t
+}
+
+func (t *opTimeout) get() time.Duration {
+	return time.Duration(t.d.Load())
+}
+
+func (t *opTimeout) set(d time.Duration) {
+	t.d.Store(int64(d))
+}
+
+func NewExampleRepo(ctx context.Context, cnf Config) Store {
 	storageOnce.Do(func() {
 		dbCtx, dbCancel := context.WithCancel(ctx)
 		storage.ctx = dbCtx
 		storage.cancel = dbCancel
-		storage.data = make(map[identifier]line)
+		storage.data = make(map[identifier]example.Line)
 		storage.request = make(chan request)
-		storage.timeoutSeconds = 1
+		storage.timeout = newOpTimeout(cnf.Timeout())
 
 		storage.start()
 	})
@@ -86,22 +300,110 @@ func (s Store) start() {
 	go s.run()
 }
 
+// run serves the requests one at a time. The search index belongs to the
+// loop, so it is kept in step with the data without any locking.
 func (s Store) run() {
+	p := newPartition(s.data)
+
 	for {
 		select {
 		case <-s.ctx.Done():
 			return
 		case req := <-s.request:
-			switch req.requestType {
-			case writeRequest:
-				s.data[req.id] = req.input
-			case readRequest:
-				req.output <- findLine(s.data, req.id)
-			case countRequest:
-				req.count <- count(s.data)
-			}
+			req.reply <- serve(p, req)
+		}
+	}
+}
+
+func serve(p partition, req request) response {
+	switch req.requestType {
+	case writeRequest:
+		return response{errs: []error{p.insert(req.id, req.input)}}
+	case readRequest:
+		return response{line: findLine(p.data, req.id)}
+	case countRequest:
+		return response{count: int64(len(p.data))}
+	case writeManyRequest:
+		errs := make([]error, len(req.ids))
+		for i, id := range req.ids {
+			errs[i] = p.insert(id, req.inputs[i])
 		}
+
+		return response{errs: errs}
+	case readManyRequest:
+		return response{lines: findLines(p.data, req.ids)}
+	case scanRequest:
+		return response{keys: sortKeys(p.keys())}
+	case searchRequest:
+		return response{result: search(req.query, p)}
+	case findRequest:
+		return response{matches: find(req.spec, p)}
+	case deleteRequest:
+		return response{found: p.delete(req.id, req.at)}
+	case restoreRequest:
+		return response{found: p.restore(req.id)}
+	case purgeRequest:
+		return response{purged: p.purge(req.at)}
 	}
+
+	return response{}
+}
+
+// do hands req to the store loop and waits for its response. It gives up
+// with ErrTimeOut once ctx is done, and fails with ErrStopped when the store
+// stops before answering.
+func (s Store) do(ctx context.Context, req request) (response, error) {
+	req.reply = make(chan response, 1)
+
+	if err := ctx.Err(); err != nil {
+		return response{}, ErrTimeOut
+	}
+
+	select {
+	case <-ctx.Done():
+		return response{}, ErrTimeOut
+	case <-s.ctx.Done():
+		return response{}, ErrStopped
+	case s.request <- req:
+	}
+
+	select {
+	case resp := <-req.reply:
+		return resp, nil
+	case <-ctx.Done():
+		return response{}, ErrTimeOut
+	case <-s.ctx.Done():
+		// The loop may have answered right before stopping.
+		select {
+		case resp := <-req.reply:
+			return resp, nil
+		default:
+			return response{}, ErrStopped
+		}
+	}
+}
+
+// withTimeout bounds ctx by the store timeout, a nil ctx standing for the
+// store context.
+func (s Store) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = s.ctx
+	}
+
+	return context.WithTimeout(ctx, s.timeout.get())
+}
+
+// ApplyConfig validates the memory config read from r and applies its
+// timeout, the rest of it is only read on start.
+func (s Store) ApplyConfig(r io.Reader) error {
+	cnf, err := parseConfig(r)
+	if err != nil {
+		return err
+	}
+
+	s.timeout.set(cnf.Timeout())
+
+	return nil
 }
 
 func (s Store) stop() {
@@ -109,99 +411,464 @@ func (s Store) stop() {
 }
 
 func (s Store) Write(ctx context.Context, n example.Line) error {
-	var cancel context.CancelFunc
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 
-	if ctx == nil {
-		ctx, cancel = context.WithTimeout(s.ctx, time.Duration(s.timeoutSeconds)*time.Second)
-		defer cancel()
+	id := identifier(n.ID.String())
+	resp, err := s.do(ctx, request{
+		requestType: writeRequest,
+		id:          id,
+		input:       newLine(id, n),
+	})
+	if err != nil {
+		return err
 	}
 
-	return s.write(ctx, n)
+	return resp.errs[0]
+}
+
+func (s Store) Read(ctx context.Context, id example.Identifier) (*example.Line, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := s.do(ctx, request{
+		requestType: readRequest,
+		id:          identifier(id.String()),
+	})
+
+	return resp.line, err
 }
 
-func (s Store) write(ctx context.Context, input example.Line) error {
+// WriteMany stores every line with a single message to the store loop, so
+// the lines are written together or not at all. Only the lines whose
+// identifier is taken fail on their own.
+func (s Store) WriteMany(ctx context.Context, lines []example.Line) []error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
 	req := request{
-		requestType: writeRequest,
-		id:          identifier(input.ID.String()),
-		input: line{
-			createdAT: input.Created.Format(timeLayout),
-			data:      input.Data,
-		},
+		requestType: writeManyRequest,
+		ids:         make([]identifier, len(lines)),
+		inputs:      make([]example.Line, len(lines)),
 	}
 
-	if err := ctx.Err(); err != nil {
-		return ErrTimeOut
+	for i, input := range lines {
+		req.ids[i] = identifier(input.ID.String())
+		req.inputs[i] = newLine(req.ids[i], input)
 	}
 
-	select {
-	case <-ctx.Done():
-		return ErrTimeOut
-	case s.request <- req:
-		return nil
+	resp, err := s.do(ctx, req)
+	if err != nil {
+		errs := make([]error, len(lines))
+		for i := range errs {
+			errs[i] = err
+		}
+
+		return errs
 	}
+
+	return resp.errs
 }
 
-func (s Store) Read(ctx context.Context, id example.Identifier) (*example.Line, error) {
-	var cancel context.CancelFunc
+func (s Store) ReadMany(ctx context.Context, ids []example.Identifier) ([]*example.Line, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 
-	if ctx == nil {
-		ctx, cancel = context.WithTimeout(s.ctx, time.Duration(s.timeoutSeconds)*time.Second)
-		defer cancel()
+	keys := make([]identifier, len(ids))
+	for i, id := range ids {
+		keys[i] = identifier(id.String())
 	}
 
-	return s.read(ctx, identifier(id.String()))
+	return s.readMany(ctx, keys)
 }
 
-func (s Store) read(ctx context.Context, id identifier) (*example.Line, error) {
-	req := request{
-		requestType: readRequest,
-		id:          id,
-		output:      make(chan *example.Line),
+func (s Store) readMany(ctx context.Context, ids []identifier) ([]*example.Line, error) {
+	resp, err := s.do(ctx, request{
+		requestType: readManyRequest,
+		ids:         ids,
+	})
+
+	return resp.lines, err
+}
+
+func findLines(data map[identifier]example.Line, ids []identifier) []*example.Line {
+	lines := make([]*example.Line, len(ids))
+	for i, id := range ids {
+		lines[i] = findLine(data, id)
 	}
 
-	select {
-	case <-ctx.Done():
-		return nil, ErrTimeOut
-	case s.request <- req:
-		return <-req.output, nil
+	return lines
+}
+
+// Scan takes a snapshot of the identifiers only, the lines are read a page
+// at a time as the cursor moves. Lines removed meanwhile are skipped.
+func (s Store) Scan(ctx context.Context) (example.LineCursor, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := s.do(ctx, request{requestType: scanRequest})
+	if err != nil {
+		return nil, err
 	}
+
+	return &cursor{ids: resp.keys, read: s.readPage}, nil
 }
 
-func findLine(data map[identifier]line, itemID identifier) *example.Line {
-	if item, exists := data[itemID]; exists {
-		createdAT, _ := time.Parse(timeLayout, item.createdAT)
-		return &example.Line{
-			ID:      itemID,
-			Created: createdAT,
-			Data:    item.data,
+func (s Store) readPage(ctx context.Context, ids []identifier) ([]*example.Line, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	return s.readMany(ctx, ids)
+}
+
+func sortKeys(ids []identifier) []identifier {
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	return ids
+}
+
+// cursor reads the lines of ids a page at a time with read.
+type cursor struct {
+	ids     []identifier
+	read    func(context.Context, []identifier) ([]*example.Line, error)
+	page    []*example.Line
+	current example.Line
+	err     error
+}
+
+func (c *cursor) Next(ctx context.Context) bool {
+	for {
+		for len(c.page) > 0 {
+			next := c.page[0]
+			c.page = c.page[1:]
+
+			if next != nil {
+				c.current = *next
+				return true
+			}
+		}
+
+		if c.err != nil || len(c.ids) == 0 {
+			return false
+		}
+
+		size := scanPageSize
+		if len(c.ids) < size {
+			size = len(c.ids)
 		}
+
+		c.page, c.err = c.read(ctx, c.ids[:size])
+		c.ids = c.ids[size:]
 	}
+}
+
+func (c *cursor) Line() example.Line {
+	return c.current
+}
+
+func (c *cursor) Err() error {
+	return c.err
+}
+
+func (c *cursor) Close(context.Context) error {
+	c.ids = nil
+	c.page = nil
+
 	return nil
 }
 
-func (s Store) count() *int64 {
-	ctx, cancel := context.WithTimeout(s.ctx, time.Duration(s.timeoutSeconds))
+// Search ranks the lines by the tf-idf of the query terms they contain, ties
+// in identifier order so the pages stay stable.
+func (s Store) Search(ctx context.Context, query example.SearchQuery) (example.SearchResult, error) {
+	ctx, cancel := s.withTimeout(ctx)
 	defer cancel()
 
-	req := request{
-		requestType: countRequest,
-		count:       make(chan *int64),
+	resp, err := s.do(ctx, request{
+		requestType: searchRequest,
+		query:       query,
+	})
+
+	return resp.result, err
+}
+
+// Delete marks the line as deleted, keeping the time of an earlier deletion.
+func (s Store) Delete(ctx context.Context, id example.Identifier, at time.Time) (bool, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := s.do(ctx, request{
+		requestType: deleteRequest,
+		id:          identifier(id.String()),
+		at:          at,
+	})
+
+	return resp.found, err
+}
+
+func (s Store) Restore(ctx context.Context, id example.Identifier) (bool, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := s.do(ctx, request{
+		requestType: restoreRequest,
+		id:          identifier(id.String()),
+	})
+
+	return resp.found, err
+}
+
+// Purge removes the lines deleted before the given time, from the data and
+// from the search index.
+func (s Store) Purge(ctx context.Context, before time.Time) ([]example.Line, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := s.do(ctx, request{
+		requestType: purgeRequest,
+		at:          before,
+	})
+
+	return resp.purged, err
+}
+
+// Find evaluates the specification against every line inside the store loop.
+func (s Store) Find(ctx context.Context, spec example.LineSpecification) ([]example.Line, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := s.do(ctx, request{
+		requestType: findRequest,
+		spec:        spec,
+	})
+
+	return resp.matches, err
+}
+
+// partition is a part of the lines with the search index of its own lines.
+type partition struct {
+	data  map[identifier]example.Line
+	index invertedIndex
+}
+
+func newPartition(data map[identifier]example.Line) partition {
+	return partition{data: data, index: newInvertedIndex(data)}
+}
+
+// insert stores a new line, failing with ErrExists for a taken identifier
+// as the identifiers are never written twice.
+func (p partition) insert(id identifier, input example.Line) error {
+	if _, exists := p.data[id]; exists {
+		return fmt.Errorf("line %q: %w", id, ErrExists)
 	}
 
-	select {
-	case <-ctx.Done():
-		return nil
-	case s.request <- req:
+	p.data[id] = input
+	p.index.add(id, input.Data)
+
+	return nil
+}
+
+func (p partition) delete(id identifier, at time.Time) bool {
+	l, exists := p.data[id]
+	if !exists {
+		return false
 	}
 
-	count := <-req.count
+	if !l.Deleted() {
+		l.DeletedAt = at
+		p.data[id] = l
+	}
+
+	return true
+}
+
+func (p partition) restore(id identifier) bool {
+	l, exists := p.data[id]
+	if !exists {
+		return false
+	}
+
+	l.DeletedAt = time.Time{}
+	p.data[id] = l
+
+	return true
+}
+
+func (p partition) purge(before time.Time) []example.Line {
+	var purged []example.Line
+	for id := range p.data {
+		if l := p.data[id]; l.Deleted() && l.DeletedAt.Before(before) {
+			p.index.remove(id, l.Data)
+			delete(p.data, id)
+			purged = append(purged, l)
+		}
+	}
+
+	return purged
+}
+
+func (p partition) keys() []identifier {
+	ids := make([]identifier, 0, len(p.data))
+	for id := range p.data {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func find(spec example.LineSpecification, parts ...partition) []example.Line {
+	lines := make([]example.Line, 0)
+	for _, p := range parts {
+		for id := range p.data {
+			if line := findLine(p.data, id); spec.IsSatisfiedBy(*line) {
+				lines = append(lines, *line)
+			}
+		}
+	}
+
+	sort.Slice(lines, func(i, j int) bool {
+		return spec.Less(lines[i], lines[j])
+	})
+
+	if spec.Limit > 0 && spec.Limit < len(lines) {
+		lines = lines[:spec.Limit]
+	}
+
+	return lines
+}
+
+// invertedIndex maps every term to the lines containing it, with the number
+// of times it appears in each of them.
+type invertedIndex map[string]map[identifier]int
+
+func newInvertedIndex(data map[identifier]example.Line) invertedIndex {
+	index := make(invertedIndex)
+	for id, l := range data {
+		index.add(id, l.Data)
+	}
+
+	return index
+}
+
+func (ii invertedIndex) add(id identifier, data string) {
+	for _, token := range example.Tokenize(data) {
+		postings, exists := ii[token.Term]
+		if !exists {
+			postings = make(map[identifier]int)
+			ii[token.Term] = postings
+		}
+
+		postings[id]++
+	}
+}
+
+func (ii invertedIndex) remove(id identifier, data string) {
+	for _, term := range example.Terms(data) {
+		delete(ii[term], id)
+		if len(ii[term]) == 0 {
+			delete(ii, term)
+		}
+	}
+}
+
+// search scores the lines of every part against the document frequencies of
+// all of them together, so the ranking does not depend on how the lines are
+// split.
+func search(query example.SearchQuery, parts ...partition) example.SearchResult {
+	total := 0
+	for _, p := range parts {
+		total += len(p.data)
+	}
+
+	scores := make(map[identifier]float64)
+	owners := make(map[identifier]partition)
+	for _, term := range example.Terms(query.Text) {
+		frequency := 0
+		for _, p := range parts {
+			frequency += len(p.index[term])
+		}
+
+		if frequency == 0 {
+			continue
+		}
+
+		idf := math.Log(1 + float64(total)/float64(frequency))
+		for _, p := range parts {
+			for id, occurrences := range p.index[term] {
+				if !p.data[id].Deleted() || query.IncludeDeleted {
+					scores[id] += float64(occurrences) * idf
+					owners[id] = p
+				}
+			}
+		}
+	}
+
+	phrases := example.Phrases(query.Text)
+
+	ids := make([]identifier, 0, len(scores))
+	for id := range scores {
+		if len(phrases) > 0 && !example.ContainsPhrases(example.Tokenize(owners[id].data[id].Data), phrases) {
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+
+		return ids[i] < ids[j]
+	})
+
+	result := example.SearchResult{Total: int64(len(ids))}
+
+	if query.Offset >= len(ids) {
+		return result
+	}
+
+	ids = ids[query.Offset:]
+	if query.Limit > 0 && query.Limit < len(ids) {
+		ids = ids[:query.Limit]
+	}
+
+	result.Hits = make([]example.SearchHit, len(ids))
+	for i, id := range ids {
+		result.Hits[i] = example.SearchHit{
+			Line:  *findLine(owners[id].data, id),
+			Score: scores[id],
+		}
+	}
+
+	return result
+}
+
+func findLine(data map[identifier]example.Line, itemID identifier) *example.Line {
+	if item, exists := data[itemID]; exists {
+		return &item
+	}
+	return nil
+}
+
+func (s Store) count(ctx context.Context) (int64, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := s.do(ctx, request{requestType: countRequest})
+
+	return resp.count, err
+}
 
-	return count
+func (s Store) Name() string {
+	return healthCheckName
 }
 
-func count(data map[identifier]line) *int64 {
-	count := new(int64)
-	*count = int64(len(data))
+// Check makes a round trip through the store loop, so a stuck or stopped
+// loop is reported as unhealthy instead of blocking the caller.
+func (s Store) Check(ctx context.Context) error {
+	_, err := s.do(ctx, request{requestType: pingRequest})
 
-	return count
+	return err
 }
@@ -3,16 +3,146 @@ package memory
 import (
 	"clean-arquitecture-template/internal/domain/example"
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_Error(t *testing.T) {
 	err := ErrTimeOut
 
 	assert.Equal(t, "data store timeout", err.Error())
+	assert.ErrorIs(t, err, example.ErrTimeout)
+	assert.NotErrorIs(t, ErrReadConfig, example.ErrTimeout)
+	assert.ErrorIs(t, ErrStopped, example.ErrUnavailable)
+	assert.NotErrorIs(t, ErrStopped, example.ErrTimeout)
+}
+
+type configReaderMock struct {
+	f func(node string) (io.Reader, error)
+}
+
+func (crm configReaderMock) Find(node string) (io.Reader, error) {
+	return crm.f(node)
+}
+
+type readerMock struct {
+	err error
+}
+
+func (rm readerMock) Read(p []byte) (n int, err error) {
+	return 0, rm.err
+}
+
+func Test_ReadConfig(t *testing.T) {
+	testCases := []struct {
+		testName          string
+		buildConfigReader func(node string) (io.Reader, error)
+		expectedTimeout   time.Duration
+		expectedImpl      string
+		expectedShards    int
+		expectedError     error
+	}{
+		{
+			testName: "config-read-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return nil, errors.New("reader error")
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "config-reader-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return readerMock{err: errors.New("read error")}, nil
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "config-unmarshal-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader("{"), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "invalid-timeout-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"timeout": "one second"}`), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "default-timeout-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{}`), nil
+			},
+			expectedTimeout: defaultTimeout,
+			expectedImpl:    ChannelImplementation,
+			expectedShards:  defaultShards,
+		},
+		{
+			testName: "success-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"timeout": "250ms"}`), nil
+			},
+			expectedTimeout: 250 * time.Millisecond,
+			expectedImpl:    ChannelImplementation,
+			expectedShards:  defaultShards,
+		},
+		{
+			testName: "sharded-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"implementation": "sharded", "shards": 8}`), nil
+			},
+			expectedTimeout: defaultTimeout,
+			expectedImpl:    ShardedImplementation,
+			expectedShards:  8,
+		},
+		{
+			testName: "unknown-implementation-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"implementation": "disk"}`), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "negative-shards-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"implementation": "sharded", "shards": -1}`), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+	}
+
+	for _, c := range testCases {
+		name := c.testName
+		expectedTimeout := c.expectedTimeout
+		expectedImpl := c.expectedImpl
+		expectedShards := c.expectedShards
+		expectedError := c.expectedError
+		readerMock := configReaderMock{
+			c.buildConfigReader,
+		}
+
+		t.Run(name, func(t *testing.T) {
+			cnf, err := ReadConfig(readerMock)
+
+			if cnf == nil {
+				assert.ErrorIs(t, err, expectedError)
+			} else {
+				assert.Equal(t, expectedTimeout, cnf.Timeout())
+				assert.Equal(t, expectedImpl, cnf.Implementation())
+				assert.Equal(t, expectedShards, cnf.Shards())
+				assert.NoError(t, err)
+			}
+		})
+	}
 }
 
 func Test_NewID(t *testing.T) {
@@ -26,14 +156,57 @@ func Test_NewID(t *testing.T) {
 }
 
 func Test_New(t *testing.T) {
-	st := NewExampleRepo(context.Background())
+	st := NewExampleRepo(context.Background(), config{timeout: time.Second})
 
 	st.stop()
 
 	assert.NotNil(t, st.request)
 	assert.NotNil(t, st.data)
 	assert.NotNil(t, st.cancel)
-	assert.Equal(t, 1, st.timeoutSeconds)
+	assert.Equal(t, time.Second, st.timeout.get())
+}
+
+func Test_ApplyConfig(t *testing.T) {
+	testCases := []struct {
+		testName        string
+		content         string
+		expectedTimeout time.Duration
+		expectedError   error
+	}{
+		{
+			testName:        "timeout-case",
+			content:         `{"timeout": "250ms"}`,
+			expectedTimeout: 250 * time.Millisecond,
+		},
+		{
+			testName:        "default-timeout-case",
+			content:         `{}`,
+			expectedTimeout: defaultTimeout,
+		},
+		{
+			testName:        "invalid-timeout-case",
+			content:         `{"timeout": "one second"}`,
+			expectedTimeout: time.Minute,
+			expectedError:   ErrReadConfig,
+		},
+	}
+
+	for _, c := range testCases {
+		content := c.content
+		expectedTimeout := c.expectedTimeout
+		expectedError := c.expectedError
+
+		t.Run(c.testName, func(t *testing.T) {
+			st := Store{timeout: newOpTimeout(time.Minute)}
+			handlers := st
+
+			err := st.ApplyConfig(strings.NewReader(content))
+
+			assert.ErrorIs(t, err, expectedError)
+			assert.Equal(t, expectedTimeout, handlers.timeout.get())
+			assert.ErrorIs(t, NewShardedRepo(config{}).ApplyConfig(strings.NewReader(content)), expectedError)
+		})
+	}
 }
 
 func Test_RequestType(t *testing.T) {
@@ -57,6 +230,46 @@ func Test_RequestType(t *testing.T) {
 			rtype:        countRequest,
 			expectedName: "count",
 		},
+		{
+			name:         "ping-requesttype-case",
+			rtype:        pingRequest,
+			expectedName: "ping",
+		},
+		{
+			name:         "write-many-requesttype-case",
+			rtype:        writeManyRequest,
+			expectedName: "write-many",
+		},
+		{
+			name:         "read-many-requesttype-case",
+			rtype:        readManyRequest,
+			expectedName: "read-many",
+		},
+		{
+			name:         "scan-requesttype-case",
+			rtype:        scanRequest,
+			expectedName: "scan",
+		},
+		{
+			name:         "search-requesttype-case",
+			rtype:        searchRequest,
+			expectedName: "search",
+		},
+		{
+			name:         "delete-requesttype-case",
+			rtype:        deleteRequest,
+			expectedName: "delete",
+		},
+		{
+			name:         "restore-requesttype-case",
+			rtype:        restoreRequest,
+			expectedName: "restore",
+		},
+		{
+			name:         "purge-requesttype-case",
+			rtype:        purgeRequest,
+			expectedName: "purge",
+		},
 	}
 
 	for _, c := range testCase {
@@ -78,7 +291,7 @@ func Test_Write(t *testing.T) {
 		ctx            context.Context
 		dbtimeOut      int
 		input          []example.Line
-		expectedOutput map[identifier]line
+		expectedOutput map[identifier]example.Line
 		expectedError  error
 	}{
 
@@ -93,11 +306,8 @@ func Test_Write(t *testing.T) {
 					Data:    "first-line",
 				},
 			},
-			expectedOutput: map[identifier]line{
-				"one": {
-					createdAT: tstamp.Format(timeLayout),
-					data:      "first-line",
-				},
+			expectedOutput: map[identifier]example.Line{
+				"one": {ID: identifier("one"), Created: tstamp, Data: "first-line"},
 			},
 			expectedError: nil,
 		},
@@ -112,11 +322,8 @@ func Test_Write(t *testing.T) {
 					Data:    "first-line",
 				},
 			},
-			expectedOutput: map[identifier]line{
-				"one": {
-					createdAT: tstamp.Format(timeLayout),
-					data:      "first-line",
-				},
+			expectedOutput: map[identifier]example.Line{
+				"one": {ID: identifier("one"), Created: tstamp, Data: "first-line"},
 			},
 			expectedError: nil,
 		},
@@ -141,19 +348,10 @@ func Test_Write(t *testing.T) {
 					Data:    "third-line",
 				},
 			},
-			expectedOutput: map[identifier]line{
-				"one": {
-					createdAT: tstamp.Format(timeLayout),
-					data:      "first-line",
-				},
-				"two": {
-					createdAT: tstamp.Format(timeLayout),
-					data:      "second-line",
-				},
-				"three": {
-					createdAT: tstamp.Format(timeLayout),
-					data:      "third-line",
-				},
+			expectedOutput: map[identifier]example.Line{
+				"one":   {ID: identifier("one"), Created: tstamp, Data: "first-line"},
+				"two":   {ID: identifier("two"), Created: tstamp, Data: "second-line"},
+				"three": {ID: identifier("three"), Created: tstamp, Data: "third-line"},
 			},
 			expectedError: nil,
 		},
@@ -169,7 +367,7 @@ func Test_Write(t *testing.T) {
 				},
 			},
 			expectedError:  ErrTimeOut,
-			expectedOutput: map[identifier]line{},
+			expectedOutput: map[identifier]example.Line{},
 		},
 	}
 
@@ -184,11 +382,11 @@ func Test_Write(t *testing.T) {
 		}
 
 		st := Store{
-			ctx:            storageCtx,
-			cancel:         cancel,
-			data:           make(map[identifier]line),
-			request:        make(chan request),
-			timeoutSeconds: c.dbtimeOut,
+			ctx:     storageCtx,
+			cancel:  cancel,
+			data:    make(map[identifier]example.Line),
+			request: make(chan request),
+			timeout: newOpTimeout(time.Duration(c.dbtimeOut) * time.Second),
 		}
 
 		input := c.input
@@ -224,13 +422,11 @@ func Test_Write(t *testing.T) {
 				}
 			}
 
+			// Writes are acknowledged once applied, so they are all counted.
 			if err == nil {
-				for {
-					count := st.count()
-					if count != nil && *count == int64(len(input)) {
-						break
-					}
-				}
+				count, cerr := st.count(context.Background())
+				assert.NoError(t, cerr)
+				assert.Equal(t, int64(len(input)), count)
 			}
 
 			st.stop()
@@ -248,7 +444,7 @@ func Test_Read(t *testing.T) {
 		name           string
 		ctx            context.Context
 		dbtimeOut      int
-		registers      map[identifier]line
+		registers      map[identifier]example.Line
 		searchedid     identifier
 		expectedResult *example.Line
 		expectedError  error
@@ -257,19 +453,10 @@ func Test_Read(t *testing.T) {
 			name:      "found-test-case",
 			ctx:       context.Background(),
 			dbtimeOut: 1,
-			registers: map[identifier]line{
-				"one": {
-					createdAT: tstamp.Format(timeLayout),
-					data:      "first-line",
-				},
-				"two": {
-					createdAT: tstamp.Format(timeLayout),
-					data:      "second-line",
-				},
-				"three": {
-					createdAT: tstamp.Format(timeLayout),
-					data:      "third-line",
-				},
+			registers: map[identifier]example.Line{
+				"one":   {ID: identifier("one"), Created: tstamp, Data: "first-line"},
+				"two":   {ID: identifier("two"), Created: tstamp, Data: "second-line"},
+				"three": {ID: identifier("three"), Created: tstamp, Data: "third-line"},
 			},
 			searchedid: identifier("two"),
 			expectedResult: &example.Line{
@@ -282,19 +469,10 @@ func Test_Read(t *testing.T) {
 			name:      "not-found-test-case",
 			ctx:       context.Background(),
 			dbtimeOut: 1,
-			registers: map[identifier]line{
-				"one": {
-					createdAT: tstamp.Format(timeLayout),
-					data:      "first-line",
-				},
-				"two": {
-					createdAT: tstamp.Format(timeLayout),
-					data:      "second-line",
-				},
-				"three": {
-					createdAT: tstamp.Format(timeLayout),
-					data:      "third-line",
-				},
+			registers: map[identifier]example.Line{
+				"one":   {ID: identifier("one"), Created: tstamp, Data: "first-line"},
+				"two":   {ID: identifier("two"), Created: tstamp, Data: "second-line"},
+				"three": {ID: identifier("three"), Created: tstamp, Data: "third-line"},
 			},
 			searchedid:     identifier("x"),
 			expectedResult: nil,
@@ -313,11 +491,11 @@ func Test_Read(t *testing.T) {
 		storeCtx, cancel := context.WithCancel(context.Background())
 
 		st := Store{
-			ctx:            storeCtx,
-			cancel:         cancel,
-			data:           c.registers,
-			request:        make(chan request),
-			timeoutSeconds: c.dbtimeOut,
+			ctx:     storeCtx,
+			cancel:  cancel,
+			data:    c.registers,
+			request: make(chan request),
+			timeout: newOpTimeout(time.Duration(c.dbtimeOut) * time.Second),
 		}
 
 		searchedID := c.searchedid
@@ -337,3 +515,513 @@ func Test_Read(t *testing.T) {
 		})
 	}
 }
+
+func Test_Check(t *testing.T) {
+	testCases := []struct {
+		name          string
+		running       bool
+		expectedError error
+	}{
+		{
+			name:          "running-store-case",
+			running:       true,
+			expectedError: nil,
+		},
+		{
+			name:          "stopped-store-case",
+			running:       false,
+			expectedError: ErrTimeOut,
+		},
+	}
+
+	for _, c := range testCases {
+		name := c.name
+		running := c.running
+		expectedError := c.expectedError
+
+		t.Run(name, func(t *testing.T) {
+			storeCtx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			st := Store{
+				ctx:     storeCtx,
+				cancel:  cancel,
+				data:    make(map[identifier]example.Line),
+				request: make(chan request),
+			}
+
+			if running {
+				st.start()
+			}
+
+			ctx, checkCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer checkCancel()
+
+			assert.Equal(t, healthCheckName, st.Name())
+			assert.Equal(t, expectedError, st.Check(ctx))
+		})
+	}
+}
+
+func Test_WriteManyReadMany(t *testing.T) {
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name            string
+		running         bool
+		input           []example.Line
+		searchedIDs     []example.Identifier
+		expectedErrors  []error
+		expectedResult  []*example.Line
+		expectedReadErr error
+	}{
+		{
+			name:    "found-and-missing-case",
+			running: true,
+			input: []example.Line{
+				{ID: identifier("one"), Created: tstamp, Data: "first-line"},
+				{ID: identifier("two"), Created: tstamp, Data: "second-line"},
+			},
+			searchedIDs:    []example.Identifier{identifier("two"), identifier("x"), identifier("one")},
+			expectedErrors: []error{nil, nil},
+			expectedResult: []*example.Line{
+				{ID: identifier("two"), Created: tstamp, Data: "second-line"},
+				nil,
+				{ID: identifier("one"), Created: tstamp, Data: "first-line"},
+			},
+		},
+		{
+			name:           "empty-case",
+			running:        true,
+			input:          []example.Line{},
+			searchedIDs:    []example.Identifier{},
+			expectedErrors: []error{},
+			expectedResult: []*example.Line{},
+		},
+		{
+			name:    "stopped-store-case",
+			running: false,
+			input: []example.Line{
+				{ID: identifier("one"), Created: tstamp, Data: "first-line"},
+				{ID: identifier("two"), Created: tstamp, Data: "second-line"},
+			},
+			searchedIDs:     []example.Identifier{identifier("one")},
+			expectedErrors:  []error{ErrTimeOut, ErrTimeOut},
+			expectedResult:  nil,
+			expectedReadErr: ErrTimeOut,
+		},
+	}
+
+	for _, c := range testCases {
+		input := c.input
+		searchedIDs := c.searchedIDs
+		running := c.running
+		expectedErrors := c.expectedErrors
+		expectedResult := c.expectedResult
+		expectedReadErr := c.expectedReadErr
+
+		t.Run(c.name, func(t *testing.T) {
+			storeCtx, cancel := context.WithCancel(context.Background())
+
+			st := Store{
+				ctx:     storeCtx,
+				cancel:  cancel,
+				data:    make(map[identifier]example.Line),
+				request: make(chan request),
+				timeout: newOpTimeout(50 * time.Millisecond),
+			}
+
+			if running {
+				st.start()
+			}
+			defer st.stop()
+
+			errs := st.WriteMany(context.Background(), input)
+			result, err := st.ReadMany(context.Background(), searchedIDs)
+
+			assert.Equal(t, expectedErrors, errs)
+			assert.Equal(t, expectedResult, result)
+			assert.Equal(t, expectedReadErr, err)
+		})
+	}
+}
+
+func Test_Scan(t *testing.T) {
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		lines         int
+		expectedLines int
+	}{
+		{
+			name:          "empty-case",
+			lines:         0,
+			expectedLines: 0,
+		},
+		{
+			name:          "single-page-case",
+			lines:         3,
+			expectedLines: 3,
+		},
+		{
+			name:          "many-pages-case",
+			lines:         2*scanPageSize + 1,
+			expectedLines: 2*scanPageSize + 1,
+		},
+	}
+
+	for _, c := range testCases {
+		lines := c.lines
+		expectedLines := c.expectedLines
+
+		t.Run(c.name, func(t *testing.T) {
+			storeCtx, cancel := context.WithCancel(context.Background())
+
+			st := Store{
+				ctx:     storeCtx,
+				cancel:  cancel,
+				data:    make(map[identifier]example.Line),
+				request: make(chan request),
+				timeout: newOpTimeout(50 * time.Millisecond),
+			}
+
+			st.start()
+			defer st.stop()
+
+			input := make([]example.Line, lines)
+			for i := range input {
+				input[i] = example.Line{ID: identifier(fmt.Sprintf("%04d", i)), Created: tstamp, Data: fmt.Sprintf("line-%d", i)}
+			}
+			st.WriteMany(context.Background(), input)
+
+			cur, err := st.Scan(context.Background())
+			require.NoError(t, err)
+
+			var scanned []example.Line
+			for cur.Next(context.Background()) {
+				scanned = append(scanned, cur.Line())
+			}
+
+			assert.NoError(t, cur.Err())
+			assert.NoError(t, cur.Close(context.Background()))
+			assert.Len(t, scanned, expectedLines)
+			if expectedLines > 0 {
+				assert.Equal(t, input, scanned)
+			}
+		})
+	}
+}
+
+func Test_ScanSkipsRemovedLines(t *testing.T) {
+	storeCtx, cancel := context.WithCancel(context.Background())
+
+	st := Store{
+		ctx:     storeCtx,
+		cancel:  cancel,
+		data:    make(map[identifier]example.Line),
+		request: make(chan request),
+		timeout: newOpTimeout(50 * time.Millisecond),
+	}
+
+	st.start()
+	defer st.stop()
+
+	cur := &cursor{ids: []identifier{"missing"}, read: st.readPage}
+
+	assert.False(t, cur.Next(context.Background()))
+	assert.NoError(t, cur.Err())
+}
+
+func Test_ScanStoppedStore(t *testing.T) {
+	storeCtx, cancel := context.WithCancel(context.Background())
+
+	st := Store{
+		ctx:     storeCtx,
+		cancel:  cancel,
+		data:    make(map[identifier]example.Line),
+		request: make(chan request),
+		timeout: newOpTimeout(50 * time.Millisecond),
+	}
+
+	cur, err := st.Scan(context.Background())
+
+	assert.Nil(t, cur)
+	assert.Equal(t, ErrTimeOut, err)
+
+	cur = &cursor{ids: []identifier{"one"}, read: st.readPage}
+
+	assert.False(t, cur.Next(context.Background()))
+	assert.Equal(t, ErrTimeOut, cur.Err())
+}
+
+func Test_Search(t *testing.T) {
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+
+	input := []example.Line{
+		{ID: identifier("a"), Created: tstamp, Data: "the quick brown fox"},
+		{ID: identifier("b"), Created: tstamp, Data: "Fox, fox and more FOX"},
+		{ID: identifier("c"), Created: tstamp, Data: "a lazy dog"},
+		{ID: identifier("d"), Created: tstamp, Data: "the brown dog"},
+	}
+
+	testCases := []struct {
+		name          string
+		query         example.SearchQuery
+		expectedIDs   []identifier
+		expectedTotal int64
+	}{
+		{
+			name:          "ranked-case",
+			query:         example.SearchQuery{Text: "fox"},
+			expectedIDs:   []identifier{"b", "a"},
+			expectedTotal: 2,
+		},
+		{
+			name:          "any-term-case",
+			query:         example.SearchQuery{Text: "lazy brown"},
+			expectedIDs:   []identifier{"c", "a", "d"},
+			expectedTotal: 3,
+		},
+		{
+			name:          "page-case",
+			query:         example.SearchQuery{Text: "lazy brown", Offset: 1, Limit: 1},
+			expectedIDs:   []identifier{"a"},
+			expectedTotal: 3,
+		},
+		{
+			name:          "past-the-end-case",
+			query:         example.SearchQuery{Text: "dog", Offset: 5},
+			expectedIDs:   []identifier{},
+			expectedTotal: 2,
+		},
+		{
+			name:          "partial-match-case",
+			query:         example.SearchQuery{Text: "quick-silver"},
+			expectedIDs:   []identifier{"a"},
+			expectedTotal: 1,
+		},
+		{
+			name:          "stored-line-case",
+			query:         example.SearchQuery{Text: "cat"},
+			expectedIDs:   []identifier{"e"},
+			expectedTotal: 1,
+		},
+	}
+
+	for _, c := range testCases {
+		query := c.query
+		expectedIDs := c.expectedIDs
+		expectedTotal := c.expectedTotal
+
+		t.Run(c.name, func(t *testing.T) {
+			storeCtx, cancel := context.WithCancel(context.Background())
+
+			st := Store{
+				ctx:     storeCtx,
+				cancel:  cancel,
+				data:    map[identifier]example.Line{"e": {ID: identifier("e"), Created: tstamp, Data: "cat"}},
+				request: make(chan request),
+				timeout: newOpTimeout(50 * time.Millisecond),
+			}
+
+			st.start()
+			defer st.stop()
+
+			st.WriteMany(context.Background(), input[:3])
+			assert.NoError(t, st.Write(context.Background(), input[3]))
+
+			result, err := st.Search(context.Background(), query)
+			require.NoError(t, err)
+
+			ids := make([]identifier, len(result.Hits))
+			for i, hit := range result.Hits {
+				ids[i] = hit.Line.ID.(identifier)
+			}
+
+			assert.Equal(t, expectedIDs, ids)
+			assert.Equal(t, expectedTotal, result.Total)
+		})
+	}
+}
+
+func Test_Find(t *testing.T) {
+	day := time.Date(2018, time.September, 16, 0, 0, 0, 0, time.UTC)
+
+	input := []example.Line{
+		{ID: identifier("a"), Created: day.Add(-time.Hour), Data: "report: late"},
+		{ID: identifier("b"), Created: day, Data: "report: midnight"},
+		{ID: identifier("c"), Created: day.Add(12 * time.Hour), Data: "note: noon"},
+		{ID: identifier("d"), Created: day.Add(12 * time.Hour), Data: "report: noon"},
+		{ID: identifier("e"), Created: day.Add(24 * time.Hour), Data: "report: next day"},
+	}
+
+	testCases := []struct {
+		name        string
+		spec        example.LineSpecification
+		expectedIDs []identifier
+	}{
+		{
+			name:        "everything-case",
+			spec:        example.LineSpecification{},
+			expectedIDs: []identifier{"a", "b", "c", "d", "e"},
+		},
+		{
+			name:        "one-day-case",
+			spec:        example.LineSpecification{CreatedAfter: day, CreatedBefore: day.Add(24 * time.Hour)},
+			expectedIDs: []identifier{"b", "c", "d"},
+		},
+		{
+			name:        "prefix-descending-case",
+			spec:        example.LineSpecification{DataPrefix: "report:", Sort: example.SortDescending},
+			expectedIDs: []identifier{"e", "d", "b", "a"},
+		},
+		{
+			name:        "limit-case",
+			spec:        example.LineSpecification{CreatedAfter: day, Limit: 2},
+			expectedIDs: []identifier{"b", "c"},
+		},
+		{
+			name:        "no-match-case",
+			spec:        example.LineSpecification{DataPrefix: "missing"},
+			expectedIDs: []identifier{},
+		},
+	}
+
+	for _, c := range testCases {
+		spec := c.spec
+		expectedIDs := c.expectedIDs
+
+		t.Run(c.name, func(t *testing.T) {
+			storeCtx, cancel := context.WithCancel(context.Background())
+
+			st := Store{
+				ctx:     storeCtx,
+				cancel:  cancel,
+				data:    make(map[identifier]example.Line),
+				request: make(chan request),
+				timeout: newOpTimeout(50 * time.Millisecond),
+			}
+
+			st.start()
+			defer st.stop()
+
+			st.WriteMany(context.Background(), input)
+
+			lines, err := st.Find(context.Background(), spec)
+			require.NoError(t, err)
+
+			ids := make([]identifier, len(lines))
+			for i, line := range lines {
+				ids[i] = line.ID.(identifier)
+			}
+
+			assert.Equal(t, expectedIDs, ids)
+		})
+	}
+}
+
+func Test_DeleteRestorePurge(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2018, time.September, 16, 10, 0, 0, 0, time.UTC)
+	deleted := created.Add(24 * time.Hour)
+
+	storeCtx, cancel := context.WithCancel(ctx)
+
+	st := Store{
+		ctx:     storeCtx,
+		cancel:  cancel,
+		data:    make(map[identifier]example.Line),
+		request: make(chan request),
+		timeout: newOpTimeout(50 * time.Millisecond),
+	}
+
+	st.start()
+	defer st.stop()
+
+	st.WriteMany(ctx, []example.Line{
+		{ID: identifier("a"), Created: created, Data: "first line"},
+		{ID: identifier("b"), Created: created, Data: "second line"},
+	})
+
+	found, err := st.Delete(ctx, identifier("missing"), deleted)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	found, err = st.Delete(ctx, identifier("a"), deleted)
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	found, err = st.Delete(ctx, identifier("a"), deleted.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	line, err := st.Read(ctx, identifier("a"))
+	require.NoError(t, err)
+	assert.Equal(t, deleted, line.DeletedAt, "a second delete keeps the first time")
+
+	lines, err := st.Find(ctx, example.LineSpecification{})
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	assert.Equal(t, identifier("b"), lines[0].ID)
+
+	lines, err = st.Find(ctx, example.LineSpecification{IncludeDeleted: true})
+	require.NoError(t, err)
+	assert.Len(t, lines, 2)
+
+	result, err := st.Search(ctx, example.SearchQuery{Text: "first"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), result.Total)
+
+	result, err = st.Search(ctx, example.SearchQuery{Text: "first", IncludeDeleted: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Total)
+
+	found, err = st.Restore(ctx, identifier("a"))
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	line, err = st.Read(ctx, identifier("a"))
+	require.NoError(t, err)
+	assert.False(t, line.Deleted())
+
+	found, err = st.Restore(ctx, identifier("missing"))
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	st.Delete(ctx, identifier("b"), deleted)
+
+	purged, err := st.Purge(ctx, deleted)
+	require.NoError(t, err)
+	assert.Empty(t, purged, "the retention is not over")
+
+	purged, err = st.Purge(ctx, deleted.Add(time.Second))
+	require.NoError(t, err)
+	require.Len(t, purged, 1)
+	assert.Equal(t, identifier("b"), purged[0].ID)
+
+	line, err = st.Read(ctx, identifier("b"))
+	require.NoError(t, err)
+	assert.Nil(t, line)
+
+	result, err = st.Search(ctx, example.SearchQuery{Text: "second", IncludeDeleted: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), result.Total)
+}
+
+func Test_DeleteStoppedStore(t *testing.T) {
+	st := Store{
+		ctx:     context.Background(),
+		request: make(chan request),
+		timeout: newOpTimeout(10 * time.Millisecond),
+	}
+
+	_, err := st.Delete(context.Background(), identifier("a"), time.Now())
+	assert.ErrorIs(t, err, ErrTimeOut)
+
+	_, err = st.Restore(context.Background(), identifier("a"))
+	assert.ErrorIs(t, err, ErrTimeOut)
+
+	_, err = st.Purge(context.Background(), time.Now())
+	assert.ErrorIs(t, err, ErrTimeOut)
+}
@@ -0,0 +1,308 @@
+package memory
+
+import (
+	"context"
+	"hash/fnv"
+	"io"
+	"sync"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+type shard struct {
+	mu sync.RWMutex
+	partition
+}
+
+// ShardedStore spreads the lines over shards by identifier, each behind its
+// own lock, so readers only wait for the writers of the same shard. Requests
+// over several shards lock them in shard order, so a batch is written, and
+// read, as a whole.
+type ShardedStore struct {
+	shards []*shard
+}
+
+func NewShardedRepo(cnf Config) *ShardedStore {
+	s := &ShardedStore{shards: make([]*shard, cnf.Shards())}
+	for i := range s.shards {
+		s.shards[i] = &shard{partition: newPartition(make(map[identifier]example.Line))}
+	}
+
+	return s
+}
+
+func (s *ShardedStore) shardOf(id identifier) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+
+	return int(h.Sum32() % uint32(len(s.shards)))
+}
+
+// lock locks the shards of ids, or every shard when ids is nil, in shard
+// order and returns the function unlocking them.
+func (s *ShardedStore) lock(ids []identifier, write bool) func() {
+	var locked []*shard
+	if ids == nil {
+		locked = s.shards
+	} else {
+		used := make([]bool, len(s.shards))
+		for _, id := range ids {
+			used[s.shardOf(id)] = true
+		}
+
+		for i, u := range used {
+			if u {
+				locked = append(locked, s.shards[i])
+			}
+		}
+	}
+
+	for _, sh := range locked {
+		if write {
+			sh.mu.Lock()
+		} else {
+			sh.mu.RLock()
+		}
+	}
+
+	return func() {
+		for _, sh := range locked {
+			if write {
+				sh.mu.Unlock()
+			} else {
+				sh.mu.RUnlock()
+			}
+		}
+	}
+}
+
+func (s *ShardedStore) partitions() []partition {
+	parts := make([]partition, len(s.shards))
+	for i, sh := range s.shards {
+		parts[i] = sh.partition
+	}
+
+	return parts
+}
+
+// alive fails for a caller already gone, the store never waits but for its
+// locks.
+func alive(ctx context.Context) error {
+	if ctx != nil && ctx.Err() != nil {
+		return ErrTimeOut
+	}
+
+	return nil
+}
+
+func (s *ShardedStore) Write(ctx context.Context, n example.Line) error {
+	if err := alive(ctx); err != nil {
+		return err
+	}
+
+	id := identifier(n.ID.String())
+	sh := s.shards[s.shardOf(id)]
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	return sh.insert(id, newLine(id, n))
+}
+
+func (s *ShardedStore) Read(ctx context.Context, id example.Identifier) (*example.Line, error) {
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+
+	key := identifier(id.String())
+	sh := s.shards[s.shardOf(key)]
+
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+
+	return findLine(sh.data, key), nil
+}
+
+func (s *ShardedStore) WriteMany(ctx context.Context, lines []example.Line) []error {
+	errs := make([]error, len(lines))
+	if err := alive(ctx); err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+
+		return errs
+	}
+
+	ids := make([]identifier, len(lines))
+	for i, l := range lines {
+		ids[i] = identifier(l.ID.String())
+	}
+
+	defer s.lock(ids, true)()
+
+	for i, id := range ids {
+		errs[i] = s.shards[s.shardOf(id)].insert(id, newLine(id, lines[i]))
+	}
+
+	return errs
+}
+
+func (s *ShardedStore) ReadMany(ctx context.Context, ids []example.Identifier) ([]*example.Line, error) {
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+
+	keys := make([]identifier, len(ids))
+	for i, id := range ids {
+		keys[i] = identifier(id.String())
+	}
+
+	return s.readMany(ctx, keys)
+}
+
+func (s *ShardedStore) readMany(ctx context.Context, ids []identifier) ([]*example.Line, error) {
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+
+	defer s.lock(ids, false)()
+
+	lines := make([]*example.Line, len(ids))
+	for i, id := range ids {
+		lines[i] = findLine(s.shards[s.shardOf(id)].data, id)
+	}
+
+	return lines, nil
+}
+
+// Scan takes a snapshot of the identifiers only, the lines are read a page
+// at a time as the cursor moves. Lines removed meanwhile are skipped.
+func (s *ShardedStore) Scan(ctx context.Context) (example.LineCursor, error) {
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+
+	unlock := s.lock(nil, false)
+
+	var ids []identifier
+	for _, sh := range s.shards {
+		ids = append(ids, sh.keys()...)
+	}
+
+	unlock()
+
+	return &cursor{ids: sortKeys(ids), read: s.readMany}, nil
+}
+
+func (s *ShardedStore) Search(ctx context.Context, query example.SearchQuery) (example.SearchResult, error) {
+	if err := alive(ctx); err != nil {
+		return example.SearchResult{}, err
+	}
+
+	defer s.lock(nil, false)()
+
+	return search(query, s.partitions()...), nil
+}
+
+func (s *ShardedStore) Find(ctx context.Context, spec example.LineSpecification) ([]example.Line, error) {
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+
+	defer s.lock(nil, false)()
+
+	return find(spec, s.partitions()...), nil
+}
+
+// Delete marks the line as deleted, keeping the time of an earlier deletion.
+func (s *ShardedStore) Delete(ctx context.Context, id example.Identifier, at time.Time) (bool, error) {
+	if err := alive(ctx); err != nil {
+		return false, err
+	}
+
+	key := identifier(id.String())
+	sh := s.shards[s.shardOf(key)]
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	return sh.delete(key, at), nil
+}
+
+func (s *ShardedStore) Restore(ctx context.Context, id example.Identifier) (bool, error) {
+	if err := alive(ctx); err != nil {
+		return false, err
+	}
+
+	key := identifier(id.String())
+	sh := s.shards[s.shardOf(key)]
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	return sh.restore(key), nil
+}
+
+// Purge removes the lines deleted before the given time, a shard at a time.
+func (s *ShardedStore) Purge(ctx context.Context, before time.Time) ([]example.Line, error) {
+	if err := alive(ctx); err != nil {
+		return nil, err
+	}
+
+	var purged []example.Line
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		purged = append(purged, sh.purge(before)...)
+		sh.mu.Unlock()
+	}
+
+	return purged, nil
+}
+
+func (s *ShardedStore) count() int64 {
+	defer s.lock(nil, false)()
+
+	var count int64
+	for _, sh := range s.shards {
+		count += int64(len(sh.data))
+	}
+
+	return count
+}
+
+func (s *ShardedStore) Name() string {
+	return healthCheckName
+}
+
+// Check takes the read lock of every shard in turn, so a shard held by a
+// stuck writer is reported as unhealthy once ctx is done instead of blocking
+// the caller.
+func (s *ShardedStore) Check(ctx context.Context) error {
+	var done <-chan struct{}
+	if ctx != nil {
+		done = ctx.Done()
+	}
+
+	for _, sh := range s.shards {
+		for !sh.mu.TryRLock() {
+			select {
+			case <-done:
+				return ErrTimeOut
+			case <-time.After(checkInterval):
+			}
+		}
+
+		sh.mu.RUnlock()
+	}
+
+	return alive(ctx)
+}
+
+// ApplyConfig validates the memory config read from r. The sharded store has
+// no timeout, the rest of the config is only read on start.
+func (s *ShardedStore) ApplyConfig(r io.Reader) error {
+	_, err := parseConfig(r)
+
+	return err
+}
@@ -0,0 +1,290 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+func Test_NewSelectsImplementation(t *testing.T) {
+	repo := New(context.Background(), config{Impl: ShardedImplementation, ShardsNum: 4})
+
+	sharded, is := repo.(*ShardedStore)
+	require.True(t, is)
+	assert.Len(t, sharded.shards, 4)
+	assert.Equal(t, healthCheckName, sharded.Name())
+	assert.NoError(t, sharded.Check(context.Background()))
+}
+
+// Test_ShardedStoreMatchesChannelStore runs the same requests against both
+// implementations and expects the same answers.
+func Test_ShardedStoreMatchesChannelStore(t *testing.T) {
+	ctx := context.Background()
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+
+	channel := newRunningStore(time.Minute)
+	defer channel.stop()
+
+	stores := map[string]Repository{
+		ChannelImplementation: channel,
+		ShardedImplementation: NewShardedRepo(config{ShardsNum: 3}),
+	}
+
+	type answer struct {
+		Request string
+		Value   interface{}
+		Err     error
+	}
+
+	run := func(repo Repository) []answer {
+		var answers []answer
+		record := func(request string, value interface{}, err error) {
+			answers = append(answers, answer{request, value, err})
+		}
+
+		for i := 0; i < 20; i++ {
+			record("write", nil, repo.Write(ctx, example.Line{
+				ID:      identifier(fmt.Sprintf("line-%02d", i)),
+				Created: tstamp.Add(time.Duration(i) * time.Hour),
+				Data:    fmt.Sprintf("the line number %d of %d lines", i%7, i%3),
+			}))
+		}
+
+		record("write-many", repo.WriteMany(ctx, []example.Line{
+			{ID: identifier("line-03"), Created: tstamp, Data: "rewritten line"},
+			{ID: identifier("line-30"), Created: tstamp, Data: "batch line"},
+		}), nil)
+
+		for _, id := range []string{"line-03", "line-30", "missing"} {
+			l, err := repo.Read(ctx, identifier(id))
+			record("read "+id, l, err)
+		}
+
+		lines, err := repo.ReadMany(ctx, []example.Identifier{identifier("line-01"), identifier("missing"), identifier("line-19")})
+		record("read-many", lines, err)
+
+		for _, id := range []string{"line-04", "line-05", "missing"} {
+			found, err := repo.Delete(ctx, identifier(id), tstamp)
+			record("delete "+id, found, err)
+		}
+
+		found, err := repo.Delete(ctx, identifier("line-04"), tstamp.Add(time.Hour))
+		record("delete again", found, err)
+
+		found, err = repo.Restore(ctx, identifier("line-05"))
+		record("restore", found, err)
+
+		for _, query := range []example.SearchQuery{
+			{Text: "line number 3", Limit: 5},
+			{Text: "lines", Offset: 2, Limit: 4},
+			{Text: "line", IncludeDeleted: true, Limit: 50},
+			{Text: "nothing"},
+		} {
+			result, err := repo.Search(ctx, query)
+			record("search "+query.Text, result, err)
+		}
+
+		for _, spec := range []example.LineSpecification{
+			{Limit: 50},
+			{DataPrefix: "the line", Sort: example.SortDescending, Limit: 5},
+			{CreatedAfter: tstamp.Add(5 * time.Hour), IncludeDeleted: true, Limit: 50},
+		} {
+			matches, err := repo.Find(ctx, spec)
+			record("find", matches, err)
+		}
+
+		cur, err := repo.Scan(ctx)
+		record("scan", nil, err)
+
+		var scanned []example.Line
+		for cur.Next(ctx) {
+			scanned = append(scanned, cur.Line())
+		}
+		record("scanned", scanned, cur.Err())
+
+		purged, err := repo.Purge(ctx, tstamp.Add(time.Minute))
+		record("purge", purged, err)
+
+		lines, err = repo.ReadMany(ctx, []example.Identifier{identifier("line-04"), identifier("line-05")})
+		record("read purged", lines, err)
+
+		return answers
+	}
+
+	expected := run(stores[ChannelImplementation])
+	assert.Equal(t, expected, run(stores[ShardedImplementation]))
+}
+
+func Test_ShardedStoreCanceledCaller(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	st := NewShardedRepo(config{})
+
+	assert.ErrorIs(t, st.Write(ctx, example.Line{ID: identifier("one")}), ErrTimeOut)
+	assert.ErrorIs(t, st.WriteMany(ctx, []example.Line{{ID: identifier("one")}})[0], ErrTimeOut)
+
+	_, err := st.Read(ctx, identifier("one"))
+	assert.ErrorIs(t, err, ErrTimeOut)
+
+	_, err = st.Scan(ctx)
+	assert.ErrorIs(t, err, example.ErrTimeout)
+
+	assert.ErrorIs(t, st.Check(ctx), ErrTimeOut)
+	assert.Equal(t, int64(0), st.count())
+}
+
+func Test_ShardedCheck(t *testing.T) {
+	st := NewShardedRepo(config{ShardsNum: 4})
+
+	sh := st.shards[st.shardOf(identifier("one"))]
+	sh.mu.Lock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, st.Check(ctx), ErrTimeOut, "a shard held by a writer")
+	assert.ErrorIs(t, st.Check(ctx), example.ErrTimeout)
+
+	checked := make(chan error, 1)
+	go func() {
+		checked <- st.Check(nil)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	sh.mu.Unlock()
+
+	select {
+	case err := <-checked:
+		assert.NoError(t, err, "the writer is done")
+	case <-time.After(time.Second):
+		t.Fatal("check still blocked")
+	}
+}
+
+func Test_ShardedConcurrentReadersAndWriters(t *testing.T) {
+	const (
+		writers = 2000
+		readers = 2000
+	)
+
+	ctx := context.Background()
+	st := NewShardedRepo(config{})
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			id := identifier(fmt.Sprintf("line-%05d", i))
+			if i%2 == 0 {
+				assert.NoError(t, st.Write(ctx, example.Line{ID: id, Created: tstamp, Data: "a line"}))
+			} else {
+				assert.Equal(t, []error{nil}, st.WriteMany(ctx, []example.Line{{ID: id, Created: tstamp, Data: "a line"}}))
+			}
+
+			if i%10 == 0 {
+				_, err := st.Delete(ctx, id, tstamp)
+				assert.NoError(t, err)
+			}
+		}(i)
+	}
+
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			var err error
+			switch {
+			case i%50 == 0:
+				_, err = st.Search(ctx, example.SearchQuery{Text: "line", Limit: 10})
+			case i%50 == 1:
+				_, err = st.Find(ctx, example.LineSpecification{Limit: 10})
+			case i%50 == 2:
+				var cur example.LineCursor
+				if cur, err = st.Scan(ctx); err == nil {
+					for cur.Next(ctx) {
+					}
+					err = cur.Err()
+				}
+			default:
+				_, err = st.ReadMany(ctx, []example.Identifier{identifier(fmt.Sprintf("line-%05d", i))})
+			}
+
+			assert.NoError(t, err)
+		}(i)
+	}
+
+	require.True(t, waitTimeout(&wg, time.Minute), "requests still blocked")
+
+	assert.Equal(t, int64(writers), st.count())
+
+	purged, err := st.Purge(ctx, tstamp.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Len(t, purged, writers/10)
+}
+
+// Test_RoundTripKeepsLines expects both implementations to give back the
+// lines as written, to the nanosecond and in the location of their times.
+func Test_RoundTripKeepsLines(t *testing.T) {
+	ctx := context.Background()
+	zone := time.FixedZone("UTC-3", -3*60*60)
+	created := time.Date(2018, time.September, 16, 12, 0, 0, 123456789, zone)
+	deleted := time.Date(2019, time.March, 1, 8, 30, 15, 987654321, time.UTC)
+
+	channel := newRunningStore(time.Minute)
+	defer channel.stop()
+
+	stores := map[string]Repository{
+		ChannelImplementation: channel,
+		ShardedImplementation: NewShardedRepo(config{ShardsNum: 3}),
+	}
+
+	for name, repo := range stores {
+		repo := repo
+
+		t.Run(name, func(t *testing.T) {
+			single := example.Line{ID: identifier("single"), Created: created, Data: "single line", DeletedAt: deleted}
+			batch := []example.Line{
+				{ID: identifier("first"), Created: created, Data: "first line"},
+				{ID: identifier("second"), Created: created.Add(time.Nanosecond), Data: "second line"},
+			}
+
+			require.NoError(t, repo.Write(ctx, single))
+			assert.Equal(t, []error{nil, nil}, repo.WriteMany(ctx, batch))
+
+			read, err := repo.Read(ctx, single.ID)
+			require.NoError(t, err)
+			assert.Equal(t, &single, read)
+
+			lines, err := repo.ReadMany(ctx, []example.Identifier{batch[0].ID, batch[1].ID})
+			require.NoError(t, err)
+			assert.Equal(t, []*example.Line{&batch[0], &batch[1]}, lines)
+
+			at := time.Date(2020, time.May, 5, 5, 5, 5, 5, zone)
+			found, err := repo.Delete(ctx, batch[0].ID, at)
+			require.NoError(t, err)
+			require.True(t, found)
+
+			read, err = repo.Read(ctx, batch[0].ID)
+			require.NoError(t, err)
+			assert.Equal(t, at, read.DeletedAt)
+
+			matches, err := repo.Find(ctx, example.LineSpecification{DataPrefix: "second"})
+			require.NoError(t, err)
+			assert.Equal(t, []example.Line{batch[1]}, matches)
+		})
+	}
+}
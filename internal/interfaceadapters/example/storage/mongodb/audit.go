@@ -0,0 +1,143 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const (
+	auditIndexName string = "line_id_at"
+
+	auditCollectionSuffix string = "_audit"
+)
+
+// auditRecord keeps the line values as they are stored in the lines
+// collection.
+type auditRecord struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	LineID    interface{}        `bson:"line_id"`
+	Action    string             `bson:"action"`
+	Actor     string             `bson:"actor"`
+	RequestID string             `bson:"request_id"`
+	At        time.Time          `bson:"at"`
+	Before    *line              `bson:"before,omitempty"`
+	After     *line              `bson:"after,omitempty"`
+}
+
+// auditLog only ever inserts into its collection, the records are never
+// updated nor deleted.
+type auditLog struct {
+	ctx        context.Context
+	collection mongoCollection
+	timeout    *opTimeout
+}
+
+func auditIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "line_id", Value: 1}, {Key: "at", Value: 1}, {Key: "_id", Value: 1}},
+			Options: options.Index().SetName(auditIndexName),
+		},
+	}
+}
+
+func (al auditLog) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return store{ctx: al.ctx, timeout: al.timeout}.withTimeout(ctx)
+}
+
+func (al auditLog) Append(ctx context.Context, records []example.AuditRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	ctx, cancel := al.withTimeout(ctx)
+	defer cancel()
+
+	documents := make([]interface{}, len(records))
+	for i, record := range records {
+		document, err := storedRecord(record)
+		if err != nil {
+			return err
+		}
+
+		documents[i] = document
+	}
+
+	if _, err := al.collection.InsertMany(ctx, documents); err != nil {
+		return storeError(err, ErrDataInserted)
+	}
+
+	return nil
+}
+
+func (al auditLog) History(ctx context.Context, id example.Identifier) ([]example.AuditRecord, error) {
+	ctx, cancel := al.withTimeout(ctx)
+	defer cancel()
+
+	key, err := documentID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := al.collection.Find(ctx, bson.D{{Key: "line_id", Value: key}}, opts)
+	if err != nil {
+		return nil, storeError(err, ErrMongoSystem)
+	}
+
+	var stored []auditRecord
+	if err = cursor.All(ctx, &stored); err != nil {
+		return nil, storeError(err, ErrMongoSystem)
+	}
+
+	history := make([]example.AuditRecord, len(stored))
+	for i, record := range stored {
+		history[i] = example.AuditRecord{
+			LineID:    lineIdentifier(record.LineID),
+			Action:    example.AuditAction(record.Action),
+			Actor:     record.Actor,
+			RequestID: record.RequestID,
+			At:        record.At,
+			Before:    record.Before.registerLine(),
+			After:     record.After.registerLine(),
+		}
+	}
+
+	return history, nil
+}
+
+func storedRecord(record example.AuditRecord) (auditRecord, error) {
+	id, err := documentID(record.LineID)
+	if err != nil {
+		return auditRecord{}, err
+	}
+
+	stored := auditRecord{
+		ID:        primitive.NewObjectID(),
+		LineID:    id,
+		Action:    string(record.Action),
+		Actor:     record.Actor,
+		RequestID: record.RequestID,
+		At:        record.At,
+	}
+
+	if record.Before != nil {
+		before := storedLine(id, *record.Before)
+		stored.Before = &before
+	}
+
+	if record.After != nil {
+		after := storedLine(id, *record.After)
+		stored.After = &after
+	}
+
+	return stored, nil
+}
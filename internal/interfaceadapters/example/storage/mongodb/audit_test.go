@@ -0,0 +1,206 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"clean-arquitecture-template/internal/domain/example"
+	"clean-arquitecture-template/internal/interfaceadapters/example/storage/mongodb/mongotest"
+)
+
+func Test_AuditAppend(t *testing.T) {
+	id := Identifier(primitive.NewObjectID())
+	at := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+	created := example.Line{ID: id, Created: at, Data: "first-line"}
+
+	testCases := []struct {
+		testName      string
+		records       []example.AuditRecord
+		mongoRes      bson.D
+		expectedError error
+	}{
+		{
+			testName: "success-case",
+			records: []example.AuditRecord{
+				{LineID: id, Action: example.AuditCreate, Actor: "alice", RequestID: "r1", At: at, After: &created},
+			},
+			mongoRes: mtest.CreateSuccessResponse(),
+		},
+		{
+			testName: "nothing-to-append-case",
+		},
+		{
+			testName: "error-id-case",
+			records: []example.AuditRecord{
+				{LineID: example.MockIdentifier(""), Action: example.AuditCreate, At: at},
+			},
+			expectedError: ErrIdentifyer,
+		},
+		{
+			testName: "mongodb-error-case",
+			records: []example.AuditRecord{
+				{LineID: id, Action: example.AuditCreate, At: at, After: &created},
+			},
+			mongoRes: mtest.CreateWriteErrorsResponse(mtest.WriteError{
+				Index:   0,
+				Code:    1,
+				Message: "insert-many-error",
+			}),
+			expectedError: ErrDataInserted,
+		},
+	}
+
+	for _, c := range testCases {
+		testName := c.testName
+		records := c.records
+		mongoRes := c.mongoRes
+		expectedError := c.expectedError
+
+		mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+		defer mt.Close()
+
+		mt.Run(testName, func(mt *mtest.T) {
+			if mongoRes != nil {
+				mt.AddMockResponses(mongoRes)
+			}
+
+			al := auditLog{
+				ctx:        context.Background(),
+				collection: mt.Coll,
+			}
+
+			err := al.Append(context.Background(), records)
+
+			assert.ErrorIs(t, err, expectedError)
+		})
+	}
+}
+
+func Test_AuditHistory(t *testing.T) {
+	id := Identifier(primitive.NewObjectID())
+	at := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+	ns := fmt.Sprintf("%s.%s", "dbname", "lines_audit")
+
+	created := example.Line{ID: id, Created: at, Data: "first-line"}
+	deleted := created
+	deleted.DeletedAt = at.Add(time.Hour)
+
+	document := func(mt *mtest.T, record example.AuditRecord) bson.D {
+		stored, err := storedRecord(record)
+		require.NoError(mt, err)
+
+		bsonData, err := bson.Marshal(stored)
+		require.NoError(mt, err)
+
+		var bsonD bson.D
+		require.NoError(mt, bson.Unmarshal(bsonData, &bsonD))
+
+		return bsonD
+	}
+
+	records := []example.AuditRecord{
+		{LineID: id, Action: example.AuditCreate, Actor: "alice", RequestID: "r1", At: at, After: &created},
+		{LineID: id, Action: example.AuditDelete, Actor: "bob", RequestID: "r2", At: at.Add(time.Hour), Before: &created, After: &deleted},
+	}
+
+	testCases := []struct {
+		testName       string
+		id             example.Identifier
+		expectedResult []example.AuditRecord
+		expectedError  error
+		prepMongoMock  func(mt *mtest.T)
+	}{
+		{
+			testName:       "records-case",
+			id:             id,
+			expectedResult: records,
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, document(mt, records[0]), document(mt, records[1])))
+			},
+		},
+		{
+			testName:       "empty-case",
+			id:             id,
+			expectedResult: []example.AuditRecord{},
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+			},
+		},
+		{
+			testName:      "error-id-case",
+			id:            example.MockIdentifier(""),
+			expectedError: ErrIdentifyer,
+			prepMongoMock: func(mt *mtest.T) {},
+		},
+		{
+			testName:      "mongodb-error-case",
+			id:            id,
+			expectedError: ErrMongoSystem,
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+					Code:    1,
+					Message: "database general error",
+					Name:    "database general error",
+				}))
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		testName := c.testName
+		id := c.id
+		expectedResult := c.expectedResult
+		expectedError := c.expectedError
+		prepMongoMock := c.prepMongoMock
+
+		mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+		defer mt.Close()
+
+		mt.Run(testName, func(mt *mtest.T) {
+			prepMongoMock(mt)
+
+			al := auditLog{
+				ctx:        context.Background(),
+				collection: mt.Coll,
+			}
+
+			result, err := al.History(context.Background(), id)
+
+			assert.Equal(t, expectedResult, result)
+			assert.ErrorIs(t, err, expectedError)
+		})
+	}
+}
+
+// Test_AuditRoundTrip appends the records of two lines, out of order, and
+// expects the history of one of them to hold only its records, oldest first.
+func Test_AuditRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	id, other := Identifier(primitive.NewObjectID()), Identifier(primitive.NewObjectID())
+	at := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+
+	created := example.Line{ID: id, Created: at, Data: "first-line"}
+	deleted := created
+	deleted.DeletedAt = at.Add(time.Hour)
+
+	records := []example.AuditRecord{
+		{LineID: id, Action: example.AuditDelete, Actor: "bob", RequestID: "r2", At: at.Add(time.Hour), Before: &created, After: &deleted},
+		{LineID: other, Action: example.AuditCreate, Actor: "carol", RequestID: "r3", At: at},
+		{LineID: id, Action: example.AuditCreate, Actor: "alice", RequestID: "r1", At: at, After: &created},
+	}
+
+	al := auditLog{ctx: ctx, collection: mongotest.NewCollection()}
+	require.NoError(t, al.Append(ctx, records))
+
+	history, err := al.History(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, []example.AuditRecord{records[2], records[0]}, history)
+}
@@ -0,0 +1,79 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"clean-arquitecture-template/internal/domain/example"
+	"clean-arquitecture-template/internal/interfaceadapters/example/storage/mongodb/mongotest"
+	"clean-arquitecture-template/internal/interfaceadapters/example/storage/repotest"
+)
+
+// conformanceDSNEnv names the server the conformance suite runs against,
+// the suite is skipped without one.
+const conformanceDSNEnv string = "MONGODB_TEST_DSN"
+
+func Test_Conformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) (example.LineRepository, example.IdentityProvider) {
+		return fakeStore(), NewIdentityProvider()
+	})
+}
+
+func Test_ConformanceWithIdentities(t *testing.T) {
+	repotest.RunWithIdentities(t, func(t *testing.T) example.LineRepository {
+		return fakeStore()
+	})
+}
+
+// fakeStore is a store on in-process collections, with the text index of the
+// lines one.
+func fakeStore() store {
+	lines := mongotest.NewCollection()
+	lines.TextIndex("data")
+	timeout := newOpTimeout(defaultTimeout)
+
+	return store{
+		ctx:        context.Background(),
+		collection: lines,
+		timeout:    timeout,
+		audit:      auditLog{collection: mongotest.NewCollection(), timeout: timeout},
+	}
+}
+
+func Test_ConformanceWithServer(t *testing.T) {
+	dsn, exists := os.LookupEnv(conformanceDSNEnv)
+	if !exists {
+		t.Skipf("%s is not set", conformanceDSNEnv)
+	}
+
+	repotest.Run(t, func(t *testing.T) (example.LineRepository, example.IdentityProvider) {
+		ctx := context.Background()
+
+		cnf, err := ReadConfig(configReaderMock{
+			f: func(node string) (io.Reader, error) {
+				return strings.NewReader(fmt.Sprintf(`{"dsn": %q, "database": "repotest", "collection": "lines_%s"}`,
+					dsn, primitive.NewObjectID().Hex())), nil
+			},
+		})
+		require.NoError(t, err)
+
+		st, err := NewExampleRepo(ctx, cnf)
+		require.NoError(t, err)
+
+		t.Cleanup(func() {
+			require.NoError(t, st.collection.(*mongo.Collection).Drop(ctx))
+			require.NoError(t, st.audit.collection.(*mongo.Collection).Drop(ctx))
+			require.NoError(t, st.Close(ctx))
+		})
+
+		return st, NewIdentityProvider()
+	})
+}
@@ -2,18 +2,23 @@ package mongodb
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 
 	"clean-arquitecture-template/internal/domain/example"
 )
@@ -21,12 +26,24 @@ import (
 const (
 	ErrIdentifyer   mongoError = "invalid mongodb identifyer"
 	ErrDataInserted mongoError = "db error on insert-one"
+	ErrDataUpdated  mongoError = "db error on update"
+	ErrDataDeleted  mongoError = "db error on delete"
 	ErrMongoSystem  mongoError = "database error"
 	ErrReadConfig   mongoError = "unable to tead message"
-
-	objectIDRegexpFormat string = `ObjectID\("([a-zA-Z0-9]+)"\)`
+	ErrConnect      mongoError = "unable to connect to mongodb"
 
 	ConfigNode string = "apps.example.interface-adapters.storage.mongodb"
+
+	healthCheckName string = "mongodb"
+
+	defaultTimeout time.Duration = 5 * time.Second
+
+	scanBatchSize int32 = 500
+
+	textIndexName    string = "data_text"
+	textLanguage     string = "none"
+	createdIndexName string = "created_at"
+	deletedIndexName string = "deleted_at"
 )
 
 type mongoError string
@@ -39,12 +56,39 @@ type Config interface {
 	DSN() string
 	Database() string
 	Collection() string
+	AuditCollection() string
+	Timeout() time.Duration
+	MaxPoolSize() uint64
+	MinPoolSize() uint64
+	ServerSelectionTimeout() time.Duration
+	ConnectTimeout() time.Duration
+	RetryWrites() bool
+	ReadPreference() *readpref.ReadPref
+	WriteConcern() *writeconcern.WriteConcern
+	TLS() bool
 }
 
+// config leaves to the driver, or to the DSN, every client option not set.
 type config struct {
 	Dsn            string `json:"dsn"`
 	DbName         string `json:"database"`
 	CollectionName string `json:"collection"`
+	AuditName      string `json:"audit-collection"`
+	OpTimeout      string `json:"timeout"`
+	MaxPool        uint64 `json:"max-pool-size"`
+	MinPool        uint64 `json:"min-pool-size"`
+	SelectTimeout  string `json:"server-selection-timeout"`
+	ConnTimeout    string `json:"connect-timeout"`
+	Retry          *bool  `json:"retry-writes"`
+	ReadPref       string `json:"read-preference"`
+	Concern        string `json:"write-concern"`
+	UseTLS         bool   `json:"tls"`
+
+	timeout        time.Duration
+	selectTimeout  time.Duration
+	connectTimeout time.Duration
+	readPref       *readpref.ReadPref
+	writeConcern   *writeconcern.WriteConcern
 }
 
 func (c config) DSN() string {
@@ -59,6 +103,53 @@ func (c config) Collection() string {
 	return c.CollectionName
 }
 
+// AuditCollection defaults to the lines collection name with an _audit
+// suffix.
+func (c config) AuditCollection() string {
+	if c.AuditName == "" {
+		return c.CollectionName + auditCollectionSuffix
+	}
+
+	return c.AuditName
+}
+
+func (c config) Timeout() time.Duration {
+	return c.timeout
+}
+
+func (c config) MaxPoolSize() uint64 {
+	return c.MaxPool
+}
+
+func (c config) MinPoolSize() uint64 {
+	return c.MinPool
+}
+
+func (c config) ServerSelectionTimeout() time.Duration {
+	return c.selectTimeout
+}
+
+func (c config) ConnectTimeout() time.Duration {
+	return c.connectTimeout
+}
+
+// RetryWrites defaults to true, as in the driver.
+func (c config) RetryWrites() bool {
+	return c.Retry == nil || *c.Retry
+}
+
+func (c config) ReadPreference() *readpref.ReadPref {
+	return c.readPref
+}
+
+func (c config) WriteConcern() *writeconcern.WriteConcern {
+	return c.writeConcern
+}
+
+func (c config) TLS() bool {
+	return c.UseTLS
+}
+
 type ConfigReader interface {
 	Find(node string) (io.Reader, error)
 }
@@ -69,88 +160,253 @@ func ReadConfig(cfnReader ConfigReader) (Config, error) {
 		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
 	}
 
+	cnf, err := parseConfig(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return cnf, nil
+}
+
+func parseConfig(reader io.Reader) (config, error) {
 	d, err := io.ReadAll(reader)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+		return config{}, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
 	}
 
-	cnf := config{}
+	cnf := config{
+		timeout: defaultTimeout,
+	}
 	if err = json.Unmarshal(d, &cnf); err != nil {
-		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+		return config{}, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	if cnf.OpTimeout != "" {
+		if cnf.timeout, err = time.ParseDuration(cnf.OpTimeout); err != nil {
+			return config{}, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+		}
+	}
+
+	if cnf.SelectTimeout != "" {
+		if cnf.selectTimeout, err = time.ParseDuration(cnf.SelectTimeout); err != nil {
+			return config{}, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+		}
+	}
+
+	if cnf.ConnTimeout != "" {
+		if cnf.connectTimeout, err = time.ParseDuration(cnf.ConnTimeout); err != nil {
+			return config{}, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+		}
+	}
+
+	if cnf.MaxPool > 0 && cnf.MinPool > cnf.MaxPool {
+		return config{}, fmt.Errorf("min-pool-size %d over max-pool-size %d: %w", cnf.MinPool, cnf.MaxPool, ErrReadConfig)
+	}
+
+	if cnf.ReadPref != "" {
+		mode, err := readpref.ModeFromString(cnf.ReadPref)
+		if err != nil {
+			return config{}, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+		}
+
+		if cnf.readPref, err = readpref.New(mode); err != nil {
+			return config{}, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+		}
+	}
+
+	if cnf.Concern != "" {
+		if cnf.writeConcern, err = parseWriteConcern(cnf.Concern); err != nil {
+			return config{}, err
+		}
 	}
 
 	return cnf, nil
 }
 
-type Identifier primitive.ObjectID
+// parseWriteConcern takes "majority" or the number of members acknowledging
+// the writes.
+func parseWriteConcern(w string) (*writeconcern.WriteConcern, error) {
+	if w == "majority" {
+		return writeconcern.New(writeconcern.WMajority()), nil
+	}
 
-func NewIdentityProvider() Identifier {
-	return Identifier{}
-}
+	n, err := strconv.Atoi(w)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("write concern %q: %w", w, ErrReadConfig)
+	}
 
-func (id Identifier) NewID() example.Identifier {
-	return Identifier(primitive.NewObjectID())
+	return writeconcern.New(writeconcern.W(n)), nil
 }
 
-func (id Identifier) String() string {
-	rx := regexp.MustCompile(objectIDRegexpFormat)
-	r := rx.FindStringSubmatch(primitive.ObjectID(id).String())
+// clientOptions applies the config over the options of the DSN. TLS is only
+// turned on with the defaults when the DSN does not configure it already.
+func clientOptions(conf Config) *options.ClientOptions {
+	opts := options.Client().ApplyURI(conf.DSN())
 
-	if len(r) != 2 {
-		return ""
+	if n := conf.MaxPoolSize(); n > 0 {
+		opts.SetMaxPoolSize(n)
 	}
 
-	return r[1]
-}
+	if n := conf.MinPoolSize(); n > 0 {
+		opts.SetMinPoolSize(n)
+	}
 
-func (id Identifier) GetObjectID() primitive.ObjectID {
-	return primitive.ObjectID(id)
-}
+	if d := conf.ServerSelectionTimeout(); d > 0 {
+		opts.SetServerSelectionTimeout(d)
+	}
 
-func (id Identifier) ParseID(key string) (example.Identifier, error) {
-	oid, err := primitive.ObjectIDFromHex(key)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", err.Error(), ErrIdentifyer)
+	if d := conf.ConnectTimeout(); d > 0 {
+		opts.SetConnectTimeout(d)
+	}
+
+	opts.SetRetryWrites(conf.RetryWrites())
+
+	if rp := conf.ReadPreference(); rp != nil {
+		opts.SetReadPreference(rp)
+	}
+
+	if wc := conf.WriteConcern(); wc != nil {
+		opts.SetWriteConcern(wc)
+	}
+
+	if conf.TLS() && opts.TLSConfig == nil {
+		opts.SetTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12})
 	}
 
-	return Identifier(oid), nil
+	return opts
 }
 
 type mongoCollection interface {
 	InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error)
+	InsertMany(ctx context.Context, documents []interface{}, opts ...*options.InsertManyOptions) (*mongo.InsertManyResult, error)
 	FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult
+	Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error)
+	UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
+	DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
+	CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error)
+}
+
+type mongoClient interface {
+	Ping(ctx context.Context, rp *readpref.ReadPref) error
+	Disconnect(ctx context.Context) error
 }
 
 type store struct {
 	ctx        context.Context
 	collection mongoCollection
+	client     mongoClient
+	timeout    *opTimeout
+	audit      auditLog
+}
+
+// opTimeout is shared by the copies of a store and its audit log, so the
+// timeout of a config reload reaches all of them.
+type opTimeout struct {
+	d atomic.Int64
+}
+
+func newOpTimeout(d time.Duration) *opTimeout {
+	t := &opTimeout{}
+	t.d.Store(int64(d))
+
+	return t
+}
+
+// get returns no timeout for a nil opTimeout.
+func (t *opTimeout) get() time.Duration {
+	if t == nil {
+		return 0
+	}
+
+	return time.Duration(t.d.Load())
+}
+
+func (t *opTimeout) set(d time.Duration) {
+	t.d.Store(int64(d))
 }
 
-func NewExampleRepo(ctx context.Context, conf Config) store {
-	clientOptions := options.Client().ApplyURI(conf.DSN())
-	client, err := mongo.Connect(ctx, clientOptions)
+// NewExampleRepo connects to the server and makes sure the indexes exist,
+// all bound by ctx. The client is disconnected when any of it fails, and by
+// Close otherwise.
+func NewExampleRepo(ctx context.Context, conf Config) (store, error) {
+	client, err := mongo.Connect(ctx, clientOptions(conf))
+	if err != nil {
+		return store{}, fmt.Errorf("%s: %w", err.Error(), ErrConnect)
+	}
 
+	st, err := newStore(ctx, client, conf)
 	if err != nil {
-		log.Fatal(err)
+		_ = client.Disconnect(ctx)
+		return store{}, fmt.Errorf("%s: %w", err.Error(), ErrConnect)
+	}
+
+	return st, nil
+}
+
+func newStore(ctx context.Context, client *mongo.Client, conf Config) (store, error) {
+	if err := client.Ping(ctx, nil); err != nil {
+		return store{}, err
+	}
+
+	collection := client.Database(conf.Database()).Collection(conf.Collection())
+
+	// The search relies on the text index, Find on the created_at one and
+	// Purge on the deleted_at one. They are left alone when they already
+	// exist. The text index keeps the words as they are, with no stemming, a
+	// text index of another language has to be dropped first.
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "data", Value: "text"}},
+			Options: options.Index().SetName(textIndexName).SetDefaultLanguage(textLanguage),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}},
+			Options: options.Index().SetName(createdIndexName),
+		},
+		{
+			Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetName(deletedIndexName).SetSparse(true),
+		},
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		return store{}, err
 	}
 
-	if err = client.Ping(ctx, nil); err != nil {
-		log.Fatal(err)
+	audit := client.Database(conf.Database()).Collection(conf.AuditCollection())
+	if _, err := audit.Indexes().CreateMany(ctx, auditIndexes()); err != nil {
+		return store{}, err
 	}
 
+	timeout := newOpTimeout(conf.Timeout())
+
 	return store{
 		ctx:        ctx,
-		collection: client.Database(conf.Database()).Collection(conf.Collection()),
-	}
+		collection: collection,
+		client:     client,
+		timeout:    timeout,
+		audit: auditLog{
+			ctx:        ctx,
+			collection: audit,
+			timeout:    timeout,
+		},
+	}, nil
+}
+
+// AuditLog keeps the audit records in their own collection, next to the
+// lines one.
+func (s store) AuditLog() example.AuditLog {
+	return s.audit
 }
 
+// line has no deleted_at field while the line is not deleted.
 type line struct {
-	ID        primitive.ObjectID `bson:"_id"`
-	CreatedAT time.Time          `bson:"created_at"`
-	Data      string             `bson:"data"`
+	ID        interface{} `bson:"_id"`
+	CreatedAT time.Time   `bson:"created_at"`
+	Data      string      `bson:"data"`
+	DeletedAT *time.Time  `bson:"deleted_at,omitempty"`
 }
 
-func newLine(id primitive.ObjectID, createdAT time.Time, data string) line {
+func newLine(id interface{}, createdAT time.Time, data string) line {
 	return line{
 		ID:        id,
 		CreatedAT: createdAT,
@@ -158,53 +414,118 @@ func newLine(id primitive.ObjectID, createdAT time.Time, data string) line {
 	}
 }
 
+func storedLine(id interface{}, wline example.Line) line {
+	l := newLine(id, wline.Created, wline.Data)
+	if wline.Deleted() {
+		deletedAT := wline.DeletedAt
+		l.DeletedAT = &deletedAT
+	}
+
+	return l
+}
+
 func (l *line) registerLine() *example.Line {
 	if l == nil {
 		return nil
 	}
 
-	return &example.Line{
-		ID:      Identifier(l.ID),
+	registered := &example.Line{
+		ID:      lineIdentifier(l.ID),
 		Created: l.CreatedAT,
 		Data:    l.Data,
 	}
+
+	if l.DeletedAT != nil {
+		registered.DeletedAt = *l.DeletedAT
+	}
+
+	return registered
 }
 
-func (s store) Write(ctx context.Context, wline example.Line) error {
+// withTimeout bounds ctx with the store timeout, when there is one.
+func (s store) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
 	if ctx == nil {
 		ctx = s.ctx
 	}
 
-	if id, is := wline.ID.(Identifier); !is {
-		return ErrIdentifyer
-	} else {
-		return s.write(ctx, newLine(id.GetObjectID(), wline.Created, wline.Data))
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	timeout := s.timeout.get()
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// ApplyConfig validates the mongodb config read from r and applies its
+// timeout, the rest of it is only read on connect.
+func (s store) ApplyConfig(r io.Reader) error {
+	cnf, err := parseConfig(r)
+	if err != nil {
+		return err
+	}
+
+	s.timeout.set(cnf.Timeout())
+
+	return nil
+}
+
+// storeError wraps err with the domain timeout when the operation ran out of
+// time, the domain duplicate for a taken identifier, and with fallback
+// otherwise.
+func storeError(err error, fallback error) error {
+	if errors.Is(err, context.DeadlineExceeded) || mongo.IsTimeout(err) {
+		return fmt.Errorf("%s: %w", err.Error(), example.ErrTimeout)
+	}
+
+	if mongo.IsNetworkError(err) {
+		return fmt.Errorf("%s: %w", err.Error(), example.ErrUnavailable)
+	}
+
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("%s: %w", err.Error(), example.ErrDuplicate)
+	}
+
+	return fmt.Errorf("%s: %w", err.Error(), fallback)
+}
+
+func (s store) Write(ctx context.Context, wline example.Line) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	id, err := documentID(wline.ID)
+	if err != nil {
+		return err
 	}
+
+	return s.write(ctx, storedLine(id, wline))
 }
 
 func (s store) write(ctx context.Context, nline line) error {
-	_, err := s.collection.InsertOne(s.ctx, nline)
+	_, err := s.collection.InsertOne(ctx, nline)
 	if err != nil {
-		err = fmt.Errorf("%s: %w", err.Error(), ErrDataInserted)
+		err = storeError(err, ErrDataInserted)
 	}
 
 	return err
 }
 
 func (s store) Read(ctx context.Context, id example.Identifier) (*example.Line, error) {
-	if ctx == nil {
-		ctx = s.ctx
-	}
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
 
-	if id, is := id.(Identifier); !is {
-		return nil, ErrIdentifyer
-	} else {
-		r, err := s.read(ctx, id.GetObjectID())
-		return r, err
+	key, err := documentID(id)
+	if err != nil {
+		return nil, err
 	}
+
+	return s.read(ctx, key)
 }
 
-func (s store) read(ctx context.Context, id primitive.ObjectID) (*example.Line, error) {
+func (s store) read(ctx context.Context, id interface{}) (*example.Line, error) {
 	payload := new(line)
 	filter := bson.D{{Key: "_id", Value: id}}
 
@@ -213,8 +534,460 @@ func (s store) read(ctx context.Context, id primitive.ObjectID) (*example.Line,
 			return nil, nil
 		}
 
-		return nil, fmt.Errorf("%s: %w", err.Error(), ErrMongoSystem)
+		return nil, storeError(err, ErrMongoSystem)
 	}
 
 	return payload.registerLine(), nil
 }
+
+// WriteMany inserts the lines with a single unordered InsertMany, so a line
+// failing does not stop the others from being written.
+func (s store) WriteMany(ctx context.Context, wlines []example.Line) []error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	errs := make([]error, len(wlines))
+	documents := make([]interface{}, 0, len(wlines))
+	positions := make([]int, 0, len(wlines))
+
+	for i, wline := range wlines {
+		id, err := documentID(wline.ID)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		documents = append(documents, storedLine(id, wline))
+		positions = append(positions, i)
+	}
+
+	if len(documents) == 0 {
+		return errs
+	}
+
+	_, err := s.collection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+	if err == nil {
+		return errs
+	}
+
+	var bwe mongo.BulkWriteException
+	if errors.As(err, &bwe) && bwe.WriteConcernError == nil && len(bwe.WriteErrors) > 0 {
+		for _, we := range bwe.WriteErrors {
+			if we.Index >= 0 && we.Index < len(positions) {
+				errs[positions[we.Index]] = storeError(we, ErrDataInserted)
+			}
+		}
+
+		return errs
+	}
+
+	for _, i := range positions {
+		errs[i] = storeError(err, ErrDataInserted)
+	}
+
+	return errs
+}
+
+// ReadMany fetches the lines with a single $in query.
+func (s store) ReadMany(ctx context.Context, ids []example.Identifier) ([]*example.Line, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	keys := make([]interface{}, len(ids))
+	for i, id := range ids {
+		key, err := documentID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		keys[i] = key
+	}
+
+	return s.readMany(ctx, keys)
+}
+
+func (s store) readMany(ctx context.Context, ids []interface{}) ([]*example.Line, error) {
+	filter := bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}}
+
+	cursor, err := s.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, storeError(err, ErrMongoSystem)
+	}
+
+	var payload []line
+	if err = cursor.All(ctx, &payload); err != nil {
+		return nil, storeError(err, ErrMongoSystem)
+	}
+
+	found := make(map[interface{}]*line, len(payload))
+	for i := range payload {
+		found[payload[i].ID] = &payload[i]
+	}
+
+	lines := make([]*example.Line, len(ids))
+	for i, id := range ids {
+		lines[i] = found[id].registerLine()
+	}
+
+	return lines, nil
+}
+
+// Scan walks the collection in _id order, fetching scanBatchSize lines per
+// round trip. The store timeout bounds every round trip, not the whole scan.
+func (s store) Scan(ctx context.Context) (example.LineCursor, error) {
+	queryCtx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetBatchSize(scanBatchSize)
+
+	cur, err := s.collection.Find(queryCtx, bson.D{}, opts)
+	if err != nil {
+		return nil, storeError(err, ErrMongoSystem)
+	}
+
+	return &cursor{store: s, cursor: cur}, nil
+}
+
+type cursor struct {
+	store   store
+	cursor  *mongo.Cursor
+	current example.Line
+	err     error
+}
+
+func (c *cursor) Next(ctx context.Context) bool {
+	if c.err != nil {
+		return false
+	}
+
+	ctx, cancel := c.store.withTimeout(ctx)
+	defer cancel()
+
+	if !c.cursor.Next(ctx) {
+		if err := c.cursor.Err(); err != nil {
+			c.err = storeError(err, ErrMongoSystem)
+		}
+
+		return false
+	}
+
+	payload := new(line)
+	if err := c.cursor.Decode(payload); err != nil {
+		c.err = storeError(err, ErrMongoSystem)
+		return false
+	}
+
+	c.current = *payload.registerLine()
+
+	return true
+}
+
+func (c *cursor) Line() example.Line {
+	return c.current
+}
+
+func (c *cursor) Err() error {
+	return c.err
+}
+
+func (c *cursor) Close(ctx context.Context) error {
+	ctx, cancel := c.store.withTimeout(ctx)
+	defer cancel()
+
+	return c.cursor.Close(ctx)
+}
+
+// Delete sets deleted_at with an update pipeline, so an earlier deletion time
+// is kept without a read first.
+func (s store) Delete(ctx context.Context, id example.Identifier, at time.Time) (bool, error) {
+	deletedAT := bson.D{{Key: "$ifNull", Value: bson.A{"$deleted_at", at}}}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.D{{Key: "deleted_at", Value: deletedAT}}}}}
+
+	return s.update(ctx, id, update)
+}
+
+func (s store) Restore(ctx context.Context, id example.Identifier) (bool, error) {
+	update := bson.D{{Key: "$unset", Value: bson.D{{Key: "deleted_at", Value: ""}}}}
+
+	return s.update(ctx, id, update)
+}
+
+func (s store) update(ctx context.Context, id example.Identifier, update interface{}) (bool, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	key, err := documentID(id)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := s.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: key}}, update)
+	if err != nil {
+		return false, storeError(err, ErrDataUpdated)
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+// Purge finds the lines to remove and deletes them by identifier, still
+// deleted before the time. When fewer are deleted than found, some were
+// restored meanwhile, and the lines left in the collection are not purged.
+func (s store) Purge(ctx context.Context, before time.Time) ([]example.Line, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	expired := bson.E{Key: "deleted_at", Value: bson.D{{Key: "$lt", Value: before}}}
+
+	candidates, err := s.findLines(ctx, bson.D{expired})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	ids := make(bson.A, len(candidates))
+	for i := range candidates {
+		ids[i] = candidates[i].ID
+	}
+
+	byID := bson.E{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}
+
+	result, err := s.collection.DeleteMany(ctx, bson.D{byID, expired})
+	if err != nil {
+		return nil, storeError(err, ErrDataDeleted)
+	}
+
+	if result.DeletedCount == int64(len(candidates)) {
+		return registerLines(candidates), nil
+	}
+
+	left, err := s.findLines(ctx, bson.D{byID})
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make(map[interface{}]bool, len(left))
+	for _, l := range left {
+		kept[l.ID] = true
+	}
+
+	purged := make([]example.Line, 0, len(candidates)-len(left))
+	for i := range candidates {
+		if !kept[candidates[i].ID] {
+			purged = append(purged, *candidates[i].registerLine())
+		}
+	}
+
+	return purged, nil
+}
+
+func (s store) findLines(ctx context.Context, filter bson.D) ([]line, error) {
+	cursor, err := s.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, storeError(err, ErrMongoSystem)
+	}
+
+	var payload []line
+	if err = cursor.All(ctx, &payload); err != nil {
+		return nil, storeError(err, ErrMongoSystem)
+	}
+
+	return payload, nil
+}
+
+func registerLines(payload []line) []example.Line {
+	lines := make([]example.Line, len(payload))
+	for i := range payload {
+		lines[i] = *payload[i].registerLine()
+	}
+
+	return lines
+}
+
+// Find translates the specification into a range over the created_at index,
+// the data prefix into an anchored regular expression.
+func (s store) Find(ctx context.Context, spec example.LineSpecification) ([]example.Line, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	cursor, err := s.collection.Find(ctx, specFilter(spec), specOptions(spec))
+	if err != nil {
+		return nil, storeError(err, ErrMongoSystem)
+	}
+
+	var payload []line
+	if err = cursor.All(ctx, &payload); err != nil {
+		return nil, storeError(err, ErrMongoSystem)
+	}
+
+	return registerLines(payload), nil
+}
+
+// liveFilter matches the lines not deleted, deleted_at being either missing
+// or null.
+var liveFilter = bson.E{Key: "deleted_at", Value: nil}
+
+func specFilter(spec example.LineSpecification) bson.D {
+	filter := bson.D{}
+	if !spec.IncludeDeleted {
+		filter = append(filter, liveFilter)
+	}
+
+	created := bson.D{}
+	if !spec.CreatedAfter.IsZero() {
+		created = append(created, bson.E{Key: "$gte", Value: spec.CreatedAfter})
+	}
+	if !spec.CreatedBefore.IsZero() {
+		created = append(created, bson.E{Key: "$lt", Value: spec.CreatedBefore})
+	}
+	if len(created) > 0 {
+		filter = append(filter, bson.E{Key: "created_at", Value: created})
+	}
+
+	if spec.DataPrefix != "" {
+		filter = append(filter, bson.E{Key: "data", Value: primitive.Regex{Pattern: "^" + regexp.QuoteMeta(spec.DataPrefix)}})
+	}
+
+	return filter
+}
+
+func specOptions(spec example.LineSpecification) *options.FindOptions {
+	direction := 1
+	if spec.Sort == example.SortDescending {
+		direction = -1
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: direction}, {Key: "_id", Value: direction}})
+	if spec.Limit > 0 {
+		opts.SetLimit(int64(spec.Limit))
+	}
+
+	return opts
+}
+
+// Search runs a $text query, ranked by text score, and counts every match
+// with a second round trip.
+func (s store) Search(ctx context.Context, query example.SearchQuery) (example.SearchResult, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	filter := textFilter(query.Text)
+	if !query.IncludeDeleted {
+		filter = append(filter, liveFilter)
+	}
+	score := bson.E{Key: "score", Value: bson.D{{Key: "$meta", Value: "textScore"}}}
+
+	opts := options.Find().
+		SetProjection(bson.D{score}).
+		SetSort(bson.D{score, {Key: "_id", Value: 1}}).
+		SetSkip(int64(query.Offset))
+	if query.Limit > 0 {
+		opts.SetLimit(int64(query.Limit))
+	}
+
+	total, err := s.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return example.SearchResult{}, storeError(err, ErrMongoSystem)
+	}
+
+	cursor, err := s.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return example.SearchResult{}, storeError(err, ErrMongoSystem)
+	}
+
+	var payload []scoredLine
+	if err = cursor.All(ctx, &payload); err != nil {
+		return example.SearchResult{}, storeError(err, ErrMongoSystem)
+	}
+
+	result := example.SearchResult{
+		Hits:  make([]example.SearchHit, len(payload)),
+		Total: total,
+	}
+	for i := range payload {
+		result.Hits[i] = example.SearchHit{
+			Line:  *payload[i].Line.registerLine(),
+			Score: payload[i].Score,
+		}
+	}
+
+	return result, nil
+}
+
+// textFilter matches the lines the way the memory store does: the $text
+// search gets the bare terms, without stemming nor stop words, and every
+// phrase is matched term by term with a regular expression, since $text
+// matches phrases as substrings.
+func textFilter(text string) bson.D {
+	filter := bson.D{{Key: "$text", Value: bson.D{
+		{Key: "$search", Value: strings.Join(example.Terms(text), " ")},
+		{Key: "$language", Value: textLanguage},
+		{Key: "$caseSensitive", Value: false},
+		{Key: "$diacriticSensitive", Value: true},
+	}}}
+
+	phrases := example.Phrases(text)
+	if len(phrases) == 0 {
+		return filter
+	}
+
+	clauses := make(bson.A, len(phrases))
+	for i, phrase := range phrases {
+		clauses[i] = bson.D{{Key: "data", Value: primitive.Regex{Pattern: phrasePattern(phrase), Options: "i"}}}
+	}
+
+	return append(filter, bson.E{Key: "$and", Value: clauses})
+}
+
+// phrasePattern matches the terms of phrase as whole words, separated by
+// anything but letters and digits.
+func phrasePattern(phrase []string) string {
+	const separator = `[^\p{L}\p{N}]`
+
+	quoted := make([]string, len(phrase))
+	for i, term := range phrase {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+
+	return `(?:^|` + separator + `)` + strings.Join(quoted, separator+`+`) + `(?:$|` + separator + `)`
+}
+
+type scoredLine struct {
+	Line  line    `bson:",inline"`
+	Score float64 `bson:"score"`
+}
+
+// Close disconnects the client, letting the operations in progress finish
+// until ctx is done.
+func (s store) Close(ctx context.Context) error {
+	if s.client == nil {
+		return nil
+	}
+
+	if err := s.client.Disconnect(ctx); err != nil {
+		return fmt.Errorf("%s: %w", err.Error(), ErrMongoSystem)
+	}
+
+	return nil
+}
+
+func (s store) Name() string {
+	return healthCheckName
+}
+
+func (s store) Check(ctx context.Context) error {
+	if s.client == nil {
+		return ErrMongoSystem
+	}
+
+	// A nil read preference pings with the configured one of the client.
+	if err := s.client.Ping(ctx, nil); err != nil {
+		return fmt.Errorf("%s: %w", err.Error(), ErrMongoSystem)
+	}
+
+	return nil
+}
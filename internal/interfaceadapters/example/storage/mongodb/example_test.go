@@ -13,64 +13,14 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 
 	"clean-arquitecture-template/internal/domain/example"
 )
 
-func Test_NewID(t *testing.T) {
-	id := NewIdentityProvider().NewID()
-
-	mid, is := id.(Identifier)
-	assert.True(t, is)
-
-	oid := mid.GetObjectID()
-	assert.NotEmpty(t, id.String())
-	assert.NotEmpty(t, mid.String())
-	assert.NotEmpty(t, oid.String())
-}
-
-// func IdentifierFromText(key string) (example.Identifier, error) {
-func Test_IdentifierFromText(t *testing.T) {
-	testCases := []struct {
-		name          string
-		key           string
-		identifier    Identifier
-		expectedError error
-	}{
-		{
-			name:          "successful-case",
-			key:           "63f39e5fb192144de70dfa58",
-			identifier:    Identifier{},
-			expectedError: nil,
-		},
-		{
-			name:          "error-case",
-			key:           "hola",
-			identifier:    Identifier{},
-			expectedError: ErrIdentifyer,
-		},
-	}
-
-	for _, c := range testCases {
-		testname := c.name
-		key := c.key
-		id := c.identifier
-		expectedError := c.expectedError
-
-		t.Run(testname, func(t *testing.T) {
-			result, err := id.ParseID(key)
-
-			if err != nil {
-				assert.ErrorIs(t, err, expectedError)
-			} else {
-				assert.Equal(t, true, ((example.Identifier)(result) == result))
-				assert.Equal(t, 24, len(result.String()))
-			}
-		})
-	}
-}
-
 func Test_RegisterLine(t *testing.T) {
 	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.FixedZone("", 2*60*60)).UTC()
 	id := primitive.NewObjectID()
@@ -350,6 +300,8 @@ func Test_ReadConfig(t *testing.T) {
 		expectedDSN          string
 		expectedDatabaseName string
 		expectedCollection   string
+		expectedAudit        string
+		expectedTimeout      time.Duration
 		expectedError        error
 	}{
 		{
@@ -387,8 +339,35 @@ func Test_ReadConfig(t *testing.T) {
 			expectedDSN:          "mongodb-dsn",
 			expectedDatabaseName: "database-name",
 			expectedCollection:   "collection-name",
+			expectedAudit:        "collection-name_audit",
+			expectedTimeout:      defaultTimeout,
+			expectedError:        nil,
+		},
+		{
+			testName: "timeout-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{
+					"dsn": "mongodb-dsn",
+					"database": "database-name",
+					"collection": "collection-name",
+					"audit-collection": "audit-name",
+					"timeout": "2s"}
+				`), nil
+			},
+			expectedDSN:          "mongodb-dsn",
+			expectedDatabaseName: "database-name",
+			expectedCollection:   "collection-name",
+			expectedAudit:        "audit-name",
+			expectedTimeout:      2 * time.Second,
 			expectedError:        nil,
 		},
+		{
+			testName: "invalid-timeout-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"timeout": "two seconds"}`), nil
+			},
+			expectedError: ErrReadConfig,
+		},
 	}
 
 	for _, c := range testCases {
@@ -396,6 +375,8 @@ func Test_ReadConfig(t *testing.T) {
 		expectedDSN := c.expectedDSN
 		expectedDBName := c.expectedDatabaseName
 		expectedCollection := c.expectedCollection
+		expectedAudit := c.expectedAudit
+		expectedTimeout := c.expectedTimeout
 		expectedError := c.expectedError
 		readerMock := configReaderMock{
 			c.buildConfigReader,
@@ -411,8 +392,1058 @@ func Test_ReadConfig(t *testing.T) {
 				assert.Equal(t, expectedDSN, cnf.DSN())
 				assert.Equal(t, expectedDBName, cnf.Database())
 				assert.Equal(t, expectedCollection, cnf.Collection())
+				assert.Equal(t, expectedAudit, cnf.AuditCollection())
+				assert.Equal(t, expectedTimeout, cnf.Timeout())
 				assert.Equal(t, expectedError, err)
 			}
 		})
 	}
 }
+
+// clientMock fails the pings with a read preference overriding the one of the
+// client.
+type clientMock struct {
+	err           error
+	disconnectErr error
+}
+
+func (cm clientMock) Ping(ctx context.Context, rp *readpref.ReadPref) error {
+	if rp != nil {
+		return errors.New("read preference overridden")
+	}
+
+	return cm.err
+}
+
+func (cm clientMock) Disconnect(ctx context.Context) error {
+	return cm.disconnectErr
+}
+
+func Test_ApplyConfig(t *testing.T) {
+	testCases := []struct {
+		testName        string
+		content         string
+		expectedTimeout time.Duration
+		expectedError   error
+	}{
+		{
+			testName:        "timeout-case",
+			content:         `{"timeout": "2s"}`,
+			expectedTimeout: 2 * time.Second,
+		},
+		{
+			testName:        "default-timeout-case",
+			content:         `{}`,
+			expectedTimeout: defaultTimeout,
+		},
+		{
+			testName:        "invalid-timeout-case",
+			content:         `{"timeout": "two seconds"}`,
+			expectedTimeout: time.Minute,
+			expectedError:   ErrReadConfig,
+		},
+	}
+
+	for _, c := range testCases {
+		content := c.content
+		expectedTimeout := c.expectedTimeout
+		expectedError := c.expectedError
+
+		t.Run(c.testName, func(t *testing.T) {
+			timeout := newOpTimeout(time.Minute)
+			st := store{timeout: timeout, audit: auditLog{timeout: timeout}}
+
+			err := st.ApplyConfig(strings.NewReader(content))
+
+			assert.ErrorIs(t, err, expectedError)
+			assert.Equal(t, expectedTimeout, st.timeout.get())
+			assert.Equal(t, expectedTimeout, st.audit.timeout.get())
+		})
+	}
+}
+
+func Test_Check(t *testing.T) {
+	testCases := []struct {
+		testName      string
+		client        mongoClient
+		expectedError error
+	}{
+		{
+			testName:      "nil-client-case",
+			client:        nil,
+			expectedError: ErrMongoSystem,
+		},
+		{
+			testName:      "ping-error-case",
+			client:        clientMock{err: errors.New("server selection error")},
+			expectedError: ErrMongoSystem,
+		},
+		{
+			testName:      "success-case",
+			client:        clientMock{},
+			expectedError: nil,
+		},
+	}
+
+	for _, c := range testCases {
+		name := c.testName
+		client := c.client
+		expectedError := c.expectedError
+
+		t.Run(name, func(t *testing.T) {
+			st := store{client: client}
+
+			err := st.Check(context.Background())
+
+			assert.Equal(t, healthCheckName, st.Name())
+			assert.ErrorIs(t, err, expectedError)
+		})
+	}
+}
+
+func Test_NewExampleRepo(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testCases := []struct {
+		testName string
+		ctx      context.Context
+		cnf      config
+	}{
+		{
+			testName: "invalid-dsn-case",
+			ctx:      context.Background(),
+			cnf:      config{Dsn: "not-a-dsn"},
+		},
+		{
+			testName: "unreachable-server-case",
+			ctx:      context.Background(),
+			cnf:      config{Dsn: "mongodb://127.0.0.1:1", selectTimeout: 50 * time.Millisecond},
+		},
+		{
+			testName: "canceled-context-case",
+			ctx:      canceled,
+			cnf:      config{Dsn: "mongodb://127.0.0.1:1"},
+		},
+	}
+
+	for _, c := range testCases {
+		ctx := c.ctx
+		cnf := c.cnf
+
+		t.Run(c.testName, func(t *testing.T) {
+			_, err := NewExampleRepo(ctx, cnf)
+			assert.ErrorIs(t, err, ErrConnect)
+		})
+	}
+}
+
+func Test_Close(t *testing.T) {
+	testCases := []struct {
+		testName      string
+		client        mongoClient
+		expectedError error
+	}{
+		{
+			testName:      "nil-client-case",
+			client:        nil,
+			expectedError: nil,
+		},
+		{
+			testName:      "disconnect-error-case",
+			client:        clientMock{disconnectErr: errors.New("client is disconnected")},
+			expectedError: ErrMongoSystem,
+		},
+		{
+			testName:      "success-case",
+			client:        clientMock{},
+			expectedError: nil,
+		},
+	}
+
+	for _, c := range testCases {
+		name := c.testName
+		client := c.client
+		expectedError := c.expectedError
+
+		t.Run(name, func(t *testing.T) {
+			st := store{client: client}
+
+			err := st.Close(context.Background())
+
+			assert.ErrorIs(t, err, expectedError)
+			if expectedError == nil {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_ReadClientConfig(t *testing.T) {
+	testCases := []struct {
+		testName       string
+		config         string
+		expectedConfig func(t *testing.T, cnf Config)
+		expectedError  error
+	}{
+		{
+			testName: "defaults-case",
+			config:   `{"dsn": "mongodb://localhost"}`,
+			expectedConfig: func(t *testing.T, cnf Config) {
+				assert.Zero(t, cnf.MaxPoolSize())
+				assert.Zero(t, cnf.MinPoolSize())
+				assert.Zero(t, cnf.ServerSelectionTimeout())
+				assert.Zero(t, cnf.ConnectTimeout())
+				assert.True(t, cnf.RetryWrites())
+				assert.Nil(t, cnf.ReadPreference())
+				assert.Nil(t, cnf.WriteConcern())
+				assert.False(t, cnf.TLS())
+			},
+		},
+		{
+			testName: "every-option-case",
+			config: `{
+				"dsn": "mongodb://localhost",
+				"max-pool-size": 50,
+				"min-pool-size": 5,
+				"server-selection-timeout": "3s",
+				"connect-timeout": "2s",
+				"retry-writes": false,
+				"read-preference": "secondaryPreferred",
+				"write-concern": "majority",
+				"tls": true}`,
+			expectedConfig: func(t *testing.T, cnf Config) {
+				assert.Equal(t, uint64(50), cnf.MaxPoolSize())
+				assert.Equal(t, uint64(5), cnf.MinPoolSize())
+				assert.Equal(t, 3*time.Second, cnf.ServerSelectionTimeout())
+				assert.Equal(t, 2*time.Second, cnf.ConnectTimeout())
+				assert.False(t, cnf.RetryWrites())
+				assert.Equal(t, readpref.SecondaryPreferredMode, cnf.ReadPreference().Mode())
+				assert.Equal(t, "majority", cnf.WriteConcern().GetW())
+				assert.True(t, cnf.TLS())
+			},
+		},
+		{
+			testName: "numeric-write-concern-case",
+			config:   `{"write-concern": "2"}`,
+			expectedConfig: func(t *testing.T, cnf Config) {
+				assert.Equal(t, 2, cnf.WriteConcern().GetW())
+			},
+		},
+		{
+			testName:      "invalid-selection-timeout-case",
+			config:        `{"server-selection-timeout": "soon"}`,
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName:      "invalid-connect-timeout-case",
+			config:        `{"connect-timeout": "soon"}`,
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName:      "min-over-max-pool-case",
+			config:        `{"max-pool-size": 5, "min-pool-size": 10}`,
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName:      "negative-pool-case",
+			config:        `{"max-pool-size": -1}`,
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName:      "invalid-read-preference-case",
+			config:        `{"read-preference": "anywhere"}`,
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName:      "invalid-write-concern-case",
+			config:        `{"write-concern": "everyone"}`,
+			expectedError: ErrReadConfig,
+		},
+	}
+
+	for _, c := range testCases {
+		name := c.testName
+		config := c.config
+		expectedConfig := c.expectedConfig
+		expectedError := c.expectedError
+
+		t.Run(name, func(t *testing.T) {
+			cnf, err := ReadConfig(configReaderMock{
+				f: func(node string) (io.Reader, error) {
+					return strings.NewReader(config), nil
+				},
+			})
+
+			if expectedError != nil {
+				assert.Nil(t, cnf)
+				assert.ErrorIs(t, err, expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			expectedConfig(t, cnf)
+		})
+	}
+}
+
+func Test_ClientOptions(t *testing.T) {
+	retry := false
+	wc := writeconcern.New(writeconcern.WMajority())
+
+	opts := clientOptions(config{
+		Dsn:            "mongodb://localhost/?maxPoolSize=10&retryWrites=true",
+		MaxPool:        50,
+		MinPool:        5,
+		Retry:          &retry,
+		UseTLS:         true,
+		selectTimeout:  3 * time.Second,
+		connectTimeout: 2 * time.Second,
+		readPref:       readpref.Nearest(),
+		writeConcern:   wc,
+	})
+
+	require.NoError(t, opts.Validate())
+	assert.Equal(t, uint64(50), *opts.MaxPoolSize, "the config wins over the DSN")
+	assert.Equal(t, uint64(5), *opts.MinPoolSize)
+	assert.Equal(t, 3*time.Second, *opts.ServerSelectionTimeout)
+	assert.Equal(t, 2*time.Second, *opts.ConnectTimeout)
+	assert.False(t, *opts.RetryWrites)
+	assert.Equal(t, readpref.NearestMode, opts.ReadPreference.Mode())
+	assert.Equal(t, wc, opts.WriteConcern)
+	require.NotNil(t, opts.TLSConfig)
+
+	opts = clientOptions(config{Dsn: "mongodb://localhost/?maxPoolSize=10"})
+
+	require.NoError(t, opts.Validate())
+	assert.Equal(t, uint64(10), *opts.MaxPoolSize, "the DSN is kept when the config leaves it")
+	assert.True(t, *opts.RetryWrites)
+	assert.Nil(t, opts.TLSConfig)
+}
+
+func Test_StoreError(t *testing.T) {
+	testCases := []struct {
+		testName      string
+		err           error
+		expectedError error
+	}{
+		{
+			testName:      "deadline-exceeded-case",
+			err:           fmt.Errorf("server selection: %w", context.DeadlineExceeded),
+			expectedError: example.ErrTimeout,
+		},
+		{
+			testName:      "network-error-case",
+			err:           mongo.CommandError{Message: "connection reset", Labels: []string{"NetworkError"}},
+			expectedError: example.ErrUnavailable,
+		},
+		{
+			testName:      "other-error-case",
+			err:           errors.New("duplicate key"),
+			expectedError: ErrDataInserted,
+		},
+	}
+
+	for _, c := range testCases {
+		name := c.testName
+		err := c.err
+		expectedError := c.expectedError
+
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, storeError(err, ErrDataInserted), expectedError)
+		})
+	}
+}
+
+func Test_WriteMany(t *testing.T) {
+	tstamp := time.Now()
+	first := Identifier(primitive.NewObjectID())
+	second := Identifier(primitive.NewObjectID())
+
+	testCases := []struct {
+		name           string
+		input          []example.Line
+		mongoRes       []bson.D
+		expectedErrors []error
+	}{
+		{
+			name: "success-case",
+			input: []example.Line{
+				{ID: first, Created: tstamp, Data: "first-line"},
+				{ID: second, Created: tstamp, Data: "second-line"},
+			},
+			mongoRes:       []bson.D{mtest.CreateSuccessResponse()},
+			expectedErrors: []error{nil, nil},
+		},
+		{
+			name: "error-id-case",
+			input: []example.Line{
+				{ID: nil, Created: tstamp, Data: "first-line"},
+				{ID: second, Created: tstamp, Data: "second-line"},
+			},
+			mongoRes:       []bson.D{mtest.CreateSuccessResponse()},
+			expectedErrors: []error{ErrIdentifyer, nil},
+		},
+		{
+			name: "only-invalid-ids-case",
+			input: []example.Line{
+				{ID: nil, Created: tstamp, Data: "first-line"},
+			},
+			expectedErrors: []error{ErrIdentifyer},
+		},
+		{
+			name: "item-write-error-case",
+			input: []example.Line{
+				{ID: nil, Created: tstamp, Data: "first-line"},
+				{ID: first, Created: tstamp, Data: "second-line"},
+				{ID: second, Created: tstamp, Data: "third-line"},
+			},
+			mongoRes: []bson.D{mtest.CreateWriteErrorsResponse(mtest.WriteError{
+				Index:   1,
+				Code:    11000,
+				Message: "duplicate key",
+			})},
+			expectedErrors: []error{ErrIdentifyer, nil, example.ErrDuplicate},
+		},
+		{
+			name: "command-error-case",
+			input: []example.Line{
+				{ID: first, Created: tstamp, Data: "first-line"},
+				{ID: second, Created: tstamp, Data: "second-line"},
+			},
+			mongoRes: []bson.D{mtest.CreateCommandErrorResponse(mtest.CommandError{
+				Code:    1,
+				Message: "database general error",
+				Name:    "database general error",
+			})},
+			expectedErrors: []error{ErrDataInserted, ErrDataInserted},
+		},
+	}
+
+	for _, c := range testCases {
+		testName := c.name
+		input := c.input
+		mongoRes := c.mongoRes
+		expectedErrors := c.expectedErrors
+
+		mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+		defer mt.Close()
+
+		mt.Run(testName, func(mt *mtest.T) {
+			mt.AddMockResponses(mongoRes...)
+
+			st := store{
+				ctx:        context.Background(),
+				collection: mt.Coll,
+			}
+
+			errs := st.WriteMany(context.Background(), input)
+
+			require.Len(t, errs, len(expectedErrors))
+			for i, expectedError := range expectedErrors {
+				if expectedError == nil {
+					assert.NoError(t, errs[i])
+				} else {
+					assert.ErrorIs(t, errs[i], expectedError)
+				}
+			}
+		})
+	}
+}
+
+func Test_ReadMany(t *testing.T) {
+	first := Identifier(primitive.NewObjectID())
+	second := Identifier(primitive.NewObjectID())
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.FixedZone("", 2*60*60)).UTC()
+	ns := fmt.Sprintf("%s.%s", "dbname", "lines")
+
+	document := func(mt *mtest.T, l line) bson.D {
+		bsonData, err := bson.Marshal(l)
+		require.NoError(mt, err)
+
+		var bsonD bson.D
+		require.NoError(mt, bson.Unmarshal(bsonData, &bsonD))
+
+		return bsonD
+	}
+
+	testCases := []struct {
+		testName       string
+		ids            []example.Identifier
+		expectedResult []*example.Line
+		expectedError  error
+		prepMongoMock  func(mt *mtest.T)
+	}{
+		{
+			testName:      "identifier-error-case",
+			ids:           []example.Identifier{first, nil},
+			expectedError: ErrIdentifyer,
+		},
+		{
+			testName:      "mongodb-error-case",
+			ids:           []example.Identifier{first},
+			expectedError: ErrMongoSystem,
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+					Code:    1,
+					Message: "database general error",
+					Name:    "database general error",
+				}))
+			},
+		},
+		{
+			testName: "found-and-missing-case",
+			ids:      []example.Identifier{second, first},
+			expectedResult: []*example.Line{
+				nil,
+				{ID: first, Created: tstamp, Data: "first-line"},
+			},
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(1, ns, mtest.FirstBatch, document(mt, newLine(first.GetObjectID(), tstamp, "first-line"))),
+					mtest.CreateCursorResponse(0, ns, mtest.NextBatch),
+				)
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		testName := c.testName
+		ids := c.ids
+		expectedResult := c.expectedResult
+		expectedError := c.expectedError
+		prepMongoMock := c.prepMongoMock
+
+		mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+		defer mt.Close()
+
+		mt.Run(testName, func(mt *mtest.T) {
+			if prepMongoMock != nil {
+				prepMongoMock(mt)
+			}
+
+			st := store{
+				ctx:        context.Background(),
+				collection: mt.Coll,
+			}
+
+			result, err := st.ReadMany(context.Background(), ids)
+
+			assert.Equal(t, expectedResult, result)
+			assert.ErrorIs(t, err, expectedError)
+		})
+	}
+}
+
+func Test_Scan(t *testing.T) {
+	first := Identifier(primitive.NewObjectID())
+	second := Identifier(primitive.NewObjectID())
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+	ns := fmt.Sprintf("%s.%s", "dbname", "lines")
+
+	document := func(mt *mtest.T, l line) bson.D {
+		bsonData, err := bson.Marshal(l)
+		require.NoError(mt, err)
+
+		var bsonD bson.D
+		require.NoError(mt, bson.Unmarshal(bsonData, &bsonD))
+
+		return bsonD
+	}
+
+	commandError := mtest.CreateCommandErrorResponse(mtest.CommandError{
+		Code:    1,
+		Message: "database general error",
+		Name:    "database general error",
+	})
+
+	testCases := []struct {
+		testName          string
+		expectedLines     []example.Line
+		expectedScanError error
+		expectedError     error
+		prepMongoMock     func(mt *mtest.T)
+	}{
+		{
+			testName:          "find-error-case",
+			expectedScanError: ErrMongoSystem,
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(commandError)
+			},
+		},
+		{
+			testName: "batches-case",
+			expectedLines: []example.Line{
+				{ID: first, Created: tstamp, Data: "first-line"},
+				{ID: second, Created: tstamp, Data: "second-line"},
+			},
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(1, ns, mtest.FirstBatch, document(mt, newLine(first.GetObjectID(), tstamp, "first-line"))),
+					mtest.CreateCursorResponse(1, ns, mtest.NextBatch, document(mt, newLine(second.GetObjectID(), tstamp, "second-line"))),
+					mtest.CreateCursorResponse(0, ns, mtest.NextBatch),
+				)
+			},
+		},
+		{
+			testName:      "get-more-error-case",
+			expectedLines: []example.Line{{ID: first, Created: tstamp, Data: "first-line"}},
+			expectedError: ErrMongoSystem,
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(1, ns, mtest.FirstBatch, document(mt, newLine(first.GetObjectID(), tstamp, "first-line"))),
+					commandError,
+					mtest.CreateSuccessResponse(),
+				)
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		testName := c.testName
+		expectedLines := c.expectedLines
+		expectedScanError := c.expectedScanError
+		expectedError := c.expectedError
+		prepMongoMock := c.prepMongoMock
+
+		mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+		defer mt.Close()
+
+		mt.Run(testName, func(mt *mtest.T) {
+			prepMongoMock(mt)
+
+			st := store{
+				ctx:        context.Background(),
+				collection: mt.Coll,
+			}
+
+			cur, err := st.Scan(context.Background())
+			assert.ErrorIs(t, err, expectedScanError)
+			if expectedScanError != nil {
+				return
+			}
+
+			var lines []example.Line
+			for cur.Next(context.Background()) {
+				lines = append(lines, cur.Line())
+			}
+
+			assert.Equal(t, expectedLines, lines)
+			assert.ErrorIs(t, cur.Err(), expectedError)
+			assert.NoError(t, cur.Close(context.Background()))
+		})
+	}
+}
+
+func Test_Search(t *testing.T) {
+	first := Identifier(primitive.NewObjectID())
+	second := Identifier(primitive.NewObjectID())
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+	ns := fmt.Sprintf("%s.%s", "dbname", "lines")
+
+	document := func(mt *mtest.T, l line, score float64) bson.D {
+		bsonData, err := bson.Marshal(scoredLine{Line: l, Score: score})
+		require.NoError(mt, err)
+
+		var bsonD bson.D
+		require.NoError(mt, bson.Unmarshal(bsonData, &bsonD))
+
+		return bsonD
+	}
+
+	count := func(n int32) bson.D {
+		return mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: n}})
+	}
+
+	commandError := mtest.CreateCommandErrorResponse(mtest.CommandError{
+		Code:    1,
+		Message: "database general error",
+		Name:    "database general error",
+	})
+
+	testCases := []struct {
+		testName       string
+		expectedResult example.SearchResult
+		expectedError  error
+		prepMongoMock  func(mt *mtest.T)
+	}{
+		{
+			testName: "hits-case",
+			expectedResult: example.SearchResult{
+				Hits: []example.SearchHit{
+					{Line: example.Line{ID: second, Created: tstamp, Data: "fox fox"}, Score: 1.5},
+					{Line: example.Line{ID: first, Created: tstamp, Data: "fox"}, Score: 1},
+				},
+				Total: 3,
+			},
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					count(3),
+					mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+						document(mt, newLine(second.GetObjectID(), tstamp, "fox fox"), 1.5),
+						document(mt, newLine(first.GetObjectID(), tstamp, "fox"), 1),
+					),
+				)
+			},
+		},
+		{
+			testName:       "count-error-case",
+			expectedResult: example.SearchResult{},
+			expectedError:  ErrMongoSystem,
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(commandError)
+			},
+		},
+		{
+			testName:       "find-error-case",
+			expectedResult: example.SearchResult{},
+			expectedError:  ErrMongoSystem,
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(count(1), commandError)
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		testName := c.testName
+		expectedResult := c.expectedResult
+		expectedError := c.expectedError
+		prepMongoMock := c.prepMongoMock
+
+		mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+		defer mt.Close()
+
+		mt.Run(testName, func(mt *mtest.T) {
+			prepMongoMock(mt)
+
+			st := store{
+				ctx:        context.Background(),
+				collection: mt.Coll,
+			}
+
+			result, err := st.Search(context.Background(), example.SearchQuery{Text: "fox", Limit: 2})
+
+			assert.Equal(t, expectedResult, result)
+			assert.ErrorIs(t, err, expectedError)
+		})
+	}
+}
+
+func Test_Find(t *testing.T) {
+	first := Identifier(primitive.NewObjectID())
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+	ns := fmt.Sprintf("%s.%s", "dbname", "lines")
+
+	document := func(mt *mtest.T, l line) bson.D {
+		bsonData, err := bson.Marshal(l)
+		require.NoError(mt, err)
+
+		var bsonD bson.D
+		require.NoError(mt, bson.Unmarshal(bsonData, &bsonD))
+
+		return bsonD
+	}
+
+	testCases := []struct {
+		testName       string
+		expectedResult []example.Line
+		expectedError  error
+		prepMongoMock  func(mt *mtest.T)
+	}{
+		{
+			testName:       "lines-case",
+			expectedResult: []example.Line{{ID: first, Created: tstamp, Data: "first-line"}},
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, document(mt, newLine(first.GetObjectID(), tstamp, "first-line"))))
+			},
+		},
+		{
+			testName:       "empty-case",
+			expectedResult: []example.Line{},
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+			},
+		},
+		{
+			testName:      "mongodb-error-case",
+			expectedError: ErrMongoSystem,
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+					Code:    1,
+					Message: "database general error",
+					Name:    "database general error",
+				}))
+			},
+		},
+	}
+
+	for _, c := range testCases {
+		testName := c.testName
+		expectedResult := c.expectedResult
+		expectedError := c.expectedError
+		prepMongoMock := c.prepMongoMock
+
+		mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+		defer mt.Close()
+
+		mt.Run(testName, func(mt *mtest.T) {
+			prepMongoMock(mt)
+
+			st := store{
+				ctx:        context.Background(),
+				collection: mt.Coll,
+			}
+
+			result, err := st.Find(context.Background(), example.LineSpecification{DataPrefix: "first"})
+
+			assert.Equal(t, expectedResult, result)
+			assert.ErrorIs(t, err, expectedError)
+		})
+	}
+}
+
+func Test_SpecFilter(t *testing.T) {
+	after := time.Date(2018, time.September, 16, 0, 0, 0, 0, time.UTC)
+	before := after.Add(24 * time.Hour)
+
+	testCases := []struct {
+		testName         string
+		spec             example.LineSpecification
+		expectedFilter   bson.D
+		expectedSort     bson.D
+		expectedLimitSet bool
+	}{
+		{
+			testName:       "empty-case",
+			spec:           example.LineSpecification{},
+			expectedFilter: bson.D{{Key: "deleted_at", Value: nil}},
+			expectedSort:   bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}},
+		},
+		{
+			testName: "full-case",
+			spec: example.LineSpecification{
+				CreatedAfter:   after,
+				CreatedBefore:  before,
+				DataPrefix:     "a.b",
+				Sort:           example.SortDescending,
+				Limit:          10,
+				IncludeDeleted: true,
+			},
+			expectedFilter: bson.D{
+				{Key: "created_at", Value: bson.D{{Key: "$gte", Value: after}, {Key: "$lt", Value: before}}},
+				{Key: "data", Value: primitive.Regex{Pattern: `^a\.b`}},
+			},
+			expectedSort:     bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			expectedLimitSet: true,
+		},
+	}
+
+	for _, c := range testCases {
+		spec := c.spec
+		expectedFilter := c.expectedFilter
+		expectedSort := c.expectedSort
+		expectedLimitSet := c.expectedLimitSet
+
+		t.Run(c.testName, func(t *testing.T) {
+			opts := specOptions(spec)
+
+			assert.Equal(t, expectedFilter, specFilter(spec))
+			assert.Equal(t, expectedSort, opts.Sort)
+			assert.Equal(t, expectedLimitSet, opts.Limit != nil)
+		})
+	}
+}
+
+func Test_DeleteRestore(t *testing.T) {
+	id := Identifier(primitive.NewObjectID())
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+
+	updated := func(n int32) bson.D {
+		return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: n}, bson.E{Key: "nModified", Value: n})
+	}
+
+	testCases := []struct {
+		testName      string
+		id            example.Identifier
+		restore       bool
+		mongoRes      bson.D
+		expectedFound bool
+		expectedError error
+	}{
+		{
+			testName:      "delete-case",
+			id:            id,
+			mongoRes:      updated(1),
+			expectedFound: true,
+		},
+		{
+			testName: "delete-not-found-case",
+			id:       id,
+			mongoRes: updated(0),
+		},
+		{
+			testName:      "restore-case",
+			id:            id,
+			restore:       true,
+			mongoRes:      updated(1),
+			expectedFound: true,
+		},
+		{
+			testName:      "error-id-case",
+			id:            example.MockIdentifier(""),
+			mongoRes:      updated(1),
+			expectedError: ErrIdentifyer,
+		},
+		{
+			testName: "mongodb-error-case",
+			id:       id,
+			restore:  true,
+			mongoRes: mtest.CreateCommandErrorResponse(mtest.CommandError{
+				Code:    1,
+				Message: "database general error",
+				Name:    "database general error",
+			}),
+			expectedError: ErrDataUpdated,
+		},
+	}
+
+	for _, c := range testCases {
+		testName := c.testName
+		id := c.id
+		restore := c.restore
+		mongoRes := c.mongoRes
+		expectedFound := c.expectedFound
+		expectedError := c.expectedError
+
+		mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+		defer mt.Close()
+
+		mt.Run(testName, func(mt *mtest.T) {
+			mt.AddMockResponses(mongoRes)
+
+			st := store{
+				ctx:        context.Background(),
+				collection: mt.Coll,
+			}
+
+			var found bool
+			var err error
+			if restore {
+				found, err = st.Restore(context.Background(), id)
+			} else {
+				found, err = st.Delete(context.Background(), id, tstamp)
+			}
+
+			assert.Equal(t, expectedFound, found)
+			assert.ErrorIs(t, err, expectedError)
+		})
+	}
+}
+
+func Test_Purge(t *testing.T) {
+	first := Identifier(primitive.NewObjectID())
+	second := Identifier(primitive.NewObjectID())
+	tstamp := time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+	ns := fmt.Sprintf("%s.%s", "dbname", "lines")
+
+	document := func(mt *mtest.T, id Identifier) bson.D {
+		l := newLine(id.GetObjectID(), tstamp, "deleted-line")
+		l.DeletedAT = &tstamp
+
+		bsonData, err := bson.Marshal(l)
+		require.NoError(mt, err)
+
+		var bsonD bson.D
+		require.NoError(mt, bson.Unmarshal(bsonData, &bsonD))
+
+		return bsonD
+	}
+
+	commandError := mtest.CreateCommandErrorResponse(mtest.CommandError{
+		Code:    1,
+		Message: "database general error",
+		Name:    "database general error",
+	})
+
+	testCases := []struct {
+		testName       string
+		prepMongoMock  func(mt *mtest.T)
+		expectedPurged []example.Identifier
+		expectedError  error
+	}{
+		{
+			testName: "purge-case",
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, document(mt, first), document(mt, second)),
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: int32(2)}),
+				)
+			},
+			expectedPurged: []example.Identifier{first, second},
+		},
+		{
+			testName: "restored-meanwhile-case",
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, document(mt, first), document(mt, second)),
+					mtest.CreateSuccessResponse(bson.E{Key: "n", Value: int32(1)}),
+					mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, document(mt, first)),
+				)
+			},
+			expectedPurged: []example.Identifier{second},
+		},
+		{
+			testName: "nothing-to-purge-case",
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+			},
+		},
+		{
+			testName: "find-error-case",
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(commandError)
+			},
+			expectedError: ErrMongoSystem,
+		},
+		{
+			testName: "delete-error-case",
+			prepMongoMock: func(mt *mtest.T) {
+				mt.AddMockResponses(
+					mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, document(mt, first)),
+					commandError,
+				)
+			},
+			expectedError: ErrDataDeleted,
+		},
+	}
+
+	for _, c := range testCases {
+		testName := c.testName
+		prepMongoMock := c.prepMongoMock
+		expectedPurged := c.expectedPurged
+		expectedError := c.expectedError
+
+		mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+		defer mt.Close()
+
+		mt.Run(testName, func(mt *mtest.T) {
+			prepMongoMock(mt)
+
+			st := store{
+				ctx:        context.Background(),
+				collection: mt.Coll,
+			}
+
+			purged, err := st.Purge(context.Background(), time.Now())
+			assert.ErrorIs(t, err, expectedError)
+
+			ids := make([]example.Identifier, 0, len(purged))
+			for _, l := range purged {
+				ids = append(ids, l.ID)
+				assert.Equal(t, "deleted-line", l.Data)
+			}
+
+			assert.ElementsMatch(t, expectedPurged, ids)
+		})
+	}
+}
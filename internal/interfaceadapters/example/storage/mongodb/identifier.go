@@ -0,0 +1,108 @@
+package mongodb
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+// objectIDTextLen is the length of the hex text of an ObjectID.
+const objectIDTextLen int = 2 * len(primitive.ObjectID{})
+
+// Identifier is an ObjectID, comparable with == and usable as a map key. Its
+// text form is the 24 hex digits of the ObjectID, in JSON too.
+type Identifier primitive.ObjectID
+
+func NewIdentityProvider() Identifier {
+	return Identifier{}
+}
+
+func (id Identifier) NewID() example.Identifier {
+	return Identifier(primitive.NewObjectID())
+}
+
+func (id Identifier) String() string {
+	var text [objectIDTextLen]byte
+	hex.Encode(text[:], id[:])
+
+	return string(text[:])
+}
+
+func (id Identifier) GetObjectID() primitive.ObjectID {
+	return primitive.ObjectID(id)
+}
+
+func (id Identifier) ParseID(key string) (example.Identifier, error) {
+	var parsed Identifier
+	if err := parsed.UnmarshalText([]byte(key)); err != nil {
+		return nil, err
+	}
+
+	return parsed, nil
+}
+
+func (id Identifier) MarshalText() ([]byte, error) {
+	text := make([]byte, objectIDTextLen)
+	hex.Encode(text, id[:])
+
+	return text, nil
+}
+
+func (id *Identifier) UnmarshalText(text []byte) error {
+	if len(text) != objectIDTextLen {
+		return fmt.Errorf("%q is not %d hex digits: %w", text, objectIDTextLen, ErrIdentifyer)
+	}
+
+	var parsed Identifier
+	if _, err := hex.Decode(parsed[:], text); err != nil {
+		return fmt.Errorf("%s: %w", err.Error(), ErrIdentifyer)
+	}
+
+	*id = parsed
+
+	return nil
+}
+
+// MarshalJSON writes the quoted text form, the hex digits needing no
+// escaping.
+func (id Identifier) MarshalJSON() ([]byte, error) {
+	text := make([]byte, objectIDTextLen+2)
+	text[0], text[len(text)-1] = '"', '"'
+	hex.Encode(text[1:len(text)-1], id[:])
+
+	return text, nil
+}
+
+// documentID is the _id a line is stored under: the ObjectID of an
+// Identifier, the text of an identifier of any other kind, so the lines of
+// every identity strategy can be stored.
+func documentID(id example.Identifier) (interface{}, error) {
+	switch id := id.(type) {
+	case nil:
+		return nil, ErrIdentifyer
+	case Identifier:
+		return id.GetObjectID(), nil
+	}
+
+	key := id.String()
+	if key == "" {
+		return nil, ErrIdentifyer
+	}
+
+	return key, nil
+}
+
+// lineIdentifier is the identifier of a stored _id, back from documentID.
+func lineIdentifier(id interface{}) example.Identifier {
+	switch id := id.(type) {
+	case primitive.ObjectID:
+		return Identifier(id)
+	case string:
+		return example.ID(id)
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package mongodb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+func Test_NewID(t *testing.T) {
+	id := NewIdentityProvider().NewID()
+
+	mid, is := id.(Identifier)
+	assert.True(t, is)
+
+	oid := mid.GetObjectID()
+	assert.NotEmpty(t, id.String())
+	assert.NotEmpty(t, mid.String())
+	assert.NotEmpty(t, oid.String())
+}
+
+// func IdentifierFromText(key string) (example.Identifier, error) {
+func Test_IdentifierFromText(t *testing.T) {
+	testCases := []struct {
+		name          string
+		key           string
+		identifier    Identifier
+		expectedError error
+	}{
+		{
+			name:          "successful-case",
+			key:           "63f39e5fb192144de70dfa58",
+			identifier:    Identifier{},
+			expectedError: nil,
+		},
+		{
+			name:          "error-case",
+			key:           "hola",
+			identifier:    Identifier{},
+			expectedError: ErrIdentifyer,
+		},
+	}
+
+	for _, c := range testCases {
+		testname := c.name
+		key := c.key
+		id := c.identifier
+		expectedError := c.expectedError
+
+		t.Run(testname, func(t *testing.T) {
+			result, err := id.ParseID(key)
+
+			if err != nil {
+				assert.ErrorIs(t, err, expectedError)
+			} else {
+				assert.Equal(t, true, ((example.Identifier)(result) == result))
+				assert.Equal(t, 24, len(result.String()))
+			}
+		})
+	}
+}
+
+func Test_IdentifierString(t *testing.T) {
+	oid := primitive.NewObjectID()
+
+	assert.Equal(t, oid.Hex(), Identifier(oid).String())
+	assert.Equal(t, "000000000000000000000000", Identifier{}.String())
+}
+
+func Test_IdentifierText(t *testing.T) {
+	testCases := []struct {
+		name          string
+		text          string
+		identifier    Identifier
+		expectedError error
+	}{
+		{
+			name:       "lower-case",
+			text:       "63f39e5fb192144de70dfa58",
+			identifier: Identifier{0x63, 0xf3, 0x9e, 0x5f, 0xb1, 0x92, 0x14, 0x4d, 0xe7, 0x0d, 0xfa, 0x58},
+		},
+		{
+			name:       "upper-case",
+			text:       "63F39E5FB192144DE70DFA58",
+			identifier: Identifier{0x63, 0xf3, 0x9e, 0x5f, 0xb1, 0x92, 0x14, 0x4d, 0xe7, 0x0d, 0xfa, 0x58},
+		},
+		{
+			name:          "too-short",
+			text:          "63f39e5fb192144de70dfa5",
+			expectedError: ErrIdentifyer,
+		},
+		{
+			name:          "too-long",
+			text:          "63f39e5fb192144de70dfa580",
+			expectedError: ErrIdentifyer,
+		},
+		{
+			name:          "not-hex",
+			text:          "63f39e5fb192144de70dfazz",
+			expectedError: ErrIdentifyer,
+		},
+		{
+			name:          "empty",
+			text:          "",
+			expectedError: ErrIdentifyer,
+		},
+	}
+
+	for _, c := range testCases {
+		testname := c.name
+		text := c.text
+		identifier := c.identifier
+		expectedError := c.expectedError
+
+		t.Run(testname, func(t *testing.T) {
+			var id Identifier
+			err := id.UnmarshalText([]byte(text))
+
+			if expectedError != nil {
+				assert.ErrorIs(t, err, expectedError)
+				assert.Equal(t, Identifier{}, id, "a failed unmarshal leaves the identifier untouched")
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, identifier, id)
+
+			marshaled, err := id.MarshalText()
+			require.NoError(t, err)
+			assert.Equal(t, primitive.ObjectID(identifier).Hex(), string(marshaled))
+		})
+	}
+}
+
+func Test_IdentifierJSON(t *testing.T) {
+	oid := primitive.NewObjectID()
+
+	type document struct {
+		ID    Identifier            `json:"id"`
+		Index map[Identifier]string `json:"index"`
+	}
+
+	input := document{
+		ID:    Identifier(oid),
+		Index: map[Identifier]string{Identifier(oid): "line"},
+	}
+
+	encoded, err := json.Marshal(input)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"`+oid.Hex()+`","index":{"`+oid.Hex()+`":"line"}}`, string(encoded))
+
+	var output document
+	require.NoError(t, json.Unmarshal(encoded, &output))
+	assert.Equal(t, input, output)
+
+	var id Identifier
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"hola"`), &id), ErrIdentifyer)
+}
+
+func Test_IdentifierID(t *testing.T) {
+	oid := primitive.NewObjectID()
+
+	parsed, err := NewIdentityProvider().ParseID(oid.Hex())
+	require.NoError(t, err)
+
+	assert.Equal(t, example.ID(oid.Hex()), example.IDOf(Identifier(oid)))
+	assert.Equal(t, example.IDOf(Identifier(oid)), example.IDOf(parsed))
+	assert.Equal(t, example.ID(""), example.IDOf(nil))
+}
+
+var benchmarkIdentifier = Identifier(primitive.NewObjectID())
+
+func Benchmark_IdentifierString(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_ = benchmarkIdentifier.String()
+	}
+}
+
+func Benchmark_IdentifierMarshalText(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := benchmarkIdentifier.MarshalText(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Benchmark_IdentifierMarshalJSON(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := benchmarkIdentifier.MarshalJSON(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Benchmark_ParseID(b *testing.B) {
+	key := benchmarkIdentifier.String()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := benchmarkIdentifier.ParseID(key); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,454 @@
+// Package mongotest provides an in-process stand-in for a mongo collection,
+// so the mongodb adapter can be tested without a server. The documents are
+// kept as BSON and the filters, updates, sorts and text searches are
+// evaluated on them, with the semantics the adapter relies on. Anything
+// beyond that fails with ErrUnsupported rather than being silently ignored.
+package mongotest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	ErrUnsupported fakeError = "unsupported by the fake collection"
+	ErrNoTextIndex fakeError = "text index required for $text query"
+	ErrImmutableID fakeError = "the _id field is immutable"
+
+	duplicateKeyCode int = 11000
+
+	idField string = "_id"
+)
+
+type fakeError string
+
+func (fe fakeError) Error() string {
+	return string(fe)
+}
+
+// Collection keeps its documents in insertion order, which is the order they
+// are returned in when no sort is given. It is safe for concurrent use.
+type Collection struct {
+	mu         sync.RWMutex
+	documents  []bson.Raw
+	textFields []string
+}
+
+func NewCollection() *Collection {
+	return &Collection{}
+}
+
+// TextIndex indexes the given fields for $text queries, as the text index of
+// a real collection would. There is no stemming and no stop words.
+func (c *Collection) TextIndex(fields ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.textFields = append([]string(nil), fields...)
+}
+
+// Documents returns a copy of every stored document, in insertion order.
+func (c *Collection) Documents() []bson.Raw {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	documents := make([]bson.Raw, len(c.documents))
+	for i, d := range c.documents {
+		documents[i] = append(bson.Raw(nil), d...)
+	}
+
+	return documents
+}
+
+func (c *Collection) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	raw, id, err := withID(document)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.indexOf(id) >= 0 {
+		return nil, mongo.WriteException{WriteErrors: mongo.WriteErrors{duplicateKey(0, id)}}
+	}
+
+	c.documents = append(c.documents, raw)
+
+	return &mongo.InsertOneResult{InsertedID: decoded(id)}, nil
+}
+
+// InsertMany is ordered by default, the first failing document stops the
+// insertion of the following ones. Unordered, every document is tried.
+func (c *Collection) InsertMany(ctx context.Context, documents []interface{}, opts ...*options.InsertManyOptions) (*mongo.InsertManyResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ordered := true
+	if o := options.MergeInsertManyOptions(opts...); o.Ordered != nil {
+		ordered = *o.Ordered
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := &mongo.InsertManyResult{}
+	var failures []mongo.BulkWriteError
+
+	for i, document := range documents {
+		raw, id, err := withID(document)
+		if err != nil {
+			return nil, err
+		}
+
+		if c.indexOf(id) >= 0 {
+			failures = append(failures, mongo.BulkWriteError{WriteError: duplicateKey(i, id)})
+			if ordered {
+				break
+			}
+
+			continue
+		}
+
+		c.documents = append(c.documents, raw)
+		result.InsertedIDs = append(result.InsertedIDs, decoded(id))
+	}
+
+	if len(failures) > 0 {
+		return result, mongo.BulkWriteException{WriteErrors: failures}
+	}
+
+	return result, nil
+}
+
+func (c *Collection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	if err := ctx.Err(); err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+
+	o := options.MergeFindOneOptions(opts...)
+	found, err := c.find(filter, o.Sort, o.Projection, o.Skip, nil)
+	if err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+
+	if len(found) == 0 {
+		return mongo.NewSingleResultFromDocument(bson.D{}, mongo.ErrNoDocuments, nil)
+	}
+
+	return mongo.NewSingleResultFromDocument(found[0], nil, nil)
+}
+
+func (c *Collection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	o := options.MergeFindOptions(opts...)
+	found, err := c.find(filter, o.Sort, o.Projection, o.Skip, o.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	documents := make([]interface{}, len(found))
+	for i, d := range found {
+		documents[i] = d
+	}
+
+	return mongo.NewCursorFromDocuments(documents, nil, nil)
+}
+
+// UpdateOne takes either a document of update operators or an aggregation
+// pipeline, and updates the first document matched.
+func (c *Collection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if o := options.MergeUpdateOptions(opts...); o.Upsert != nil && *o.Upsert {
+		return nil, fmt.Errorf("upsert: %w", ErrUnsupported)
+	}
+
+	q, err := c.compile(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	apply, err := compileUpdate(update)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := &mongo.UpdateResult{}
+	for i, d := range c.documents {
+		matched, _, err := q.match(d)
+		if err != nil {
+			return nil, err
+		}
+
+		if !matched {
+			continue
+		}
+
+		updated, err := apply(d)
+		if err != nil {
+			return nil, err
+		}
+
+		result.MatchedCount = 1
+		if !bytes.Equal(updated, d) {
+			c.documents[i] = updated
+			result.ModifiedCount = 1
+		}
+
+		break
+	}
+
+	return result, nil
+}
+
+func (c *Collection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	return c.delete(ctx, filter, 1)
+}
+
+func (c *Collection) DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	return c.delete(ctx, filter, -1)
+}
+
+func (c *Collection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	o := options.MergeCountOptions(opts...)
+	found, err := c.find(filter, nil, nil, o.Skip, o.Limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(found)), nil
+}
+
+// delete removes up to limit documents, every one matched when limit is
+// negative.
+func (c *Collection) delete(ctx context.Context, filter interface{}, limit int) (*mongo.DeleteResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	q, err := c.compile(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	kept := c.documents[:0]
+	var deleted int64
+	for _, d := range c.documents {
+		matched := false
+		if limit < 0 || deleted < int64(limit) {
+			if matched, _, err = q.match(d); err != nil {
+				return nil, err
+			}
+		}
+
+		if matched {
+			deleted++
+			continue
+		}
+
+		kept = append(kept, d)
+	}
+
+	for i := len(kept); i < len(c.documents); i++ {
+		c.documents[i] = nil
+	}
+	c.documents = kept
+
+	return &mongo.DeleteResult{DeletedCount: deleted}, nil
+}
+
+type scored struct {
+	document bson.Raw
+	score    float64
+}
+
+func (c *Collection) find(filter, sorting, projection interface{}, skip, limit *int64) ([]bson.Raw, error) {
+	q, err := c.compile(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := compileSort(sorting)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := compileProjection(projection)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	var found []scored
+	for _, d := range c.documents {
+		matched, score, err := q.match(d)
+		if err != nil {
+			c.mu.RUnlock()
+			return nil, err
+		}
+
+		if matched {
+			found = append(found, scored{document: d, score: score})
+		}
+	}
+	c.mu.RUnlock()
+
+	if len(keys) > 0 {
+		sort.SliceStable(found, func(i, j int) bool {
+			return keys.less(found[i], found[j])
+		})
+	}
+
+	if skip != nil && *skip > 0 {
+		if *skip >= int64(len(found)) {
+			found = nil
+		} else {
+			found = found[*skip:]
+		}
+	}
+
+	if limit != nil && *limit != 0 {
+		n := *limit
+		if n < 0 {
+			n = -n
+		}
+
+		if n < int64(len(found)) {
+			found = found[:n]
+		}
+	}
+
+	documents := make([]bson.Raw, len(found))
+	for i, s := range found {
+		if documents[i], err = project(s); err != nil {
+			return nil, err
+		}
+	}
+
+	return documents, nil
+}
+
+func (c *Collection) compile(filter interface{}) (query, error) {
+	c.mu.RLock()
+	fields := c.textFields
+	c.mu.RUnlock()
+
+	return compileQuery(filter, fields)
+}
+
+// indexOf finds the document with the given _id, -1 when there is none.
+func (c *Collection) indexOf(id bson.RawValue) int {
+	for i, d := range c.documents {
+		if equal(d.Lookup(idField), id) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// withID marshals document, with a new ObjectID as _id when it has none.
+func withID(document interface{}) (bson.Raw, bson.RawValue, error) {
+	raw, err := marshal(document)
+	if err != nil {
+		return nil, bson.RawValue{}, err
+	}
+
+	if id, err := raw.LookupErr(idField); err == nil {
+		return raw, id, nil
+	}
+
+	elements, err := toD(raw)
+	if err != nil {
+		return nil, bson.RawValue{}, err
+	}
+
+	elements = append(bson.D{{Key: idField, Value: primitive.NewObjectID()}}, elements...)
+	if raw, err = bson.Marshal(elements); err != nil {
+		return nil, bson.RawValue{}, err
+	}
+
+	return raw, raw.Lookup(idField), nil
+}
+
+// marshal turns a document given as any of the types the driver takes into
+// BSON. A nil document is the empty one.
+func marshal(document interface{}) (bson.Raw, error) {
+	switch d := document.(type) {
+	case nil:
+		return bson.Marshal(bson.D{})
+	case bson.Raw:
+		return d, nil
+	case []byte:
+		return bson.Raw(d), nil
+	}
+
+	return bson.Marshal(document)
+}
+
+// toD splits a document into its elements, keeping their values as they are
+// encoded.
+func toD(raw bson.Raw) (bson.D, error) {
+	elements, err := raw.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	d := make(bson.D, len(elements))
+	for i, e := range elements {
+		d[i] = bson.E{Key: e.Key(), Value: e.Value()}
+	}
+
+	return d, nil
+}
+
+func decoded(value bson.RawValue) interface{} {
+	if value.Type == bsontype.ObjectID {
+		return value.ObjectID()
+	}
+
+	var v interface{}
+	if err := value.Unmarshal(&v); err != nil {
+		return value
+	}
+
+	return v
+}
+
+func duplicateKey(index int, id bson.RawValue) mongo.WriteError {
+	return mongo.WriteError{
+		Index:   index,
+		Code:    duplicateKeyCode,
+		Message: fmt.Sprintf("E11000 duplicate key error dup key: { _id: %s }", id),
+	}
+}
@@ -0,0 +1,381 @@
+package mongotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type document struct {
+	ID      string     `bson:"_id"`
+	N       int        `bson:"n"`
+	Data    string     `bson:"data"`
+	At      time.Time  `bson:"at"`
+	Deleted *time.Time `bson:"deleted_at,omitempty"`
+	Tags    []string   `bson:"tags,omitempty"`
+}
+
+var tstamp = time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+
+func newFilled(t *testing.T) *Collection {
+	deleted := tstamp.Add(time.Hour)
+
+	c := NewCollection()
+	_, err := c.InsertMany(context.Background(), []interface{}{
+		document{ID: "a", N: 1, Data: "red apple", At: tstamp, Tags: []string{"fruit", "red"}},
+		document{ID: "b", N: 2, Data: "green apple", At: tstamp.Add(time.Minute), Deleted: &deleted},
+		document{ID: "c", N: 3, Data: "red cherry", At: tstamp.Add(2 * time.Minute), Tags: []string{"fruit"}},
+		document{ID: "d", N: 4, Data: "apple apple pie", At: tstamp.Add(3 * time.Minute)},
+	})
+	require.NoError(t, err)
+
+	return c
+}
+
+func ids(t *testing.T, cursor *mongo.Cursor, err error) []string {
+	require.NoError(t, err)
+
+	var found []document
+	require.NoError(t, cursor.All(context.Background(), &found))
+
+	keys := make([]string, len(found))
+	for i, d := range found {
+		keys[i] = d.ID
+	}
+
+	return keys
+}
+
+func Test_Find(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filter   interface{}
+		expected []string
+	}{
+		{name: "nil-case", filter: nil, expected: []string{"a", "b", "c", "d"}},
+		{name: "equal-case", filter: bson.D{{Key: "data", Value: "red apple"}}, expected: []string{"a"}},
+		{name: "bson-m-case", filter: bson.M{"n": 3}, expected: []string{"c"}},
+		{name: "number-types-case", filter: bson.D{{Key: "n", Value: 2.0}}, expected: []string{"b"}},
+		{name: "null-matches-missing-case", filter: bson.D{{Key: "deleted_at", Value: nil}}, expected: []string{"a", "c", "d"}},
+		{name: "exists-case", filter: bson.D{{Key: "deleted_at", Value: bson.D{{Key: "$exists", Value: true}}}}, expected: []string{"b"}},
+		{name: "in-case", filter: bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: []string{"d", "a", "x"}}}}}, expected: []string{"a", "d"}},
+		{name: "nin-case", filter: bson.D{{Key: "_id", Value: bson.D{{Key: "$nin", Value: bson.A{"a", "b"}}}}}, expected: []string{"c", "d"}},
+		{name: "ne-case", filter: bson.D{{Key: "n", Value: bson.D{{Key: "$ne", Value: 1}}}}, expected: []string{"b", "c", "d"}},
+		{name: "range-case", filter: bson.D{{Key: "n", Value: bson.D{{Key: "$gt", Value: 1}, {Key: "$lte", Value: 3}}}}, expected: []string{"b", "c"}},
+		{name: "time-range-case", filter: bson.D{{Key: "at", Value: bson.D{{Key: "$gte", Value: tstamp.Add(time.Minute)}, {Key: "$lt", Value: tstamp.Add(3 * time.Minute)}}}}, expected: []string{"b", "c"}},
+		{name: "time-against-number-case", filter: bson.D{{Key: "at", Value: bson.D{{Key: "$gt", Value: 0}}}}, expected: []string{}},
+		{name: "regex-case", filter: bson.D{{Key: "data", Value: primitive.Regex{Pattern: "^red"}}}, expected: []string{"a", "c"}},
+		{name: "regex-operator-case", filter: bson.D{{Key: "data", Value: bson.D{{Key: "$regex", Value: "APPLE$"}, {Key: "$options", Value: "i"}}}}, expected: []string{"a", "b"}},
+		{name: "array-element-case", filter: bson.D{{Key: "tags", Value: "red"}}, expected: []string{"a"}},
+		{name: "or-case", filter: bson.D{{Key: "$or", Value: bson.A{bson.D{{Key: "n", Value: 1}}, bson.D{{Key: "n", Value: 4}}}}}, expected: []string{"a", "d"}},
+		{name: "and-case", filter: bson.D{{Key: "$and", Value: bson.A{bson.D{{Key: "tags", Value: "fruit"}}, bson.D{{Key: "n", Value: bson.D{{Key: "$gt", Value: 1}}}}}}}, expected: []string{"c"}},
+		{name: "nor-case", filter: bson.D{{Key: "$nor", Value: bson.A{bson.D{{Key: "n", Value: 1}}, bson.D{{Key: "n", Value: 4}}}}}, expected: []string{"b", "c"}},
+		{name: "no-match-case", filter: bson.D{{Key: "data", Value: "banana"}}, expected: []string{}},
+	}
+
+	c := newFilled(t)
+
+	for _, tc := range testCases {
+		filter := tc.filter
+		expected := tc.expected
+
+		t.Run(tc.name, func(t *testing.T) {
+			cursor, err := c.Find(context.Background(), filter)
+			assert.Equal(t, expected, ids(t, cursor, err))
+
+			count, err := c.CountDocuments(context.Background(), filter)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(expected)), count)
+		})
+	}
+}
+
+func Test_FindOptions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     *options.FindOptions
+		expected []string
+	}{
+		{
+			name:     "descending-case",
+			opts:     options.Find().SetSort(bson.D{{Key: "at", Value: -1}}),
+			expected: []string{"d", "c", "b", "a"},
+		},
+		{
+			name:     "missing-first-case",
+			opts:     options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: 1}}),
+			expected: []string{"b", "a", "c", "d"},
+		},
+		{
+			name:     "skip-limit-case",
+			opts:     options.Find().SetSort(bson.D{{Key: "n", Value: 1}}).SetSkip(1).SetLimit(2),
+			expected: []string{"b", "c"},
+		},
+		{
+			name:     "skip-past-the-end-case",
+			opts:     options.Find().SetSkip(10),
+			expected: []string{},
+		},
+	}
+
+	c := newFilled(t)
+
+	for _, tc := range testCases {
+		opts := tc.opts
+		expected := tc.expected
+
+		t.Run(tc.name, func(t *testing.T) {
+			cursor, err := c.Find(context.Background(), bson.D{}, opts)
+			assert.Equal(t, expected, ids(t, cursor, err))
+		})
+	}
+}
+
+func Test_TextSearch(t *testing.T) {
+	ctx := context.Background()
+	c := newFilled(t)
+
+	filter := bson.D{{Key: "$text", Value: bson.D{{Key: "$search", Value: "Apple"}}}}
+
+	_, err := c.Find(ctx, filter)
+	assert.ErrorIs(t, err, ErrNoTextIndex)
+
+	c.TextIndex("data")
+
+	score := bson.E{Key: "score", Value: bson.D{{Key: "$meta", Value: "textScore"}}}
+	cursor, err := c.Find(ctx, append(filter, bson.E{Key: "deleted_at", Value: nil}), options.Find().
+		SetProjection(bson.D{score}).
+		SetSort(bson.D{score, {Key: "_id", Value: 1}}))
+	require.NoError(t, err)
+
+	var found []struct {
+		ID    string  `bson:"_id"`
+		Data  string  `bson:"data"`
+		Score float64 `bson:"score"`
+	}
+	require.NoError(t, cursor.All(ctx, &found))
+
+	require.Len(t, found, 2)
+	assert.Equal(t, "d", found[0].ID, "more occurrences score higher")
+	assert.Equal(t, "a", found[1].ID)
+	assert.Greater(t, found[0].Score, found[1].Score)
+	assert.Equal(t, "apple apple pie", found[0].Data, "the projection keeps the whole document")
+}
+
+func Test_FindOne(t *testing.T) {
+	ctx := context.Background()
+	c := newFilled(t)
+
+	var found document
+	require.NoError(t, c.FindOne(ctx, bson.D{{Key: "_id", Value: "c"}}).Decode(&found))
+	assert.Equal(t, "red cherry", found.Data)
+	assert.Equal(t, tstamp.Add(2*time.Minute), found.At)
+
+	err := c.FindOne(ctx, bson.D{{Key: "_id", Value: "x"}}).Decode(&found)
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	require.NoError(t, c.FindOne(ctx, bson.D{}, options.FindOne().SetSort(bson.D{{Key: "n", Value: -1}})).Decode(&found))
+	assert.Equal(t, "d", found.ID)
+}
+
+func Test_Insert(t *testing.T) {
+	ctx := context.Background()
+	c := NewCollection()
+
+	result, err := c.InsertOne(ctx, bson.D{{Key: "data", Value: "no id"}})
+	require.NoError(t, err)
+	assert.IsType(t, primitive.ObjectID{}, result.InsertedID)
+
+	_, err = c.InsertOne(ctx, document{ID: "a"})
+	require.NoError(t, err)
+
+	_, err = c.InsertOne(ctx, document{ID: "a"})
+	assert.True(t, mongo.IsDuplicateKeyError(err))
+
+	_, err = c.InsertMany(ctx, []interface{}{document{ID: "b"}, document{ID: "a"}, document{ID: "c"}})
+	var bwe mongo.BulkWriteException
+	require.ErrorAs(t, err, &bwe)
+	require.Len(t, bwe.WriteErrors, 1)
+	assert.Equal(t, 1, bwe.WriteErrors[0].Index)
+	assert.Len(t, c.Documents(), 3, "an ordered insert stops at the first failure")
+
+	many, err := c.InsertMany(ctx, []interface{}{document{ID: "a"}, document{ID: "c"}, document{ID: "b"}}, options.InsertMany().SetOrdered(false))
+	require.ErrorAs(t, err, &bwe)
+	assert.Len(t, bwe.WriteErrors, 2)
+	assert.Equal(t, []interface{}{"c"}, many.InsertedIDs)
+	assert.Len(t, c.Documents(), 4, "an unordered insert tries every document")
+}
+
+func Test_UpdateOne(t *testing.T) {
+	at := tstamp.Add(time.Hour)
+	later := at.Add(time.Hour)
+	keepDeleted := mongo.Pipeline{{{Key: "$set", Value: bson.D{{Key: "deleted_at", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$deleted_at", later}}}}}}}}
+
+	testCases := []struct {
+		name             string
+		filter           interface{}
+		update           interface{}
+		expectedMatched  int64
+		expectedModified int64
+		expectedError    error
+		check            func(t *testing.T, found document)
+	}{
+		{
+			name:             "set-case",
+			filter:           bson.D{{Key: "_id", Value: "a"}},
+			update:           bson.D{{Key: "$set", Value: bson.D{{Key: "data", Value: "pink apple"}, {Key: "n", Value: 10}}}},
+			expectedMatched:  1,
+			expectedModified: 1,
+			check: func(t *testing.T, found document) {
+				assert.Equal(t, "pink apple", found.Data)
+				assert.Equal(t, 10, found.N)
+			},
+		},
+		{
+			name:             "unset-case",
+			filter:           bson.D{{Key: "_id", Value: "b"}},
+			update:           bson.D{{Key: "$unset", Value: bson.D{{Key: "deleted_at", Value: ""}}}},
+			expectedMatched:  1,
+			expectedModified: 1,
+			check: func(t *testing.T, found document) {
+				assert.Nil(t, found.Deleted)
+			},
+		},
+		{
+			name:            "unchanged-case",
+			filter:          bson.D{{Key: "_id", Value: "c"}},
+			update:          bson.D{{Key: "$set", Value: bson.D{{Key: "n", Value: 3}}}},
+			expectedMatched: 1,
+		},
+		{
+			name:             "pipeline-sets-missing-case",
+			filter:           bson.D{{Key: "_id", Value: "a"}},
+			update:           keepDeleted,
+			expectedMatched:  1,
+			expectedModified: 1,
+			check: func(t *testing.T, found document) {
+				require.NotNil(t, found.Deleted)
+				assert.Equal(t, later, *found.Deleted)
+			},
+		},
+		{
+			name:            "pipeline-keeps-existing-case",
+			filter:          bson.D{{Key: "_id", Value: "b"}},
+			update:          keepDeleted,
+			expectedMatched: 1,
+			check: func(t *testing.T, found document) {
+				require.NotNil(t, found.Deleted)
+				assert.Equal(t, at, *found.Deleted)
+			},
+		},
+		{
+			name:   "not-found-case",
+			filter: bson.D{{Key: "_id", Value: "x"}},
+			update: bson.D{{Key: "$set", Value: bson.D{{Key: "n", Value: 1}}}},
+		},
+		{
+			name:          "replacement-case",
+			filter:        bson.D{{Key: "_id", Value: "a"}},
+			update:        bson.D{{Key: "n", Value: 1}},
+			expectedError: ErrUnsupported,
+		},
+		{
+			name:          "immutable-id-case",
+			filter:        bson.D{{Key: "_id", Value: "a"}},
+			update:        bson.D{{Key: "$set", Value: bson.D{{Key: "_id", Value: "z"}}}},
+			expectedError: ErrImmutableID,
+		},
+		{
+			name:          "unsupported-operator-case",
+			filter:        bson.D{{Key: "_id", Value: "a"}},
+			update:        bson.D{{Key: "$inc", Value: bson.D{{Key: "n", Value: 1}}}},
+			expectedError: ErrUnsupported,
+		},
+	}
+
+	for _, tc := range testCases {
+		filter := tc.filter
+		update := tc.update
+		expectedMatched := tc.expectedMatched
+		expectedModified := tc.expectedModified
+		expectedError := tc.expectedError
+		check := tc.check
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := newFilled(t)
+
+			result, err := c.UpdateOne(ctx, filter, update)
+			if expectedError != nil {
+				assert.ErrorIs(t, err, expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, expectedMatched, result.MatchedCount)
+			assert.Equal(t, expectedModified, result.ModifiedCount)
+
+			if check != nil {
+				var found document
+				require.NoError(t, c.FindOne(ctx, filter).Decode(&found))
+				check(t, found)
+			}
+		})
+	}
+}
+
+func Test_Delete(t *testing.T) {
+	ctx := context.Background()
+	c := newFilled(t)
+
+	result, err := c.DeleteOne(ctx, bson.D{{Key: "tags", Value: "fruit"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.DeletedCount)
+
+	result, err = c.DeleteMany(ctx, bson.D{{Key: "n", Value: bson.D{{Key: "$gte", Value: 2}}}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.DeletedCount)
+	assert.Empty(t, c.Documents())
+}
+
+func Test_Unsupported(t *testing.T) {
+	ctx := context.Background()
+	c := newFilled(t)
+
+	for _, filter := range []interface{}{
+		bson.D{{Key: "$where", Value: "true"}},
+		bson.D{{Key: "n", Value: bson.D{{Key: "$mod", Value: bson.A{2, 0}}}}},
+	} {
+		_, err := c.Find(ctx, filter)
+		assert.ErrorIs(t, err, ErrUnsupported)
+	}
+
+	_, err := c.Find(ctx, bson.D{}, options.Find().SetProjection(bson.D{{Key: "data", Value: 1}}))
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func Test_ExpiredContext(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	c := newFilled(t)
+
+	_, err := c.InsertOne(ctx, document{ID: "e"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = c.Find(ctx, bson.D{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.ErrorIs(t, c.FindOne(ctx, bson.D{}).Err(), context.DeadlineExceeded)
+
+	_, err = c.DeleteMany(ctx, bson.D{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, c.Documents(), 4)
+}
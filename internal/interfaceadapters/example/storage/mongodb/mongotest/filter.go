@@ -0,0 +1,708 @@
+package mongotest
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// query is a compiled filter. terms holds the words of a $text search, nil
+// when the filter has none.
+type query struct {
+	filter     bson.Raw
+	terms      []string
+	textFields []string
+}
+
+func compileQuery(filter interface{}, textFields []string) (query, error) {
+	raw, err := marshal(filter)
+	if err != nil {
+		return query{}, err
+	}
+
+	q := query{filter: raw, textFields: textFields}
+
+	text, err := raw.LookupErr("$text")
+	if err != nil {
+		return q, nil
+	}
+
+	if len(textFields) == 0 {
+		return query{}, ErrNoTextIndex
+	}
+
+	spec, is := text.DocumentOK()
+	if !is {
+		return query{}, fmt.Errorf("$text: %w", ErrUnsupported)
+	}
+
+	elements, err := spec.Elements()
+	if err != nil {
+		return query{}, err
+	}
+
+	// The words are matched without stemming, regardless of case and with
+	// their diacritics, which the options may only confirm.
+	for _, e := range elements {
+		switch e.Key() {
+		case "$search":
+			search, is := e.Value().StringValueOK()
+			if !is {
+				return query{}, fmt.Errorf("$search of type %s: %w", e.Value().Type, ErrUnsupported)
+			}
+
+			q.terms = terms(search)
+		case "$language":
+			if language, is := e.Value().StringValueOK(); !is || language != "none" {
+				return query{}, fmt.Errorf("$language %s: %w", e.Value(), ErrUnsupported)
+			}
+		case "$caseSensitive":
+			if sensitive, is := e.Value().BooleanOK(); !is || sensitive {
+				return query{}, fmt.Errorf("$caseSensitive %s: %w", e.Value(), ErrUnsupported)
+			}
+		case "$diacriticSensitive":
+			if sensitive, is := e.Value().BooleanOK(); !is || !sensitive {
+				return query{}, fmt.Errorf("$diacriticSensitive %s: %w", e.Value(), ErrUnsupported)
+			}
+		default:
+			return query{}, fmt.Errorf("$text option %q: %w", e.Key(), ErrUnsupported)
+		}
+	}
+
+	if q.terms == nil {
+		q.terms = []string{}
+	}
+
+	return q, nil
+}
+
+// match reports whether document satisfies the filter, and its text score
+// when the filter is a $text search.
+func (q query) match(document bson.Raw) (bool, float64, error) {
+	var score float64
+	if q.terms != nil {
+		if score = textScore(document, q.textFields, q.terms); score == 0 {
+			return false, 0, nil
+		}
+	}
+
+	matched, err := matchDocument(document, q.filter, true)
+
+	return matched, score, err
+}
+
+func matchDocument(document, filter bson.Raw, top bool) (bool, error) {
+	elements, err := filter.Elements()
+	if err != nil {
+		return false, err
+	}
+
+	for _, e := range elements {
+		var matched bool
+
+		switch key := e.Key(); {
+		case key == "$text" && top:
+			continue
+		case key == "$and" || key == "$or" || key == "$nor":
+			matched, err = matchLogical(document, key, e.Value())
+		case strings.HasPrefix(key, "$"):
+			return false, fmt.Errorf("operator %q: %w", key, ErrUnsupported)
+		default:
+			matched, err = matchField(document, key, e.Value())
+		}
+
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func matchLogical(document bson.Raw, operator string, operand bson.RawValue) (bool, error) {
+	clauses, is := operand.ArrayOK()
+	if !is {
+		return false, fmt.Errorf("%s of type %s: %w", operator, operand.Type, ErrUnsupported)
+	}
+
+	values, err := clauses.Values()
+	if err != nil {
+		return false, err
+	}
+
+	for _, v := range values {
+		clause, is := v.DocumentOK()
+		if !is {
+			return false, fmt.Errorf("%s clause of type %s: %w", operator, v.Type, ErrUnsupported)
+		}
+
+		matched, err := matchDocument(document, clause, false)
+		if err != nil {
+			return false, err
+		}
+
+		switch {
+		case operator == "$and" && !matched:
+			return false, nil
+		case operator == "$or" && matched:
+			return true, nil
+		case operator == "$nor" && matched:
+			return false, nil
+		}
+	}
+
+	return operator != "$or", nil
+}
+
+func matchField(document bson.Raw, path string, condition bson.RawValue) (bool, error) {
+	value, err := document.LookupErr(strings.Split(path, ".")...)
+	exists := err == nil
+
+	if operators, is := operatorDocument(condition); is {
+		return matchOperators(value, exists, operators)
+	}
+
+	if condition.Type == bsontype.Regex {
+		pattern, options := condition.Regex()
+		return matchRegex(value, exists, pattern, options)
+	}
+
+	return matchEqual(value, exists, condition), nil
+}
+
+// operatorDocument reports whether condition is a document of query
+// operators rather than a value to compare with.
+func operatorDocument(condition bson.RawValue) (bson.Raw, bool) {
+	d, is := condition.DocumentOK()
+	if !is {
+		return nil, false
+	}
+
+	first, err := d.IndexErr(0)
+	if err != nil {
+		return nil, false
+	}
+
+	return d, strings.HasPrefix(first.Key(), "$")
+}
+
+func matchOperators(value bson.RawValue, exists bool, operators bson.Raw) (bool, error) {
+	elements, err := operators.Elements()
+	if err != nil {
+		return false, err
+	}
+
+	for _, e := range elements {
+		operand := e.Value()
+
+		var matched bool
+		switch op := e.Key(); op {
+		case "$eq":
+			matched = matchEqual(value, exists, operand)
+		case "$ne":
+			matched = !matchEqual(value, exists, operand)
+		case "$gt", "$gte", "$lt", "$lte":
+			matched = exists && anyValue(value, func(v bson.RawValue) bool {
+				c, comparable := compare(v, operand)
+				return comparable && satisfies(op, c)
+			})
+		case "$in", "$nin":
+			if matched, err = matchIn(value, exists, operand); err != nil {
+				return false, err
+			}
+
+			matched = matched == (op == "$in")
+		case "$exists":
+			matched = exists == truthy(operand)
+		case "$regex":
+			pattern, options := operand.StringValue(), ""
+			if operand.Type == bsontype.Regex {
+				pattern, options = operand.Regex()
+			}
+
+			if o, err := operators.LookupErr("$options"); err == nil {
+				options = o.StringValue()
+			}
+
+			if matched, err = matchRegex(value, exists, pattern, options); err != nil {
+				return false, err
+			}
+		case "$options":
+			matched = true
+		default:
+			return false, fmt.Errorf("operator %q: %w", op, ErrUnsupported)
+		}
+
+		if !matched {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// matchEqual compares a field with a value, a null value matching the fields
+// missing too. An array field matches when any of its elements does.
+func matchEqual(value bson.RawValue, exists bool, operand bson.RawValue) bool {
+	if operand.Type == bsontype.Null {
+		return !exists || value.Type == bsontype.Null
+	}
+
+	return exists && anyValue(value, func(v bson.RawValue) bool {
+		return equal(v, operand)
+	})
+}
+
+func matchIn(value bson.RawValue, exists bool, operand bson.RawValue) (bool, error) {
+	candidates, is := operand.ArrayOK()
+	if !is {
+		return false, fmt.Errorf("$in of type %s: %w", operand.Type, ErrUnsupported)
+	}
+
+	values, err := candidates.Values()
+	if err != nil {
+		return false, err
+	}
+
+	for _, candidate := range values {
+		if candidate.Type == bsontype.Regex {
+			pattern, options := candidate.Regex()
+			if matched, err := matchRegex(value, exists, pattern, options); err != nil || matched {
+				return matched, err
+			}
+
+			continue
+		}
+
+		if matchEqual(value, exists, candidate) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func matchRegex(value bson.RawValue, exists bool, pattern, options string) (bool, error) {
+	if !exists {
+		return false, nil
+	}
+
+	flags := ""
+	for _, o := range options {
+		switch o {
+		case 'i', 'm', 's':
+			flags += string(o)
+		default:
+			return false, fmt.Errorf("regular expression option %q: %w", o, ErrUnsupported)
+		}
+	}
+
+	if flags != "" {
+		pattern = "(?" + flags + ")" + pattern
+	}
+
+	rx, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, err
+	}
+
+	return anyValue(value, func(v bson.RawValue) bool {
+		s, is := v.StringValueOK()
+		return is && rx.MatchString(s)
+	}), nil
+}
+
+func anyValue(value bson.RawValue, test func(bson.RawValue) bool) bool {
+	if test(value) {
+		return true
+	}
+
+	elements, is := value.ArrayOK()
+	if !is {
+		return false
+	}
+
+	values, err := elements.Values()
+	if err != nil {
+		return false
+	}
+
+	for _, v := range values {
+		if test(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func satisfies(operator string, comparison int) bool {
+	switch operator {
+	case "$gt":
+		return comparison > 0
+	case "$gte":
+		return comparison >= 0
+	case "$lt":
+		return comparison < 0
+	default:
+		return comparison <= 0
+	}
+}
+
+func truthy(value bson.RawValue) bool {
+	switch value.Type {
+	case bsontype.Boolean:
+		return value.Boolean()
+	case bsontype.Null, bsontype.Undefined:
+		return false
+	}
+
+	if n, is := number(value); is {
+		return n != 0
+	}
+
+	return true
+}
+
+func equal(a, b bson.RawValue) bool {
+	c, comparable := compare(a, b)
+	return comparable && c == 0
+}
+
+// compare orders two values of the same kind, numbers of any type being of
+// the same kind. Values of different kinds are not comparable, as in query
+// filters.
+func compare(a, b bson.RawValue) (int, bool) {
+	if x, is := number(a); is {
+		y, is := number(b)
+		if !is {
+			return 0, false
+		}
+
+		return compareFloats(x, y), true
+	}
+
+	if a.Type != b.Type {
+		return 0, false
+	}
+
+	switch a.Type {
+	case bsontype.String:
+		return strings.Compare(a.StringValue(), b.StringValue()), true
+	case bsontype.ObjectID:
+		x, y := a.ObjectID(), b.ObjectID()
+		return bytes.Compare(x[:], y[:]), true
+	case bsontype.DateTime:
+		return compareInts(a.DateTime(), b.DateTime()), true
+	case bsontype.Boolean:
+		return compareBools(a.Boolean(), b.Boolean()), true
+	case bsontype.Null, bsontype.Undefined, bsontype.MinKey, bsontype.MaxKey:
+		return 0, true
+	}
+
+	if bytes.Equal(a.Value, b.Value) {
+		return 0, true
+	}
+
+	return bytes.Compare(a.Value, b.Value), false
+}
+
+func number(value bson.RawValue) (float64, bool) {
+	switch value.Type {
+	case bsontype.Double:
+		return value.Double(), true
+	case bsontype.Int32:
+		return float64(value.Int32()), true
+	case bsontype.Int64:
+		return float64(value.Int64()), true
+	}
+
+	return 0, false
+}
+
+func compareFloats(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+
+	return 0
+}
+
+func compareInts(x, y int64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+
+	return 0
+}
+
+func compareBools(x, y bool) int {
+	switch {
+	case x == y:
+		return 0
+	case y:
+		return -1
+	}
+
+	return 1
+}
+
+// typeOrder is the order of the BSON types in sorts, the values missing
+// sorting as null.
+func typeOrder(t bsontype.Type) int {
+	switch t {
+	case bsontype.MinKey:
+		return 0
+	case 0, bsontype.Null, bsontype.Undefined:
+		return 1
+	case bsontype.Double, bsontype.Int32, bsontype.Int64, bsontype.Decimal128:
+		return 2
+	case bsontype.String, bsontype.Symbol:
+		return 3
+	case bsontype.EmbeddedDocument:
+		return 4
+	case bsontype.Array:
+		return 5
+	case bsontype.Binary:
+		return 6
+	case bsontype.ObjectID:
+		return 7
+	case bsontype.Boolean:
+		return 8
+	case bsontype.DateTime:
+		return 9
+	case bsontype.Timestamp:
+		return 10
+	case bsontype.Regex:
+		return 11
+	case bsontype.MaxKey:
+		return 13
+	}
+
+	return 12
+}
+
+// sortKey sorts by a field, or by the text score when score is set.
+type sortKey struct {
+	path      []string
+	score     bool
+	direction int
+}
+
+type sortKeys []sortKey
+
+func compileSort(sorting interface{}) (sortKeys, error) {
+	if sorting == nil {
+		return nil, nil
+	}
+
+	raw, err := marshal(sorting)
+	if err != nil {
+		return nil, err
+	}
+
+	elements, err := raw.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(sortKeys, len(elements))
+	for i, e := range elements {
+		if isTextScore(e.Value()) {
+			keys[i] = sortKey{score: true, direction: -1}
+			continue
+		}
+
+		direction, is := number(e.Value())
+		if !is || (direction != 1 && direction != -1) {
+			return nil, fmt.Errorf("sort on %q by %s: %w", e.Key(), e.Value(), ErrUnsupported)
+		}
+
+		keys[i] = sortKey{path: strings.Split(e.Key(), "."), direction: int(direction)}
+	}
+
+	return keys, nil
+}
+
+func (sk sortKeys) less(a, b scored) bool {
+	for _, k := range sk {
+		var c int
+		if k.score {
+			c = compareFloats(a.score, b.score)
+		} else {
+			c = compareForSort(a.document.Lookup(k.path...), b.document.Lookup(k.path...))
+		}
+
+		if c != 0 {
+			return c*k.direction < 0
+		}
+	}
+
+	return false
+}
+
+func compareForSort(a, b bson.RawValue) int {
+	if x, y := typeOrder(a.Type), typeOrder(b.Type); x != y {
+		return x - y
+	}
+
+	if c, comparable := compare(a, b); comparable {
+		return c
+	}
+
+	return bytes.Compare(a.Value, b.Value)
+}
+
+// compileProjection only takes the text score, added to the whole document
+// under the given field.
+func compileProjection(projection interface{}) (func(scored) (bson.Raw, error), error) {
+	whole := func(s scored) (bson.Raw, error) {
+		return s.document, nil
+	}
+
+	if projection == nil {
+		return whole, nil
+	}
+
+	raw, err := marshal(projection)
+	if err != nil {
+		return nil, err
+	}
+
+	elements, err := raw.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	var scoreFields []string
+	for _, e := range elements {
+		if !isTextScore(e.Value()) {
+			return nil, fmt.Errorf("projection of %q: %w", e.Key(), ErrUnsupported)
+		}
+
+		scoreFields = append(scoreFields, e.Key())
+	}
+
+	if len(scoreFields) == 0 {
+		return whole, nil
+	}
+
+	return func(s scored) (bson.Raw, error) {
+		d, err := toD(s.document)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, field := range scoreFields {
+			d = append(d, bson.E{Key: field, Value: s.score})
+		}
+
+		return bson.Marshal(d)
+	}, nil
+}
+
+func isTextScore(value bson.RawValue) bool {
+	d, is := value.DocumentOK()
+	if !is {
+		return false
+	}
+
+	meta, err := d.LookupErr("$meta")
+	if err != nil {
+		return false
+	}
+
+	return meta.StringValue() == "textScore"
+}
+
+// textScore scores document against the terms the way the server does with
+// unit weights: every occurrence of a term counts half the previous one, and
+// the sum is weighted by how much of the field the term covers. Zero means
+// no term is in the document.
+func textScore(document bson.Raw, fields []string, searched []string) float64 {
+	wanted := make(map[string]bool, len(searched))
+	for _, t := range searched {
+		wanted[t] = true
+	}
+
+	var total float64
+	for _, field := range fields {
+		value, err := document.LookupErr(strings.Split(field, ".")...)
+		if err != nil {
+			continue
+		}
+
+		text, is := value.StringValueOK()
+		if !is {
+			continue
+		}
+
+		tokens := tokenize(text)
+
+		type frequency struct {
+			count int
+			freq  float64
+		}
+		found := make(map[string]*frequency)
+		for _, t := range tokens {
+			if !wanted[t] {
+				continue
+			}
+
+			f, exists := found[t]
+			if !exists {
+				f = &frequency{}
+				found[t] = f
+			}
+
+			f.freq += 1 / math.Pow(2, float64(f.count))
+			f.count++
+		}
+
+		for t, f := range found {
+			coefficient := 0.5*float64(f.count)/float64(len(tokens)) + 0.5
+
+			adjustment := 1.0
+			if strings.EqualFold(text, t) {
+				adjustment += 0.1
+			}
+
+			total += f.freq * coefficient * adjustment
+		}
+	}
+
+	return total
+}
+
+// terms returns the distinct words of a $text search, lower cased.
+func terms(search string) []string {
+	seen := make(map[string]bool)
+
+	var words []string
+	for _, t := range tokenize(search) {
+		if !seen[t] {
+			seen[t] = true
+			words = append(words, t)
+		}
+	}
+
+	return words
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,318 @@
+package mongotest
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type updateFunc func(document bson.Raw) (bson.Raw, error)
+
+// compileUpdate takes a document of $set and $unset operators, or a pipeline
+// of $set, $addFields and $unset stages.
+func compileUpdate(update interface{}) (updateFunc, error) {
+	var stages []interface{}
+	switch u := update.(type) {
+	case mongo.Pipeline:
+		for _, stage := range u {
+			stages = append(stages, stage)
+		}
+	case []bson.D:
+		for _, stage := range u {
+			stages = append(stages, stage)
+		}
+	case bson.A:
+		stages = u
+	case []interface{}:
+		stages = u
+	default:
+		raw, err := marshal(update)
+		if err != nil {
+			return nil, err
+		}
+
+		return operatorUpdate(raw)
+	}
+
+	steps := make([]updateFunc, len(stages))
+	for i, stage := range stages {
+		raw, err := marshal(stage)
+		if err != nil {
+			return nil, err
+		}
+
+		if steps[i], err = pipelineStage(raw); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(document bson.Raw) (bson.Raw, error) {
+		var err error
+		for _, step := range steps {
+			if document, err = step(document); err != nil {
+				return nil, err
+			}
+		}
+
+		return document, nil
+	}, nil
+}
+
+func operatorUpdate(update bson.Raw) (updateFunc, error) {
+	elements, err := update.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(elements) == 0 || !strings.HasPrefix(elements[0].Key(), "$") {
+		return nil, fmt.Errorf("replacement document: %w", ErrUnsupported)
+	}
+
+	type change struct {
+		field string
+		value bson.RawValue
+		unset bool
+	}
+
+	var changes []change
+	for _, e := range elements {
+		fields, is := e.Value().DocumentOK()
+		if !is {
+			return nil, fmt.Errorf("%s of type %s: %w", e.Key(), e.Value().Type, ErrUnsupported)
+		}
+
+		values, err := fields.Elements()
+		if err != nil {
+			return nil, err
+		}
+
+		switch e.Key() {
+		case "$set":
+			for _, v := range values {
+				changes = append(changes, change{field: v.Key(), value: v.Value()})
+			}
+		case "$unset":
+			for _, v := range values {
+				changes = append(changes, change{field: v.Key(), unset: true})
+			}
+		default:
+			return nil, fmt.Errorf("update operator %q: %w", e.Key(), ErrUnsupported)
+		}
+	}
+
+	return func(document bson.Raw) (bson.Raw, error) {
+		d, err := toD(document)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range changes {
+			if c.unset {
+				d, err = unsetField(d, c.field)
+			} else {
+				d, err = setField(d, c.field, c.value)
+			}
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return bson.Marshal(d)
+	}, nil
+}
+
+func pipelineStage(stage bson.Raw) (updateFunc, error) {
+	elements, err := stage.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(elements) != 1 {
+		return nil, fmt.Errorf("pipeline stage with %d keys: %w", len(elements), ErrUnsupported)
+	}
+
+	operand := elements[0].Value()
+
+	switch name := elements[0].Key(); name {
+	case "$set", "$addFields":
+		fields, is := operand.DocumentOK()
+		if !is {
+			return nil, fmt.Errorf("%s of type %s: %w", name, operand.Type, ErrUnsupported)
+		}
+
+		expressions, err := fields.Elements()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(document bson.Raw) (bson.Raw, error) {
+			d, err := toD(document)
+			if err != nil {
+				return nil, err
+			}
+
+			// Every expression sees the document as it was before the
+			// stage, as on the server.
+			for _, e := range expressions {
+				value, err := evaluate(document, e.Value())
+				if err != nil {
+					return nil, err
+				}
+
+				if d, err = setField(d, e.Key(), value); err != nil {
+					return nil, err
+				}
+			}
+
+			return bson.Marshal(d)
+		}, nil
+	case "$unset":
+		var fields []string
+		if field, is := operand.StringValueOK(); is {
+			fields = []string{field}
+		} else if list, is := operand.ArrayOK(); is {
+			values, err := list.Values()
+			if err != nil {
+				return nil, err
+			}
+
+			for _, v := range values {
+				field, is := v.StringValueOK()
+				if !is {
+					return nil, fmt.Errorf("$unset of type %s: %w", v.Type, ErrUnsupported)
+				}
+
+				fields = append(fields, field)
+			}
+		} else {
+			return nil, fmt.Errorf("$unset of type %s: %w", operand.Type, ErrUnsupported)
+		}
+
+		return func(document bson.Raw) (bson.Raw, error) {
+			d, err := toD(document)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, field := range fields {
+				if d, err = unsetField(d, field); err != nil {
+					return nil, err
+				}
+			}
+
+			return bson.Marshal(d)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("pipeline stage %q: %w", elements[0].Key(), ErrUnsupported)
+}
+
+// evaluate computes an aggregation expression over document. Only field
+// paths, $ifNull and $literal are understood, every other value is taken
+// as is.
+func evaluate(document bson.Raw, expression bson.RawValue) (bson.RawValue, error) {
+	if path, is := expression.StringValueOK(); is && strings.HasPrefix(path, "$") {
+		if strings.HasPrefix(path, "$$") {
+			return bson.RawValue{}, fmt.Errorf("variable %q: %w", path, ErrUnsupported)
+		}
+
+		value, err := document.LookupErr(strings.Split(path[1:], ".")...)
+		if err != nil {
+			return null(), nil
+		}
+
+		return value, nil
+	}
+
+	operators, is := operatorDocument(expression)
+	if !is {
+		return expression, nil
+	}
+
+	elements, err := operators.Elements()
+	if err != nil {
+		return bson.RawValue{}, err
+	}
+
+	if len(elements) != 1 {
+		return bson.RawValue{}, fmt.Errorf("expression with %d operators: %w", len(elements), ErrUnsupported)
+	}
+
+	operand := elements[0].Value()
+
+	switch name := elements[0].Key(); name {
+	case "$literal":
+		return operand, nil
+	case "$ifNull":
+		arguments, is := operand.ArrayOK()
+		if !is {
+			return bson.RawValue{}, fmt.Errorf("$ifNull of type %s: %w", operand.Type, ErrUnsupported)
+		}
+
+		values, err := arguments.Values()
+		if err != nil {
+			return bson.RawValue{}, err
+		}
+
+		for i, argument := range values {
+			value, err := evaluate(document, argument)
+			if err != nil {
+				return bson.RawValue{}, err
+			}
+
+			if value.Type != bsontype.Null || i == len(values)-1 {
+				return value, nil
+			}
+		}
+
+		return null(), nil
+	}
+
+	return bson.RawValue{}, fmt.Errorf("expression operator %q: %w", elements[0].Key(), ErrUnsupported)
+}
+
+func null() bson.RawValue {
+	return bson.RawValue{Type: bsontype.Null}
+}
+
+func setField(d bson.D, field string, value bson.RawValue) (bson.D, error) {
+	if strings.Contains(field, ".") {
+		return nil, fmt.Errorf("nested field %q: %w", field, ErrUnsupported)
+	}
+
+	if field == idField {
+		return nil, ErrImmutableID
+	}
+
+	for i, e := range d {
+		if e.Key == field {
+			d[i].Value = value
+			return d, nil
+		}
+	}
+
+	return append(d, bson.E{Key: field, Value: value}), nil
+}
+
+func unsetField(d bson.D, field string) (bson.D, error) {
+	if strings.Contains(field, ".") {
+		return nil, fmt.Errorf("nested field %q: %w", field, ErrUnsupported)
+	}
+
+	if field == idField {
+		return nil, ErrImmutableID
+	}
+
+	kept := d[:0]
+	for _, e := range d {
+		if e.Key != field {
+			kept = append(kept, e)
+		}
+	}
+
+	return kept, nil
+}
@@ -0,0 +1,106 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"clean-arquitecture-template/internal/domain/example"
+	"clean-arquitecture-template/internal/interfaceadapters/example/storage/memory"
+)
+
+// Test_RoundTripMatchesMemory writes the same lines to mongo and to every
+// memory implementation and expects all of them to read back the same
+// lines. The times are in UTC and to the millisecond, which is what a BSON
+// date holds.
+func Test_RoundTripMatchesMemory(t *testing.T) {
+	created := time.Date(2018, time.September, 16, 12, 0, 0, 123000000, time.UTC)
+	deleted := time.Date(2019, time.March, 1, 8, 30, 15, 987000000, time.UTC)
+
+	testCases := []struct {
+		name  string
+		input example.Line
+	}{
+		{
+			name:  "live-line-case",
+			input: example.Line{Created: created, Data: "a live line"},
+		},
+		{
+			name:  "deleted-line-case",
+			input: example.Line{Created: created, Data: "a deleted line", DeletedAt: deleted},
+		},
+		{
+			name:  "empty-data-case",
+			input: example.Line{Created: created},
+		},
+	}
+
+	stores := make(map[string]memory.Repository)
+	for _, impl := range []string{memory.ChannelImplementation, memory.ShardedImplementation} {
+		cnf, err := memory.ReadConfig(configReaderMock{
+			f: func(node string) (io.Reader, error) {
+				return strings.NewReader(fmt.Sprintf(`{"implementation": %q}`, impl)), nil
+			},
+		})
+		require.NoError(t, err)
+
+		stores[impl] = memory.New(context.Background(), cnf)
+	}
+
+	for _, c := range testCases {
+		mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+		defer mt.Close()
+
+		input := c.input
+
+		mt.Run(c.name, func(mt *mtest.T) {
+			ctx := context.Background()
+			st := store{ctx: ctx, collection: mt.Coll}
+
+			mongoInput := input
+			mongoInput.ID = NewIdentityProvider().NewID()
+
+			mt.AddMockResponses(mtest.CreateSuccessResponse())
+			require.NoError(t, st.Write(ctx, mongoInput))
+
+			inserted := mt.GetStartedEvent().Command.Lookup("documents").Array().Index(0).Value().Document()
+
+			var document bson.D
+			require.NoError(t, bson.Unmarshal(inserted, &document))
+
+			ns := fmt.Sprintf("%s.%s", mt.Coll.Database().Name(), mt.Coll.Name())
+			mt.AddMockResponses(
+				mtest.CreateCursorResponse(1, ns, mtest.FirstBatch, document),
+				mtest.CreateCursorResponse(0, ns, mtest.NextBatch),
+			)
+
+			fromMongo, err := st.Read(ctx, mongoInput.ID)
+			require.NoError(t, err)
+			require.NotNil(t, fromMongo)
+			assert.Equal(t, mongoInput, *fromMongo)
+
+			for impl, repo := range stores {
+				memoryInput := input
+				memoryInput.ID = memory.NewID()
+
+				require.NoError(t, repo.Write(ctx, memoryInput))
+
+				fromMemory, err := repo.Read(ctx, memoryInput.ID)
+				require.NoError(t, err)
+				require.NotNil(t, fromMemory)
+				assert.Equal(t, memoryInput, *fromMemory, impl)
+
+				fromMemory.ID, fromMongo.ID = nil, nil
+				assert.Equal(t, fromMongo, fromMemory, impl)
+			}
+		})
+	}
+}
@@ -0,0 +1,556 @@
+// Package repotest checks that a line repository keeps the contract of
+// example.LineRepository. Every storage adapter runs the same suite, so the
+// services behave the same whichever store is behind them.
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"clean-arquitecture-template/internal/domain/example"
+	"clean-arquitecture-template/internal/interfaceadapters/example/identity"
+)
+
+const (
+	concurrentWriters int = 8
+	writesPerWriter   int = 25
+
+	snowflakeNode int64 = 1
+)
+
+// Factory builds an empty repository, with the provider of its identifiers,
+// for every test of the suite. The repository is released with t.Cleanup.
+type Factory func(t *testing.T) (example.LineRepository, example.IdentityProvider)
+
+// tstamp is the creation time of the lines of the suite. The times are in UTC
+// and to the millisecond, which every store keeps as is.
+var tstamp = time.Date(2018, time.September, 16, 12, 0, 0, 0, time.UTC)
+
+// Run runs the whole suite against the repositories of factory. Search is
+// only checked when the repository is an example.Searcher.
+func Run(t *testing.T, factory Factory) {
+	testCases := []struct {
+		name string
+		test func(t *testing.T, repo example.LineRepository, ids example.IdentityProvider)
+	}{
+		{name: "round-trip", test: testRoundTrip},
+		{name: "write-many-read-many", test: testWriteManyReadMany},
+		{name: "duplicate-write", test: testDuplicateWrite},
+		{name: "not-found", test: testNotFound},
+		{name: "invalid-identifier", test: testInvalidIdentifier},
+		{name: "expired-context", test: testExpiredContext},
+		{name: "concurrent-writes", test: testConcurrentWrites},
+		{name: "scan", test: testScan},
+		{name: "find", test: testFind},
+		{name: "delete-restore", test: testDeleteRestore},
+		{name: "purge", test: testPurge},
+		{name: "search", test: testSearch},
+	}
+
+	for _, c := range testCases {
+		test := c.test
+
+		t.Run(c.name, func(t *testing.T) {
+			repo, ids := factory(t)
+			test(t, repo, ids)
+		})
+	}
+}
+
+// RunWithIdentities runs the suite once for every identity strategy, so the
+// repositories of newRepo are checked to store identifiers of any kind.
+func RunWithIdentities(t *testing.T, newRepo func(t *testing.T) example.LineRepository) {
+	for _, strategy := range identity.Strategies {
+		strategy := strategy
+
+		t.Run(strategy, func(t *testing.T) {
+			Run(t, func(t *testing.T) (example.LineRepository, example.IdentityProvider) {
+				ids, err := identity.NewProvider(strategy, snowflakeNode)
+				require.NoError(t, err)
+
+				return newRepo(t), ids
+			})
+		})
+	}
+}
+
+func newLines(ids example.IdentityProvider, data ...string) []example.Line {
+	lines := make([]example.Line, len(data))
+	for i, d := range data {
+		lines[i] = example.Line{
+			ID:      ids.NewID(),
+			Created: tstamp.Add(time.Duration(i) * time.Millisecond),
+			Data:    d,
+		}
+	}
+
+	return lines
+}
+
+func writeAll(t *testing.T, repo example.LineRepository, lines []example.Line) {
+	for _, err := range repo.WriteMany(context.Background(), lines) {
+		require.NoError(t, err)
+	}
+}
+
+func testRoundTrip(t *testing.T, repo example.LineRepository, ids example.IdentityProvider) {
+	ctx := context.Background()
+
+	for _, input := range []example.Line{
+		{ID: ids.NewID(), Created: tstamp.Add(123 * time.Millisecond), Data: "a live line"},
+		{ID: ids.NewID(), Created: tstamp, Data: "a deleted line", DeletedAt: tstamp.Add(time.Hour)},
+		{ID: ids.NewID(), Created: tstamp},
+	} {
+		require.NoError(t, repo.Write(ctx, input))
+
+		read, err := repo.Read(ctx, input.ID)
+		require.NoError(t, err)
+		require.NotNil(t, read)
+		assert.Equal(t, example.IDOf(input.ID), example.IDOf(read.ID))
+		assert.Equal(t, input.Data, read.Data)
+		assert.True(t, input.Created.Equal(read.Created), "created %s, read %s", input.Created, read.Created)
+		assert.True(t, input.DeletedAt.Equal(read.DeletedAt), "deleted %s, read %s", input.DeletedAt, read.DeletedAt)
+
+		parsed, err := ids.ParseID(input.ID.String())
+		require.NoError(t, err)
+
+		again, err := repo.Read(ctx, parsed)
+		require.NoError(t, err)
+		assert.Equal(t, read, again, "a parsed identifier reads the same line")
+	}
+}
+
+func testWriteManyReadMany(t *testing.T, repo example.LineRepository, ids example.IdentityProvider) {
+	ctx := context.Background()
+	lines := newLines(ids, "first line", "second line", "third line")
+
+	assert.Equal(t, []error{nil, nil, nil}, repo.WriteMany(ctx, lines))
+	assert.Empty(t, repo.WriteMany(ctx, nil))
+
+	missing := ids.NewID()
+	read, err := repo.ReadMany(ctx, []example.Identifier{lines[2].ID, missing, lines[0].ID, lines[1].ID})
+	require.NoError(t, err)
+	require.Len(t, read, 4)
+
+	for i, expected := range []*example.Line{&lines[2], nil, &lines[0], &lines[1]} {
+		if expected == nil {
+			assert.Nil(t, read[i])
+			continue
+		}
+
+		require.NotNil(t, read[i])
+		assert.Equal(t, example.IDOf(expected.ID), example.IDOf(read[i].ID))
+		assert.Equal(t, expected.Data, read[i].Data)
+	}
+
+	read, err = repo.ReadMany(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, read)
+}
+
+// testDuplicateWrite checks an identifier is written once, the lines
+// written again with it fail and leave the stored line as it was.
+func testDuplicateWrite(t *testing.T, repo example.LineRepository, ids example.IdentityProvider) {
+	ctx := context.Background()
+	lines := newLines(ids, "first line", "second line", "third line")
+
+	require.NoError(t, repo.Write(ctx, lines[0]))
+
+	again := lines[0]
+	again.Data = "overwritten line"
+	assert.ErrorIs(t, repo.Write(ctx, again), example.ErrDuplicate)
+
+	twice := lines[2]
+	twice.Data = "third line again"
+
+	errs := repo.WriteMany(ctx, []example.Line{lines[1], again, lines[2], twice})
+	require.Len(t, errs, 4)
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], example.ErrDuplicate)
+	assert.NoError(t, errs[2])
+	assert.ErrorIs(t, errs[3], example.ErrDuplicate)
+
+	read, err := repo.ReadMany(ctx, []example.Identifier{lines[0].ID, lines[1].ID, lines[2].ID})
+	require.NoError(t, err)
+	require.Len(t, read, 3)
+
+	for i, expected := range lines {
+		require.NotNil(t, read[i])
+		assert.Equal(t, expected.Data, read[i].Data)
+	}
+
+	if searcher, is := repo.(example.Searcher); is {
+		found, err := searcher.Search(ctx, example.SearchQuery{Text: "overwritten", Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, found.Hits, "a rejected line is not indexed")
+	}
+}
+
+func testNotFound(t *testing.T, repo example.LineRepository, ids example.IdentityProvider) {
+	ctx := context.Background()
+	missing := ids.NewID()
+
+	read, err := repo.Read(ctx, missing)
+	assert.NoError(t, err)
+	assert.Nil(t, read)
+
+	found, err := repo.Delete(ctx, missing, tstamp)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	found, err = repo.Restore(ctx, missing)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func testInvalidIdentifier(t *testing.T, repo example.LineRepository, ids example.IdentityProvider) {
+	for _, key := range []string{"", "not an identifier", "0123"} {
+		id, err := ids.ParseID(key)
+		assert.Error(t, err, "key %q", key)
+		assert.Nil(t, id, "key %q", key)
+	}
+}
+
+func testExpiredContext(t *testing.T, repo example.LineRepository, ids example.IdentityProvider) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	lines := newLines(ids, "expired line", "expired batch line")
+
+	assert.ErrorIs(t, repo.Write(ctx, lines[0]), example.ErrTimeout)
+
+	for _, err := range repo.WriteMany(ctx, lines[1:]) {
+		assert.ErrorIs(t, err, example.ErrTimeout)
+	}
+
+	_, err := repo.Read(ctx, lines[0].ID)
+	assert.ErrorIs(t, err, example.ErrTimeout)
+
+	_, err = repo.ReadMany(ctx, []example.Identifier{lines[0].ID})
+	assert.ErrorIs(t, err, example.ErrTimeout)
+
+	_, err = repo.Find(ctx, example.LineSpecification{})
+	assert.ErrorIs(t, err, example.ErrTimeout)
+
+	_, err = repo.Delete(ctx, lines[0].ID, tstamp)
+	assert.ErrorIs(t, err, example.ErrTimeout)
+
+	_, err = repo.Purge(ctx, tstamp)
+	assert.ErrorIs(t, err, example.ErrTimeout)
+
+	read, err := repo.ReadMany(context.Background(), []example.Identifier{lines[0].ID, lines[1].ID})
+	require.NoError(t, err)
+	assert.Equal(t, []*example.Line{nil, nil}, read, "the lines of an expired request are not written")
+}
+
+func testConcurrentWrites(t *testing.T, repo example.LineRepository, ids example.IdentityProvider) {
+	ctx := context.Background()
+
+	written := make([][]example.Line, concurrentWriters)
+	for w := range written {
+		data := make([]string, writesPerWriter)
+		for i := range data {
+			data[i] = fmt.Sprintf("writer %d line %d", w, i)
+		}
+
+		written[w] = newLines(ids, data...)
+	}
+
+	var wg sync.WaitGroup
+	for w := range written {
+		wg.Add(1)
+
+		go func(lines []example.Line) {
+			defer wg.Done()
+
+			half := len(lines) / 2
+			for _, l := range lines[:half] {
+				assert.NoError(t, repo.Write(ctx, l))
+			}
+
+			for _, err := range repo.WriteMany(ctx, lines[half:]) {
+				assert.NoError(t, err)
+			}
+		}(written[w])
+	}
+
+	wg.Wait()
+
+	for _, lines := range written {
+		keys := make([]example.Identifier, len(lines))
+		for i, l := range lines {
+			keys[i] = l.ID
+		}
+
+		read, err := repo.ReadMany(ctx, keys)
+		require.NoError(t, err)
+
+		for i, l := range read {
+			require.NotNil(t, l, "line %s", keys[i])
+			assert.Equal(t, lines[i].Data, l.Data)
+		}
+	}
+
+	assert.Len(t, scanAll(t, repo), concurrentWriters*writesPerWriter)
+}
+
+func scanAll(t *testing.T, repo example.LineRepository) map[example.ID]example.Line {
+	ctx := context.Background()
+
+	cursor, err := repo.Scan(ctx)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cursor.Close(ctx))
+	}()
+
+	scanned := make(map[example.ID]example.Line)
+	for cursor.Next(ctx) {
+		l := cursor.Line()
+
+		_, seen := scanned[example.IDOf(l.ID)]
+		assert.False(t, seen, "line %s scanned twice", l.ID)
+
+		scanned[example.IDOf(l.ID)] = l
+	}
+
+	require.NoError(t, cursor.Err())
+
+	return scanned
+}
+
+func testScan(t *testing.T, repo example.LineRepository, ids example.IdentityProvider) {
+	ctx := context.Background()
+
+	assert.Empty(t, scanAll(t, repo))
+
+	lines := newLines(ids, "first line", "second line", "third line")
+	writeAll(t, repo, lines)
+
+	found, err := repo.Delete(ctx, lines[1].ID, tstamp.Add(time.Hour))
+	require.NoError(t, err)
+	require.True(t, found)
+
+	scanned := scanAll(t, repo)
+	require.Len(t, scanned, len(lines), "scan walks the deleted lines too")
+
+	for _, l := range lines {
+		assert.Equal(t, l.Data, scanned[example.IDOf(l.ID)].Data)
+	}
+
+	assert.True(t, scanned[example.IDOf(lines[1].ID)].Deleted())
+}
+
+func testFind(t *testing.T, repo example.LineRepository, ids example.IdentityProvider) {
+	ctx := context.Background()
+
+	lines := newLines(ids, "apple pie", "apple tart", "banana split", "apple crumble")
+	writeAll(t, repo, lines)
+
+	found, err := repo.Delete(ctx, lines[3].ID, tstamp.Add(time.Hour))
+	require.NoError(t, err)
+	require.True(t, found)
+
+	testCases := []struct {
+		name     string
+		spec     example.LineSpecification
+		expected []int
+	}{
+		{
+			name:     "everything-case",
+			spec:     example.LineSpecification{},
+			expected: []int{0, 1, 2},
+		},
+		{
+			name:     "prefix-case",
+			spec:     example.LineSpecification{DataPrefix: "apple"},
+			expected: []int{0, 1},
+		},
+		{
+			name:     "include-deleted-case",
+			spec:     example.LineSpecification{DataPrefix: "apple", IncludeDeleted: true},
+			expected: []int{0, 1, 3},
+		},
+		{
+			name:     "descending-limit-case",
+			spec:     example.LineSpecification{Sort: example.SortDescending, Limit: 2},
+			expected: []int{2, 1},
+		},
+		{
+			name: "created-range-case",
+			spec: example.LineSpecification{
+				CreatedAfter:  lines[1].Created,
+				CreatedBefore: lines[2].Created,
+			},
+			expected: []int{1},
+		},
+		{
+			name:     "no-match-case",
+			spec:     example.LineSpecification{DataPrefix: "cherry"},
+			expected: []int{},
+		},
+	}
+
+	for _, c := range testCases {
+		spec := c.spec
+		expected := make([]string, len(c.expected))
+		for i, index := range c.expected {
+			expected[i] = lines[index].Data
+		}
+
+		t.Run(c.name, func(t *testing.T) {
+			matches, err := repo.Find(ctx, spec)
+			require.NoError(t, err)
+
+			data := make([]string, len(matches))
+			for i, m := range matches {
+				data[i] = m.Data
+			}
+
+			assert.Equal(t, expected, data)
+		})
+	}
+}
+
+func testDeleteRestore(t *testing.T, repo example.LineRepository, ids example.IdentityProvider) {
+	ctx := context.Background()
+
+	lines := newLines(ids, "kept line")
+	writeAll(t, repo, lines)
+	id := lines[0].ID
+
+	deleted := tstamp.Add(time.Hour)
+	found, err := repo.Delete(ctx, id, deleted)
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	found, err = repo.Delete(ctx, id, deleted.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	read, err := repo.Read(ctx, id)
+	require.NoError(t, err)
+	require.NotNil(t, read)
+	assert.True(t, deleted.Equal(read.DeletedAt), "a second delete keeps the first time")
+
+	found, err = repo.Restore(ctx, id)
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	read, err = repo.Read(ctx, id)
+	require.NoError(t, err)
+	require.NotNil(t, read)
+	assert.False(t, read.Deleted())
+	assert.Equal(t, "kept line", read.Data)
+
+	found, err = repo.Restore(ctx, id)
+	require.NoError(t, err)
+	assert.True(t, found, "restoring a live line finds it")
+}
+
+func testPurge(t *testing.T, repo example.LineRepository, ids example.IdentityProvider) {
+	ctx := context.Background()
+
+	lines := newLines(ids, "live line", "deleted long ago", "deleted lately")
+	writeAll(t, repo, lines)
+
+	for i, at := range map[int]time.Time{1: tstamp.Add(time.Hour), 2: tstamp.Add(48 * time.Hour)} {
+		found, err := repo.Delete(ctx, lines[i].ID, at)
+		require.NoError(t, err)
+		require.True(t, found)
+	}
+
+	purged, err := repo.Purge(ctx, tstamp.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, purged, 1)
+	assert.Equal(t, example.IDOf(lines[1].ID), example.IDOf(purged[0].ID))
+	assert.Equal(t, lines[1].Data, purged[0].Data)
+	assert.True(t, tstamp.Add(time.Hour).Equal(purged[0].DeletedAt), "the purged line is returned as it was")
+
+	read, err := repo.ReadMany(ctx, []example.Identifier{lines[0].ID, lines[1].ID, lines[2].ID})
+	require.NoError(t, err)
+	require.Len(t, read, 3)
+	assert.NotNil(t, read[0])
+	assert.Nil(t, read[1], "the line deleted before the time is gone")
+	assert.NotNil(t, read[2])
+
+	purged, err = repo.Purge(ctx, tstamp.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, purged)
+}
+
+func testSearch(t *testing.T, repo example.LineRepository, ids example.IdentityProvider) {
+	searcher, is := repo.(example.Searcher)
+	if !is {
+		t.Skip("the repository does not search")
+	}
+
+	ctx := context.Background()
+
+	lines := newLines(ids, "red apple", "green apple", "red cherry", "apple apple pie")
+	writeAll(t, repo, lines)
+
+	found, err := repo.Delete(ctx, lines[1].ID, tstamp.Add(time.Hour))
+	require.NoError(t, err)
+	require.True(t, found)
+
+	hits := func(query example.SearchQuery) (int64, []string) {
+		result, err := searcher.Search(ctx, query)
+		require.NoError(t, err)
+
+		data := make([]string, len(result.Hits))
+		for i, hit := range result.Hits {
+			data[i] = hit.Line.Data
+		}
+
+		return result.Total, data
+	}
+
+	total, data := hits(example.SearchQuery{Text: "apple"})
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, "apple apple pie", data[0], "the line with more occurrences comes first")
+	assert.ElementsMatch(t, []string{"red apple", "apple apple pie"}, data)
+
+	total, data = hits(example.SearchQuery{Text: "apple", IncludeDeleted: true})
+	assert.Equal(t, int64(3), total)
+	sort.Strings(data)
+	assert.Equal(t, []string{"apple apple pie", "green apple", "red apple"}, data)
+
+	total, data = hits(example.SearchQuery{Text: "apple", Offset: 1, Limit: 1})
+	assert.Equal(t, int64(2), total, "the total counts every match, not only the page")
+	assert.Equal(t, []string{"red apple"}, data)
+
+	total, data = hits(example.SearchQuery{Text: "banana"})
+	assert.Zero(t, total)
+	assert.Empty(t, data)
+
+	phrases := []struct {
+		text     string
+		expected []string
+	}{
+		{text: `"apple pie"`, expected: []string{"apple apple pie"}},
+		{text: `"RED Apple"`, expected: []string{"red apple"}},
+		{text: `cherry "red apple"`, expected: []string{"red apple"}},
+		{text: `"red apple" "apple pie"`, expected: []string{}},
+		{text: `"apple red"`, expected: []string{}},
+		{text: `"pie apple"`, expected: []string{}},
+		{text: `"red app"`, expected: []string{}},
+		{text: `"red apple`, expected: []string{"red apple"}},
+	}
+
+	for _, p := range phrases {
+		total, data = hits(example.SearchQuery{Text: p.text})
+		assert.Equal(t, int64(len(p.expected)), total, "query %s", p.text)
+		assert.ElementsMatch(t, p.expected, data, "query %s", p.text)
+	}
+
+	total, data = hits(example.SearchQuery{Text: "apples"})
+	assert.Zero(t, total, "the terms are not stemmed")
+	assert.Empty(t, data)
+}
@@ -0,0 +1,135 @@
+package resilience
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type breakerState int
+
+const (
+	closed breakerState = iota
+	open
+	halfOpen
+)
+
+type outcome int
+
+const (
+	succeeded outcome = iota
+	failed
+	// abandoned calls were given up by their caller, they tell nothing
+	// about the storage.
+	abandoned
+)
+
+// breaker opens after Failures transient errors in a row and rejects every
+// call until Cooldown has passed. It then lets one call through, which
+// closes it again when it does not fail with a transient error.
+type breaker struct {
+	failures int
+	cooldown time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	state    breakerState
+	failed   int
+	openedAt time.Time
+	trying   bool
+}
+
+func NewBreaker(cnf BreakerConfig) Policy {
+	return &breaker{
+		failures: cnf.Failures,
+		cooldown: cnf.Cooldown,
+		now:      time.Now,
+	}
+}
+
+func (b *breaker) Do(ctx context.Context, op func(context.Context) error) error {
+	allowed, trial := b.allow()
+	if !allowed {
+		return ErrCircuitOpen
+	}
+
+	err := op(ctx)
+
+	switch {
+	case ctx.Err() != nil:
+		b.record(abandoned, trial)
+	case Transient(err):
+		b.record(failed, trial)
+	default:
+		b.record(succeeded, trial)
+	}
+
+	return err
+}
+
+// allow reports whether a call may run, and whether it is the one trying
+// the storage after the cooldown.
+func (b *breaker) allow() (bool, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case open:
+		if b.now().Before(b.openedAt.Add(b.cooldown)) {
+			return false, false
+		}
+
+		b.state = halfOpen
+		b.trying = true
+
+		return true, true
+	case halfOpen:
+		if b.trying {
+			return false, false
+		}
+
+		b.trying = true
+
+		return true, true
+	default:
+		return true, false
+	}
+}
+
+func (b *breaker) record(o outcome, trial bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == halfOpen {
+		if !trial {
+			return
+		}
+
+		b.trying = false
+
+		switch o {
+		case failed:
+			b.state = open
+			b.openedAt = b.now()
+		case succeeded:
+			b.state = closed
+			b.failed = 0
+		}
+
+		return
+	}
+
+	switch o {
+	case abandoned:
+		return
+	case succeeded:
+		b.failed = 0
+		return
+	}
+
+	b.failed++
+	if b.state == closed && b.failed >= b.failures {
+		b.state = open
+		b.openedAt = b.now()
+	}
+}
@@ -0,0 +1,82 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+func Test_BreakerDo(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, time.May, 1, 12, 0, 0, 0, time.UTC)
+
+	b := NewBreaker(BreakerConfig{Failures: 2, Cooldown: time.Minute}).(*breaker)
+	b.now = func() time.Time { return now }
+
+	calls := 0
+	fail := func(err error) func(context.Context) error {
+		return func(context.Context) error {
+			calls++
+			return err
+		}
+	}
+
+	// Errors that are not transient do not count.
+	assert.Error(t, b.Do(ctx, fail(errors.New("duplicate key"))))
+	assert.ErrorIs(t, b.Do(ctx, fail(example.ErrTimeout)), example.ErrTimeout)
+	assert.NoError(t, b.Do(ctx, fail(nil)))
+	assert.ErrorIs(t, b.Do(ctx, fail(example.ErrTimeout)), example.ErrTimeout)
+	assert.Equal(t, closed, b.state)
+
+	assert.ErrorIs(t, b.Do(ctx, fail(example.ErrUnavailable)), example.ErrUnavailable)
+	assert.Equal(t, open, b.state)
+
+	assert.ErrorIs(t, b.Do(ctx, fail(nil)), ErrCircuitOpen)
+	assert.Equal(t, 5, calls)
+
+	// The trial after the cooldown opens it again when it fails.
+	now = now.Add(time.Minute)
+	assert.ErrorIs(t, b.Do(ctx, fail(example.ErrTimeout)), example.ErrTimeout)
+	assert.Equal(t, open, b.state)
+	assert.ErrorIs(t, b.Do(ctx, fail(nil)), ErrCircuitOpen)
+
+	// And closes it when it does not.
+	now = now.Add(time.Minute)
+	assert.NoError(t, b.Do(ctx, fail(nil)))
+	assert.Equal(t, closed, b.state)
+	assert.Equal(t, 7, calls)
+}
+
+func Test_BreakerHalfOpenLetsOneTrialThrough(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, time.May, 1, 12, 0, 0, 0, time.UTC)
+
+	b := NewBreaker(BreakerConfig{Failures: 1, Cooldown: time.Minute}).(*breaker)
+	b.now = func() time.Time { return now }
+
+	assert.ErrorIs(t, b.Do(ctx, func(context.Context) error { return example.ErrTimeout }), example.ErrTimeout)
+
+	now = now.Add(time.Minute)
+	err := b.Do(ctx, func(context.Context) error {
+		assert.ErrorIs(t, b.Do(ctx, func(context.Context) error { return nil }), ErrCircuitOpen)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, closed, b.state)
+}
+
+func Test_BreakerIgnoresAbandonedCalls(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	b := NewBreaker(BreakerConfig{Failures: 1, Cooldown: time.Minute}).(*breaker)
+
+	assert.ErrorIs(t, b.Do(ctx, func(context.Context) error { return example.ErrTimeout }), example.ErrTimeout)
+	assert.Equal(t, closed, b.state)
+}
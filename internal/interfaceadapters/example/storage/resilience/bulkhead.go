@@ -0,0 +1,53 @@
+package resilience
+
+import (
+	"context"
+	"time"
+)
+
+// bulkhead runs at most MaxConcurrent calls at once, a call waits up to
+// Wait for a slot and is rejected when none frees up.
+type bulkhead struct {
+	slots chan struct{}
+	wait  time.Duration
+}
+
+func NewBulkhead(cnf BulkheadConfig) Policy {
+	return bulkhead{
+		slots: make(chan struct{}, cnf.MaxConcurrent),
+		wait:  cnf.Wait,
+	}
+}
+
+func (b bulkhead) Do(ctx context.Context, op func(context.Context) error) error {
+	if err := b.acquire(ctx); err != nil {
+		return err
+	}
+	defer func() { <-b.slots }()
+
+	return op(ctx)
+}
+
+func (b bulkhead) acquire(ctx context.Context) error {
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if b.wait <= 0 {
+		return ErrBulkheadFull
+	}
+
+	timer := time.NewTimer(b.wait)
+	defer timer.Stop()
+
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return ErrBulkheadFull
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
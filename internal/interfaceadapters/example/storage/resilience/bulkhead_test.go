@@ -0,0 +1,108 @@
+package resilience
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BulkheadDo(t *testing.T) {
+	testCases := []struct {
+		name          string
+		wait          time.Duration
+		ctx           func() context.Context
+		expectedError error
+	}{
+		{
+			name:          "no-wait-case",
+			ctx:           context.Background,
+			expectedError: ErrBulkheadFull,
+		},
+		{
+			name:          "wait-expired-case",
+			wait:          time.Millisecond,
+			ctx:           context.Background,
+			expectedError: ErrBulkheadFull,
+		},
+		{
+			name: "context-done-case",
+			wait: time.Minute,
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				return ctx
+			},
+			expectedError: context.Canceled,
+		},
+	}
+
+	for _, c := range testCases {
+		wait := c.wait
+		ctx := c.ctx()
+		expectedError := c.expectedError
+
+		t.Run(c.name, func(t *testing.T) {
+			b := NewBulkhead(BulkheadConfig{MaxConcurrent: 1, Wait: wait})
+
+			err := b.Do(context.Background(), func(context.Context) error {
+				return b.Do(ctx, func(context.Context) error {
+					t.Error("call let through a full bulkhead")
+					return nil
+				})
+			})
+
+			assert.ErrorIs(t, err, expectedError)
+
+			// The slot is given back once the call is done.
+			assert.NoError(t, b.Do(context.Background(), func(context.Context) error { return nil }))
+		})
+	}
+}
+
+func Test_BulkheadLimitsConcurrency(t *testing.T) {
+	const (
+		limit   = 3
+		callers = 20
+	)
+
+	b := NewBulkhead(BulkheadConfig{MaxConcurrent: limit, Wait: time.Minute})
+
+	var (
+		mu       sync.Mutex
+		running  int
+		observed int
+		wg       sync.WaitGroup
+	)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			assert.NoError(t, b.Do(context.Background(), func(context.Context) error {
+				mu.Lock()
+				running++
+				if running > observed {
+					observed = running
+				}
+				mu.Unlock()
+
+				time.Sleep(time.Millisecond)
+
+				mu.Lock()
+				running--
+				mu.Unlock()
+
+				return nil
+			}))
+		}()
+	}
+
+	wg.Wait()
+
+	assert.LessOrEqual(t, observed, limit)
+}
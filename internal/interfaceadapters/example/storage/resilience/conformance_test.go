@@ -0,0 +1,33 @@
+package resilience
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"clean-arquitecture-template/internal/domain/example"
+	"clean-arquitecture-template/internal/interfaceadapters/example/storage/memory"
+	"clean-arquitecture-template/internal/interfaceadapters/example/storage/repotest"
+)
+
+func Test_Conformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) (example.LineRepository, example.IdentityProvider) {
+		cnf, err := memory.ReadConfig(configReaderMock{
+			f: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"implementation": "sharded", "shards": 4}`), nil
+			},
+		})
+		require.NoError(t, err)
+
+		guarded := NewRepository(memory.NewShardedRepo(cnf),
+			noWaitRetry(3),
+			NewBreaker(BreakerConfig{Failures: 5, Cooldown: time.Minute}),
+			NewBulkhead(BulkheadConfig{MaxConcurrent: 4, Wait: time.Minute}),
+		)
+
+		return guarded, memory.NewIdentityProvider()
+	})
+}
@@ -0,0 +1,156 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+// repository runs every call to the repository it wraps through its
+// policies. A Scan only has the opening of its cursor guarded, and WriteMany
+// tries again only the lines that failed with a transient error. A write
+// tried again after a transient error may have been stored by the try that
+// failed, so a duplicate of its identifier is taken as the line written.
+type repository struct {
+	repo   example.LineRepository
+	policy Policy
+}
+
+// NewRepository wraps repo with policies, the first one outermost.
+func NewRepository(repo example.LineRepository, policies ...Policy) example.LineRepository {
+	return repository{
+		repo:   repo,
+		policy: chain(policies),
+	}
+}
+
+func (r repository) Write(ctx context.Context, line example.Line) error {
+	retried := false
+
+	return r.policy.Do(ctx, func(ctx context.Context) error {
+		err := r.repo.Write(ctx, line)
+		if retried && errors.Is(err, example.ErrDuplicate) {
+			return nil
+		}
+
+		retried = true
+
+		return err
+	})
+}
+
+func (r repository) Read(ctx context.Context, id example.Identifier) (line *example.Line, err error) {
+	err = r.policy.Do(ctx, func(ctx context.Context) error {
+		line, err = r.repo.Read(ctx, id)
+		return err
+	})
+
+	return line, err
+}
+
+func (r repository) WriteMany(ctx context.Context, lines []example.Line) []error {
+	errs := make([]error, len(lines))
+
+	pending := make([]int, len(lines))
+	for i := range pending {
+		pending[i] = i
+	}
+
+	retried := false
+
+	err := r.policy.Do(ctx, func(ctx context.Context) error {
+		batch := make([]example.Line, len(pending))
+		for j, i := range pending {
+			batch[j] = lines[i]
+		}
+
+		var (
+			again     []int
+			transient error
+		)
+		for j, werr := range r.repo.WriteMany(ctx, batch) {
+			if retried && errors.Is(werr, example.ErrDuplicate) {
+				werr = nil
+			}
+
+			i := pending[j]
+			errs[i] = werr
+
+			if Transient(werr) {
+				again = append(again, i)
+				transient = werr
+			}
+		}
+
+		pending = again
+		retried = true
+
+		return transient
+	})
+
+	// The lines never tried were rejected by a policy, the others keep the
+	// error of their last try.
+	for _, i := range pending {
+		if errs[i] == nil {
+			errs[i] = err
+		}
+	}
+
+	return errs
+}
+
+func (r repository) ReadMany(ctx context.Context, ids []example.Identifier) (lines []*example.Line, err error) {
+	err = r.policy.Do(ctx, func(ctx context.Context) error {
+		lines, err = r.repo.ReadMany(ctx, ids)
+		return err
+	})
+
+	return lines, err
+}
+
+func (r repository) Scan(ctx context.Context) (cursor example.LineCursor, err error) {
+	err = r.policy.Do(ctx, func(ctx context.Context) error {
+		cursor, err = r.repo.Scan(ctx)
+		return err
+	})
+
+	return cursor, err
+}
+
+func (r repository) Find(ctx context.Context, spec example.LineSpecification) (lines []example.Line, err error) {
+	err = r.policy.Do(ctx, func(ctx context.Context) error {
+		lines, err = r.repo.Find(ctx, spec)
+		return err
+	})
+
+	return lines, err
+}
+
+func (r repository) Delete(ctx context.Context, id example.Identifier, at time.Time) (found bool, err error) {
+	err = r.policy.Do(ctx, func(ctx context.Context) error {
+		found, err = r.repo.Delete(ctx, id, at)
+		return err
+	})
+
+	return found, err
+}
+
+func (r repository) Restore(ctx context.Context, id example.Identifier) (found bool, err error) {
+	err = r.policy.Do(ctx, func(ctx context.Context) error {
+		found, err = r.repo.Restore(ctx, id)
+		return err
+	})
+
+	return found, err
+}
+
+func (r repository) Purge(ctx context.Context, before time.Time) (purged []example.Line, err error) {
+	err = r.policy.Do(ctx, func(ctx context.Context) error {
+		purged, err = r.repo.Purge(ctx, before)
+		return err
+	})
+
+	return purged, err
+}
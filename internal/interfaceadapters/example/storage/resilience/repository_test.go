@@ -0,0 +1,299 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+// noWaitRetry tries again right away.
+func noWaitRetry(attempts int) Policy {
+	r := NewRetry(RetryConfig{Attempts: attempts}).(retry)
+	r.sleep = func(context.Context, time.Duration) error { return nil }
+
+	return r
+}
+
+func Test_RepositoryRetries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, time.May, 1, 12, 0, 0, 0, time.UTC)
+	id := example.MockIdentifier("one")
+	line := example.Line{ID: id, Created: now, Data: "first-line"}
+
+	testCases := []struct {
+		name  string
+		repo  func() *example.MockRepository
+		call  func(repo example.LineRepository) (interface{}, error)
+		value interface{}
+	}{
+		{
+			name: "write-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Write", ctx, line).Return(example.ErrTimeout).Once()
+				mr.On("Write", ctx, line).Return(nil).Once()
+
+				return mr
+			},
+			call: func(repo example.LineRepository) (interface{}, error) {
+				return nil, repo.Write(ctx, line)
+			},
+		},
+		{
+			name: "read-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Read", ctx, id).Return((*example.Line)(nil), example.ErrUnavailable).Once()
+				mr.On("Read", ctx, id).Return(&line, nil).Once()
+
+				return mr
+			},
+			call: func(repo example.LineRepository) (interface{}, error) {
+				return repo.Read(ctx, id)
+			},
+			value: &line,
+		},
+		{
+			name: "read-many-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("ReadMany", ctx, []example.Identifier{id}).Return([]*example.Line(nil), example.ErrTimeout).Once()
+				mr.On("ReadMany", ctx, []example.Identifier{id}).Return([]*example.Line{&line}, nil).Once()
+
+				return mr
+			},
+			call: func(repo example.LineRepository) (interface{}, error) {
+				return repo.ReadMany(ctx, []example.Identifier{id})
+			},
+			value: []*example.Line{&line},
+		},
+		{
+			name: "scan-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Scan", ctx).Return((*example.MockCursor)(nil), example.ErrTimeout).Once()
+				mr.On("Scan", ctx).Return(&example.MockCursor{}, nil).Once()
+
+				return mr
+			},
+			call: func(repo example.LineRepository) (interface{}, error) {
+				return repo.Scan(ctx)
+			},
+			value: &example.MockCursor{},
+		},
+		{
+			name: "find-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Find", ctx, example.LineSpecification{}).Return([]example.Line(nil), example.ErrTimeout).Once()
+				mr.On("Find", ctx, example.LineSpecification{}).Return([]example.Line{line}, nil).Once()
+
+				return mr
+			},
+			call: func(repo example.LineRepository) (interface{}, error) {
+				return repo.Find(ctx, example.LineSpecification{})
+			},
+			value: []example.Line{line},
+		},
+		{
+			name: "delete-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Delete", ctx, id, now).Return(false, example.ErrTimeout).Once()
+				mr.On("Delete", ctx, id, now).Return(true, nil).Once()
+
+				return mr
+			},
+			call: func(repo example.LineRepository) (interface{}, error) {
+				return repo.Delete(ctx, id, now)
+			},
+			value: true,
+		},
+		{
+			name: "restore-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Restore", ctx, id).Return(false, example.ErrTimeout).Once()
+				mr.On("Restore", ctx, id).Return(true, nil).Once()
+
+				return mr
+			},
+			call: func(repo example.LineRepository) (interface{}, error) {
+				return repo.Restore(ctx, id)
+			},
+			value: true,
+		},
+		{
+			name: "purge-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("Purge", ctx, now).Return(nil, example.ErrTimeout).Once()
+				mr.On("Purge", ctx, now).Return([]example.Line{{ID: example.MockIdentifier("one")}}, nil).Once()
+
+				return mr
+			},
+			call: func(repo example.LineRepository) (interface{}, error) {
+				return repo.Purge(ctx, now)
+			},
+			value: []example.Line{{ID: example.MockIdentifier("one")}},
+		},
+	}
+
+	for _, c := range testCases {
+		repo := c.repo()
+		call := c.call
+		value := c.value
+
+		t.Run(c.name, func(t *testing.T) {
+			got, err := call(NewRepository(repo, noWaitRetry(2)))
+
+			assert.NoError(t, err)
+			assert.Equal(t, value, got)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_RepositoryWriteMany(t *testing.T) {
+	ctx := context.Background()
+	one := example.Line{ID: example.MockIdentifier("one"), Data: "first-line"}
+	two := example.Line{ID: example.MockIdentifier("two"), Data: "second-line"}
+	three := example.Line{ID: example.MockIdentifier("three"), Data: "third-line"}
+	duplicate := errors.New("duplicate key")
+
+	testCases := []struct {
+		name           string
+		repo           func() *example.MockRepository
+		policies       []Policy
+		expectedErrors []error
+	}{
+		{
+			name: "retries-transient-lines-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("WriteMany", ctx, []example.Line{one, two, three}).Return([]error{nil, example.ErrTimeout, duplicate}).Once()
+				mr.On("WriteMany", ctx, []example.Line{two}).Return([]error{nil}).Once()
+
+				return mr
+			},
+			policies:       []Policy{noWaitRetry(3)},
+			expectedErrors: []error{nil, nil, duplicate},
+		},
+		{
+			name: "stored-before-timeout-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("WriteMany", ctx, []example.Line{one, two, three}).Return([]error{example.ErrTimeout, nil, example.ErrDuplicate}).Once()
+				mr.On("WriteMany", ctx, []example.Line{one}).Return([]error{example.ErrDuplicate}).Once()
+
+				return mr
+			},
+			policies:       []Policy{noWaitRetry(3)},
+			expectedErrors: []error{nil, nil, example.ErrDuplicate},
+		},
+		{
+			name: "exhausted-case",
+			repo: func() *example.MockRepository {
+				mr := &example.MockRepository{}
+				mr.On("WriteMany", ctx, []example.Line{one, two, three}).Return([]error{nil, example.ErrTimeout, nil}).Once()
+				mr.On("WriteMany", ctx, []example.Line{two}).Return([]error{example.ErrUnavailable}).Once()
+
+				return mr
+			},
+			policies:       []Policy{noWaitRetry(2)},
+			expectedErrors: []error{nil, example.ErrUnavailable, nil},
+		},
+		{
+			name: "rejected-case",
+			repo: func() *example.MockRepository {
+				return &example.MockRepository{}
+			},
+			policies:       []Policy{NewBulkhead(BulkheadConfig{MaxConcurrent: 0})},
+			expectedErrors: []error{ErrBulkheadFull, ErrBulkheadFull, ErrBulkheadFull},
+		},
+	}
+
+	for _, c := range testCases {
+		repo := c.repo()
+		policies := c.policies
+		expectedErrors := c.expectedErrors
+
+		t.Run(c.name, func(t *testing.T) {
+			errs := NewRepository(repo, policies...).WriteMany(ctx, []example.Line{one, two, three})
+
+			assert.Len(t, errs, len(expectedErrors))
+			for i, err := range errs {
+				if expectedErrors[i] == nil {
+					assert.NoError(t, err)
+					continue
+				}
+
+				assert.ErrorIs(t, err, expectedErrors[i])
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_RepositoryWriteStoredBeforeTimeout(t *testing.T) {
+	ctx := context.Background()
+	line := example.Line{ID: example.MockIdentifier("one"), Data: "first-line"}
+
+	testCases := []struct {
+		name          string
+		errs          []error
+		expectedError error
+	}{
+		{
+			name:          "stored-before-timeout-case",
+			errs:          []error{example.ErrTimeout, example.ErrDuplicate},
+			expectedError: nil,
+		},
+		{
+			name:          "duplicate-on-first-try-case",
+			errs:          []error{example.ErrDuplicate},
+			expectedError: example.ErrDuplicate,
+		},
+	}
+
+	for _, c := range testCases {
+		errs := c.errs
+		expectedError := c.expectedError
+
+		t.Run(c.name, func(t *testing.T) {
+			repo := &example.MockRepository{}
+			for _, err := range errs {
+				repo.On("Write", ctx, line).Return(err).Once()
+			}
+
+			err := NewRepository(repo, noWaitRetry(3)).Write(ctx, line)
+
+			assert.ErrorIs(t, err, expectedError)
+			if expectedError == nil {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_RepositoryFailsFastWhenOpen(t *testing.T) {
+	ctx := context.Background()
+	line := example.Line{ID: example.MockIdentifier("one"), Data: "first-line"}
+
+	repo := &example.MockRepository{}
+	repo.On("Write", ctx, line).Return(example.ErrUnavailable).Twice()
+
+	guarded := NewRepository(repo, noWaitRetry(3), NewBreaker(BreakerConfig{Failures: 2, Cooldown: time.Minute}))
+
+	err := guarded.Write(ctx, line)
+
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.ErrorIs(t, err, example.ErrUnavailable)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,214 @@
+package resilience
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+const (
+	ErrReadConfig resilienceError = "unable to read resilience config"
+
+	ErrCircuitOpen  Rejection = "circuit breaker open"
+	ErrBulkheadFull Rejection = "bulkhead full"
+
+	ConfigNode string = "apps.example.interface-adapters.storage.resilience"
+
+	defaultBaseDelay time.Duration = 50 * time.Millisecond
+	defaultMaxDelay  time.Duration = time.Second
+	defaultCooldown  time.Duration = 30 * time.Second
+)
+
+type resilienceError string
+
+func (re resilienceError) Error() string {
+	return string(re)
+}
+
+// Rejection is the error of a call a policy refused to make, the storage is
+// then unavailable without having been tried.
+type Rejection string
+
+func (r Rejection) Error() string {
+	return string(r)
+}
+
+func (r Rejection) Unwrap() error {
+	return example.ErrUnavailable
+}
+
+// Transient tells whether err may go away by trying again. Rejections are
+// not transient, trying again would be rejected as well.
+func Transient(err error) bool {
+	var rejection Rejection
+	if err == nil || errors.As(err, &rejection) {
+		return false
+	}
+
+	return errors.Is(err, example.ErrTimeout) || errors.Is(err, example.ErrUnavailable)
+}
+
+// Policy decides how op is called, if at all.
+type Policy interface {
+	Do(ctx context.Context, op func(context.Context) error) error
+}
+
+type chain []Policy
+
+func (c chain) Do(ctx context.Context, op func(context.Context) error) error {
+	if len(c) == 0 {
+		return op(ctx)
+	}
+
+	return c[0].Do(ctx, func(ctx context.Context) error {
+		return c[1:].Do(ctx, op)
+	})
+}
+
+type RetryConfig struct {
+	Attempts  int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+type BreakerConfig struct {
+	Failures int
+	Cooldown time.Duration
+}
+
+type BulkheadConfig struct {
+	MaxConcurrent int
+	Wait          time.Duration
+}
+
+// Config holds the policies of a backend, a policy with a zero Attempts,
+// Failures or MaxConcurrent is off.
+type Config interface {
+	Retry() RetryConfig
+	Breaker() BreakerConfig
+	Bulkhead() BulkheadConfig
+}
+
+type retryConfig struct {
+	Attempts  int    `json:"attempts"`
+	BaseDelay string `json:"base-delay"`
+	MaxDelay  string `json:"max-delay"`
+}
+
+type breakerConfig struct {
+	Failures int    `json:"failures"`
+	Cooldown string `json:"cooldown"`
+}
+
+type bulkheadConfig struct {
+	MaxConcurrent int    `json:"max-concurrent"`
+	Wait          string `json:"wait"`
+}
+
+type config struct {
+	RetryPolicy    retryConfig    `json:"retry"`
+	BreakerPolicy  breakerConfig  `json:"breaker"`
+	BulkheadPolicy bulkheadConfig `json:"bulkhead"`
+
+	retry    RetryConfig
+	breaker  BreakerConfig
+	bulkhead BulkheadConfig
+}
+
+func (c config) Retry() RetryConfig {
+	return c.retry
+}
+
+func (c config) Breaker() BreakerConfig {
+	return c.breaker
+}
+
+func (c config) Bulkhead() BulkheadConfig {
+	return c.bulkhead
+}
+
+type ConfigReader interface {
+	Find(node string) (io.Reader, error)
+}
+
+// ReadConfig reads the policies of backend, the name of its storage node.
+func ReadConfig(cfnReader ConfigReader, backend string) (Config, error) {
+	reader, err := cfnReader.Find(ConfigNode + "." + backend)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	d, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	cnf := config{}
+	if err = json.Unmarshal(d, &cnf); err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	if cnf.RetryPolicy.Attempts < 0 || cnf.BreakerPolicy.Failures < 0 || cnf.BulkheadPolicy.MaxConcurrent < 0 {
+		return nil, fmt.Errorf("negative policy setting: %w", ErrReadConfig)
+	}
+
+	cnf.retry = RetryConfig{Attempts: cnf.RetryPolicy.Attempts}
+	if cnf.retry.BaseDelay, err = parseDuration(cnf.RetryPolicy.BaseDelay, defaultBaseDelay); err != nil {
+		return nil, err
+	}
+
+	if cnf.retry.MaxDelay, err = parseDuration(cnf.RetryPolicy.MaxDelay, defaultMaxDelay); err != nil {
+		return nil, err
+	}
+
+	cnf.breaker = BreakerConfig{Failures: cnf.BreakerPolicy.Failures}
+	if cnf.breaker.Cooldown, err = parseDuration(cnf.BreakerPolicy.Cooldown, defaultCooldown); err != nil {
+		return nil, err
+	}
+
+	cnf.bulkhead = BulkheadConfig{MaxConcurrent: cnf.BulkheadPolicy.MaxConcurrent}
+	if cnf.bulkhead.Wait, err = parseDuration(cnf.BulkheadPolicy.Wait, 0); err != nil {
+		return nil, err
+	}
+
+	return cnf, nil
+}
+
+func parseDuration(s string, fallback time.Duration) (time.Duration, error) {
+	if s == "" {
+		return fallback, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	return d, nil
+}
+
+// Policies builds the policies turned on in cnf, the retries wrapping the
+// breaker wrapping the bulkhead, so every attempt is let through by the
+// breaker and holds a slot of the bulkhead only while it runs.
+func Policies(cnf Config) []Policy {
+	var policies []Policy
+
+	if retry := cnf.Retry(); retry.Attempts > 1 {
+		policies = append(policies, NewRetry(retry))
+	}
+
+	if breaker := cnf.Breaker(); breaker.Failures > 0 {
+		policies = append(policies, NewBreaker(breaker))
+	}
+
+	if bulkhead := cnf.Bulkhead(); bulkhead.MaxConcurrent > 0 {
+		policies = append(policies, NewBulkhead(bulkhead))
+	}
+
+	return policies
+}
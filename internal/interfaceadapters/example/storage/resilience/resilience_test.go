@@ -0,0 +1,183 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+type configReaderMock struct {
+	f func(node string) (io.Reader, error)
+}
+
+func (crm configReaderMock) Find(node string) (io.Reader, error) {
+	return crm.f(node)
+}
+
+func Test_ReadConfig(t *testing.T) {
+	testCases := []struct {
+		testName          string
+		buildConfigReader func(node string) (io.Reader, error)
+		expectedRetry     RetryConfig
+		expectedBreaker   BreakerConfig
+		expectedBulkhead  BulkheadConfig
+		expectedPolicies  int
+		expectedError     error
+	}{
+		{
+			testName: "config-find-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return nil, errors.New("not found")
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "config-unmarshal-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader("{"), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "defaults-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{}`), nil
+			},
+			expectedRetry:   RetryConfig{BaseDelay: defaultBaseDelay, MaxDelay: defaultMaxDelay},
+			expectedBreaker: BreakerConfig{Cooldown: defaultCooldown},
+		},
+		{
+			testName: "success-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				if node != ConfigNode+".mongodb" {
+					return nil, errors.New("not found")
+				}
+
+				return strings.NewReader(`{
+					"retry": {"attempts": 3, "base-delay": "10ms", "max-delay": "100ms"},
+					"breaker": {"failures": 5, "cooldown": "1m"},
+					"bulkhead": {"max-concurrent": 8, "wait": "20ms"}
+				}`), nil
+			},
+			expectedRetry:    RetryConfig{Attempts: 3, BaseDelay: 10 * time.Millisecond, MaxDelay: 100 * time.Millisecond},
+			expectedBreaker:  BreakerConfig{Failures: 5, Cooldown: time.Minute},
+			expectedBulkhead: BulkheadConfig{MaxConcurrent: 8, Wait: 20 * time.Millisecond},
+			expectedPolicies: 3,
+		},
+		{
+			testName: "negative-setting-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"bulkhead": {"max-concurrent": -1}}`), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "delay-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"retry": {"base-delay": "soon"}}`), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "cooldown-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"breaker": {"cooldown": "soon"}}`), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName: "wait-error-case",
+			buildConfigReader: func(node string) (io.Reader, error) {
+				return strings.NewReader(`{"bulkhead": {"wait": "soon"}}`), nil
+			},
+			expectedError: ErrReadConfig,
+		},
+	}
+
+	for _, c := range testCases {
+		name := c.testName
+		reader := configReaderMock{c.buildConfigReader}
+		expectedRetry := c.expectedRetry
+		expectedBreaker := c.expectedBreaker
+		expectedBulkhead := c.expectedBulkhead
+		expectedPolicies := c.expectedPolicies
+		expectedError := c.expectedError
+
+		t.Run(name, func(t *testing.T) {
+			cnf, err := ReadConfig(reader, "mongodb")
+			assert.ErrorIs(t, err, expectedError)
+
+			if expectedError != nil {
+				return
+			}
+
+			assert.Equal(t, expectedRetry, cnf.Retry())
+			assert.Equal(t, expectedBreaker, cnf.Breaker())
+			assert.Equal(t, expectedBulkhead, cnf.Bulkhead())
+			assert.Len(t, Policies(cnf), expectedPolicies)
+		})
+	}
+}
+
+func Test_Transient(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil-case"},
+		{name: "timeout-case", err: fmt.Errorf("%s: %w", "i/o timeout", example.ErrTimeout), expected: true},
+		{name: "unavailable-case", err: fmt.Errorf("%s: %w", "connection reset", example.ErrUnavailable), expected: true},
+		{name: "rejection-case", err: ErrCircuitOpen},
+		{name: "other-case", err: errors.New("duplicate key")},
+	}
+
+	for _, c := range testCases {
+		err := c.err
+		expected := c.expected
+
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, expected, Transient(err))
+		})
+	}
+}
+
+func Test_RejectionIsUnavailable(t *testing.T) {
+	assert.ErrorIs(t, ErrCircuitOpen, example.ErrUnavailable)
+	assert.ErrorIs(t, ErrBulkheadFull, example.ErrUnavailable)
+}
+
+// recordingPolicy appends its name to calls around op.
+type recordingPolicy struct {
+	name  string
+	calls *[]string
+}
+
+func (rp recordingPolicy) Do(ctx context.Context, op func(context.Context) error) error {
+	*rp.calls = append(*rp.calls, rp.name)
+	err := op(ctx)
+	*rp.calls = append(*rp.calls, "/"+rp.name)
+
+	return err
+}
+
+func Test_ChainOrder(t *testing.T) {
+	var calls []string
+	policy := chain{recordingPolicy{"outer", &calls}, recordingPolicy{"inner", &calls}}
+
+	err := policy.Do(context.Background(), func(context.Context) error {
+		calls = append(calls, "op")
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"outer", "inner", "op", "/inner", "/outer"}, calls)
+}
@@ -0,0 +1,83 @@
+package resilience
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+type retry struct {
+	attempts  int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	sleep     func(context.Context, time.Duration) error
+	jitter    func(time.Duration) time.Duration
+}
+
+// NewRetry tries op up to Attempts times, at least once, while it fails with
+// a transient error, waiting between the attempts an exponential backoff from
+// BaseDelay up to MaxDelay with full jitter.
+func NewRetry(cnf RetryConfig) Policy {
+	attempts := cnf.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	return retry{
+		attempts:  attempts,
+		baseDelay: cnf.BaseDelay,
+		maxDelay:  cnf.MaxDelay,
+		sleep:     sleep,
+		jitter:    fullJitter,
+	}
+}
+
+func (r retry) Do(ctx context.Context, op func(context.Context) error) error {
+	var err error
+	for attempt := 0; attempt < r.attempts; attempt++ {
+		if attempt > 0 {
+			if r.sleep(ctx, r.jitter(r.backoff(attempt))) != nil {
+				return err
+			}
+		}
+
+		if err = op(ctx); !Transient(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (r retry) backoff(attempt int) time.Duration {
+	delay := r.baseDelay
+	for i := 1; i < attempt && delay < r.maxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > r.maxDelay {
+		return r.maxDelay
+	}
+
+	return delay
+}
+
+func fullJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
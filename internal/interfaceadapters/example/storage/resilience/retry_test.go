@@ -0,0 +1,129 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"clean-arquitecture-template/internal/domain/example"
+)
+
+func Test_RetryDo(t *testing.T) {
+	someErr := errors.New("duplicate key")
+
+	testCases := []struct {
+		name           string
+		errs           []error
+		sleepErr       error
+		expectedCalls  int
+		expectedSleeps []time.Duration
+		expectedError  error
+	}{
+		{
+			name:          "success-case",
+			errs:          []error{nil},
+			expectedCalls: 1,
+		},
+		{
+			name:           "transient-then-success-case",
+			errs:           []error{example.ErrTimeout, example.ErrUnavailable, nil},
+			expectedCalls:  3,
+			expectedSleeps: []time.Duration{10 * time.Millisecond, 20 * time.Millisecond},
+		},
+		{
+			name:           "exhausted-case",
+			errs:           []error{example.ErrTimeout, example.ErrTimeout, example.ErrTimeout, example.ErrUnavailable},
+			expectedCalls:  4,
+			expectedSleeps: []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond},
+			expectedError:  example.ErrUnavailable,
+		},
+		{
+			name:          "permanent-error-case",
+			errs:          []error{someErr},
+			expectedCalls: 1,
+			expectedError: someErr,
+		},
+		{
+			name:          "rejected-case",
+			errs:          []error{ErrCircuitOpen},
+			expectedCalls: 1,
+			expectedError: ErrCircuitOpen,
+		},
+		{
+			name:           "context-done-case",
+			errs:           []error{example.ErrTimeout},
+			sleepErr:       context.Canceled,
+			expectedCalls:  1,
+			expectedSleeps: []time.Duration{10 * time.Millisecond},
+			expectedError:  example.ErrTimeout,
+		},
+	}
+
+	for _, c := range testCases {
+		errs := c.errs
+		sleepErr := c.sleepErr
+		expectedCalls := c.expectedCalls
+		expectedSleeps := c.expectedSleeps
+		expectedError := c.expectedError
+
+		t.Run(c.name, func(t *testing.T) {
+			var sleeps []time.Duration
+
+			r := NewRetry(RetryConfig{Attempts: 4, BaseDelay: 10 * time.Millisecond, MaxDelay: 25 * time.Millisecond}).(retry)
+			r.jitter = func(d time.Duration) time.Duration { return d }
+			r.sleep = func(ctx context.Context, d time.Duration) error {
+				sleeps = append(sleeps, d)
+				return sleepErr
+			}
+
+			calls := 0
+			err := r.Do(context.Background(), func(context.Context) error {
+				err := errs[calls]
+				calls++
+
+				return err
+			})
+
+			assert.ErrorIs(t, err, expectedError)
+			if expectedError == nil {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, expectedCalls, calls)
+			assert.Equal(t, expectedSleeps, sleeps)
+		})
+	}
+}
+
+func Test_RetryZeroAttempts(t *testing.T) {
+	someErr := errors.New("some-error")
+
+	for _, attempts := range []int{0, -1} {
+		attempts := attempts
+
+		t.Run(fmt.Sprintf("attempts-%d-case", attempts), func(t *testing.T) {
+			calls := 0
+			err := NewRetry(RetryConfig{Attempts: attempts}).Do(context.Background(), func(context.Context) error {
+				calls++
+
+				return someErr
+			})
+
+			assert.ErrorIs(t, err, someErr)
+			assert.Equal(t, 1, calls)
+		})
+	}
+}
+
+func Test_FullJitter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), fullJitter(0))
+
+	for i := 0; i < 100; i++ {
+		d := fullJitter(time.Millisecond)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.LessOrEqual(t, d, time.Millisecond)
+	}
+}
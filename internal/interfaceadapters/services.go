@@ -12,9 +12,9 @@ type MemExampleRepoService struct {
 	memRepo example.LineRepository
 }
 
-func NewMemRepoService(ctx context.Context) MemExampleRepoService {
+func NewMemRepoService(ctx context.Context, cnf memory.Config) MemExampleRepoService {
 	return MemExampleRepoService{
-		memRepo: memory.NewExampleRepo(ctx),
+		memRepo: memory.New(ctx, cnf),
 	}
 }
 
@@ -22,8 +22,13 @@ type MongoExampleRepoService struct {
 	mongoRepo example.LineRepository
 }
 
-func NewMongoRepoService(ctx context.Context, cnf mongodb.Config) MongoExampleRepoService {
-	return MongoExampleRepoService{
-		mongoRepo: mongodb.NewExampleRepo(ctx, cnf),
+func NewMongoRepoService(ctx context.Context, cnf mongodb.Config) (MongoExampleRepoService, error) {
+	repo, err := mongodb.NewExampleRepo(ctx, cnf)
+	if err != nil {
+		return MongoExampleRepoService{}, err
 	}
+
+	return MongoExampleRepoService{
+		mongoRepo: repo,
+	}, nil
 }
@@ -0,0 +1,152 @@
+package interfaceadapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"clean-arquitecture-template/internal/domain/example"
+	"clean-arquitecture-template/internal/interfaceadapters/example/storage/memory"
+	"clean-arquitecture-template/internal/interfaceadapters/example/storage/mongodb"
+)
+
+const (
+	StorageConfigNode string = "apps.example.interface-adapters.storage"
+
+	MongoBackend  string = "mongodb"
+	MemoryBackend string = "memory"
+
+	ErrReadConfig err = "unable to read config"
+)
+
+type err string
+
+func (e err) Error() string {
+	return string(e)
+}
+
+type ConfigReader interface {
+	Find(node string) (io.Reader, error)
+}
+
+// StorageConfig tells which backend keeps the lines, mongodb unless set.
+type StorageConfig interface {
+	Backend() string
+}
+
+type storageConfig struct {
+	StorageBackend string `json:"backend"`
+}
+
+func (c storageConfig) Backend() string {
+	if c.StorageBackend == "" {
+		return MongoBackend
+	}
+
+	return c.StorageBackend
+}
+
+func ReadStorageConfig(cfnReader ConfigReader) (StorageConfig, error) {
+	reader, err := cfnReader.Find(StorageConfigNode)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	d, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	cnf := storageConfig{}
+	if err = json.Unmarshal(d, &cnf); err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrReadConfig)
+	}
+
+	if backend := cnf.Backend(); backend != MongoBackend && backend != MemoryBackend {
+		return nil, fmt.Errorf("backend %q: %w", backend, ErrReadConfig)
+	}
+
+	return cnf, nil
+}
+
+type HealthChecker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// Storage is the backend chosen in config with everything the binaries take
+// from it. IDs is the identity provider native to the backend, and
+// ConfigNode the config node of the backend, which ApplyConfig takes on a
+// reload.
+type Storage struct {
+	Backend string
+	Lines   interface {
+		example.LineRepository
+		example.Searcher
+		HealthChecker
+	}
+	Audit      example.AuditLog
+	IDs        example.IdentityProvider
+	ConfigNode string
+	apply      func(io.Reader) error
+	close      func(context.Context) error
+}
+
+// NewStorage builds the backend named in the storage config, reading its
+// own config node. Close releases it.
+func NewStorage(ctx context.Context, cfnReader ConfigReader) (Storage, error) {
+	cnf, err := ReadStorageConfig(cfnReader)
+	if err != nil {
+		return Storage{}, err
+	}
+
+	if cnf.Backend() == MemoryBackend {
+		memConf, err := memory.ReadConfig(cfnReader)
+		if err != nil {
+			return Storage{}, err
+		}
+
+		repo := memory.New(ctx, memConf)
+
+		return Storage{
+			Backend:    MemoryBackend,
+			Lines:      repo,
+			Audit:      memory.NewAuditLog(),
+			IDs:        memory.NewIdentityProvider(),
+			ConfigNode: memory.ConfigNode,
+			apply:      repo.ApplyConfig,
+			close:      func(context.Context) error { return nil },
+		}, nil
+	}
+
+	mongoConf, err := mongodb.ReadConfig(cfnReader)
+	if err != nil {
+		return Storage{}, err
+	}
+
+	repo, err := mongodb.NewExampleRepo(ctx, mongoConf)
+	if err != nil {
+		return Storage{}, err
+	}
+
+	return Storage{
+		Backend:    MongoBackend,
+		Lines:      repo,
+		Audit:      repo.AuditLog(),
+		IDs:        mongodb.NewIdentityProvider(),
+		ConfigNode: mongodb.ConfigNode,
+		apply:      repo.ApplyConfig,
+		close:      repo.Close,
+	}, nil
+}
+
+// ApplyConfig validates the backend config read from r and applies what can
+// change while running, its timeout.
+func (s Storage) ApplyConfig(r io.Reader) error {
+	return s.apply(r)
+}
+
+func (s Storage) Close(ctx context.Context) error {
+	return s.close(ctx)
+}
@@ -0,0 +1,120 @@
+package interfaceadapters
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"clean-arquitecture-template/internal/domain/example"
+	"clean-arquitecture-template/internal/interfaceadapters/example/storage/memory"
+)
+
+type configReaderMock struct {
+	nodes map[string]string
+}
+
+func (crm configReaderMock) Find(node string) (io.Reader, error) {
+	data, found := crm.nodes[node]
+	if !found {
+		return nil, errors.New("not found")
+	}
+
+	return strings.NewReader(data), nil
+}
+
+func Test_ReadStorageConfig(t *testing.T) {
+	testCases := []struct {
+		testName        string
+		nodes           map[string]string
+		expectedBackend string
+		expectedError   error
+	}{
+		{
+			testName:      "config-find-error-case",
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName:      "config-unmarshal-error-case",
+			nodes:         map[string]string{StorageConfigNode: "{"},
+			expectedError: ErrReadConfig,
+		},
+		{
+			testName:        "default-case",
+			nodes:           map[string]string{StorageConfigNode: `{"mongodb": {"database": "example"}}`},
+			expectedBackend: MongoBackend,
+		},
+		{
+			testName:        "memory-case",
+			nodes:           map[string]string{StorageConfigNode: `{"backend": "memory"}`},
+			expectedBackend: MemoryBackend,
+		},
+		{
+			testName:      "unknown-backend-error-case",
+			nodes:         map[string]string{StorageConfigNode: `{"backend": "redis"}`},
+			expectedError: ErrReadConfig,
+		},
+	}
+
+	for _, c := range testCases {
+		name := c.testName
+		reader := configReaderMock{c.nodes}
+		expectedBackend := c.expectedBackend
+		expectedError := c.expectedError
+
+		t.Run(name, func(t *testing.T) {
+			cnf, err := ReadStorageConfig(reader)
+			assert.ErrorIs(t, err, expectedError)
+
+			if expectedError != nil {
+				return
+			}
+
+			assert.Equal(t, expectedBackend, cnf.Backend())
+		})
+	}
+}
+
+func Test_NewStorageMemory(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reader := configReaderMock{map[string]string{
+		StorageConfigNode: `{"backend": "memory"}`,
+		memory.ConfigNode: `{"implementation": "sharded", "shards": 2}`,
+	}}
+
+	storage, err := NewStorage(ctx, reader)
+	require.NoError(t, err)
+
+	assert.Equal(t, MemoryBackend, storage.Backend)
+	assert.Equal(t, memory.ConfigNode, storage.ConfigNode)
+	assert.IsType(t, &memory.ShardedStore{}, storage.Lines)
+	assert.NoError(t, storage.Lines.Check(ctx))
+	assert.NoError(t, storage.ApplyConfig(strings.NewReader(`{"implementation": "sharded", "timeout": "2s"}`)))
+	assert.ErrorIs(t, storage.ApplyConfig(strings.NewReader(`{"timeout": "two seconds"}`)), memory.ErrReadConfig)
+
+	line := example.Line{ID: storage.IDs.NewID(), Data: "first line"}
+	require.NoError(t, storage.Lines.Write(ctx, line))
+	require.NoError(t, storage.Audit.Append(ctx, []example.AuditRecord{{LineID: line.ID, Action: example.AuditCreate}}))
+
+	history, err := storage.Audit.History(ctx, line.ID)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+
+	assert.NoError(t, storage.Close(ctx))
+}
+
+func Test_NewStorageConfigError(t *testing.T) {
+	reader := configReaderMock{map[string]string{
+		StorageConfigNode: `{"backend": "memory"}`,
+		memory.ConfigNode: `{"implementation": "lockless"}`,
+	}}
+
+	_, err := NewStorage(context.Background(), reader)
+	assert.ErrorIs(t, err, memory.ErrReadConfig)
+}